@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,13 +18,26 @@ import (
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 
 	"github.com/Unic-X/webhook-delivery/internal/api"
+	"github.com/Unic-X/webhook-delivery/internal/archive"
+	"github.com/Unic-X/webhook-delivery/internal/blob"
 	"github.com/Unic-X/webhook-delivery/internal/config"
+	"github.com/Unic-X/webhook-delivery/internal/envelope"
+	"github.com/Unic-X/webhook-delivery/internal/grpcapi"
+	webhookv1 "github.com/Unic-X/webhook-delivery/internal/pb/webhook/v1"
 	"github.com/Unic-X/webhook-delivery/internal/repository"
 	"github.com/Unic-X/webhook-delivery/internal/service"
+	"github.com/Unic-X/webhook-delivery/internal/signing"
+	"github.com/Unic-X/webhook-delivery/internal/tracing"
 )
 
+// grpcMessageOverhead is the room left for request fields other than the payload
+// when sizing the gRPC receive limit
+const grpcMessageOverhead = 64 << 10
+
 func main() {
 	// Initialize logger
 	logger := logrus.New()
@@ -36,6 +50,17 @@ func main() {
 		logger.WithError(err).Fatal("Failed to load configuration")
 	}
 
+	// Set up tracing
+	shutdownTracing, err := tracing.Init(context.Background(), cfg, "webhook-api")
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up tracing")
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.WithError(err).Error("Failed to flush traces")
+		}
+	}()
+
 	// Set up database connection
 	db, err := setupDatabase(cfg, logger)
 	if err != nil {
@@ -64,17 +89,49 @@ func main() {
 		logger.WithError(err).Fatal("Failed to connect to Redis")
 	}
 
-	// Initialize repository
-	repo := repository.NewPostgresRepository(db)
+	// Initialize repository, encrypting secrets and payloads at rest when master keys are configured
+	keyring, err := envelope.LoadKeyring(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load encryption keys")
+	}
+	repo := repository.New(db, keyring)
 
 	// Initialize service
 	svc := service.NewWebhookService(repo, redisClient, cfg, logger)
 
+	// Set up the blob store large payloads are offloaded to
+	blobStore, err := blob.Open(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up blob store")
+	}
+	svc.SetBlobStore(blobStore)
+
+	// Set up the sink deliveries are archived to before retention removes them
+	archiveSink, err := archive.Open(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up archive sink")
+	}
+	svc.SetArchiveSink(archiveSink)
+
+	// Load the Ed25519 keys of the ed25519 signature scheme
+	signingKeys, err := signing.LoadKeyring(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load signing keys")
+	}
+	svc.SetSigningKeys(signingKeys)
+
 	// Initialize HTTP handler
-	handler := api.NewHandler(svc, logger)
+	handler := api.NewHandler(svc, cfg, logger)
+	handler.SetIdempotencyStore(redisClient)
 
 	// Set up Gin router
 	router := gin.Default()
+	// Client addresses end up in the audit log, so X-Forwarded-For is only taken
+	// from the configured proxies
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.WithError(err).Fatal("Invalid TRUSTED_PROXIES")
+	}
+	router.Use(tracing.Middleware())
 
 	// Set up routes
 	handler.SetupRoutes(router)
@@ -84,6 +141,7 @@ func main() {
 		Addr:    ":" + cfg.Port,
 		Handler: router,
 	}
+	server.RegisterOnShutdown(handler.CloseStreams)
 
 	// Start the server in a goroutine
 	go func() {
@@ -93,6 +151,32 @@ func main() {
 		}
 	}()
 
+	// Set up gRPC server
+	grpcOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor(), grpcapi.AuditUnaryInterceptor(cfg.AuditActorHeader)),
+		grpc.ChainStreamInterceptor(tracing.StreamServerInterceptor()),
+	}
+	if cfg.MaxIngestBytes > 0 {
+		// Leave room for the fields around the payload, the service enforces the exact limit
+		grpcOpts = append(grpcOpts, grpc.MaxRecvMsgSize(cfg.MaxIngestBytes+grpcMessageOverhead))
+	}
+	grpcServer := grpc.NewServer(grpcOpts...)
+	webhookv1.RegisterWebhookServiceServer(grpcServer, grpcapi.NewServer(svc, logger))
+	reflection.Register(grpcServer)
+
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to listen for gRPC")
+	}
+
+	// Start the gRPC server in a goroutine
+	go func() {
+		logger.Info("Starting gRPC server on port ", cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.WithError(err).Fatal("Failed to start gRPC server")
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shut down the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -104,8 +188,23 @@ func main() {
 	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Requests still running at the deadline are cut off, but the gRPC server and
+	// the deferred cleanup still have to run
 	if err := server.Shutdown(ctx); err != nil {
-		logger.WithError(err).Fatal("Server forced to shutdown")
+		logger.WithError(err).Error("Server forced to shutdown")
+	}
+
+	// Open WatchDeliveries streams never finish on their own, so stop forcefully
+	// once the deadline passes
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
 	}
 
 	logger.Info("Server exiting")
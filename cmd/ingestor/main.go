@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	kafkaconfig "github.com/Unic-X/webhook-delivery/config"
+	"github.com/Unic-X/webhook-delivery/internal/blob"
+	"github.com/Unic-X/webhook-delivery/internal/config"
+	"github.com/Unic-X/webhook-delivery/internal/envelope"
+	"github.com/Unic-X/webhook-delivery/internal/ingest"
+	"github.com/Unic-X/webhook-delivery/internal/repository"
+	"github.com/Unic-X/webhook-delivery/internal/service"
+	"github.com/Unic-X/webhook-delivery/internal/tracing"
+)
+
+func main() {
+	// Initialize logger
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.SetOutput(os.Stdout)
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load configuration")
+	}
+
+	if cfg.KafkaBrokers == "" || len(cfg.KafkaIngestTopics) == 0 {
+		logger.Fatal("KAFKA_BROKERS and KAFKA_INGEST_TOPICS must be set")
+	}
+
+	// Set up tracing
+	shutdownTracing, err := tracing.Init(context.Background(), cfg, "webhook-ingestor")
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up tracing")
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.WithError(err).Error("Failed to flush traces")
+		}
+	}()
+
+	// Set up database connection
+	db, err := setupDatabase(cfg, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up database")
+	}
+	defer db.Close()
+
+	// Set up Redis connection
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+	defer redisClient.Close()
+
+	// Ping Redis to check connection
+	pingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := redisClient.Ping(pingCtx).Result(); err != nil {
+		logger.WithError(err).Fatal("Failed to connect to Redis")
+	}
+
+	// Initialize repository, encrypting secrets and payloads at rest when master keys are configured
+	keyring, err := envelope.LoadKeyring(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load encryption keys")
+	}
+	repo := repository.New(db, keyring)
+
+	// Initialize service
+	svc := service.NewWebhookService(repo, redisClient, cfg, logger)
+
+	// Set up the blob store large payloads are offloaded to
+	blobStore, err := blob.Open(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up blob store")
+	}
+	svc.SetBlobStore(blobStore)
+
+	// Set up the Kafka consumer
+	consumer, err := kafkaconfig.KafkaConsumerInit(cfg.KafkaBrokers, cfg.KafkaConsumerGroup)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up Kafka consumer")
+	}
+	defer consumer.Close()
+
+	// Stop consuming on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	ingestor := ingest.NewIngestor(consumer, svc, cfg, logger)
+	if err := ingestor.Run(ctx); err != nil {
+		logger.WithError(err).Error("Ingestor stopped with error")
+		return
+	}
+
+	logger.Info("Ingestor shut down")
+}
+
+// setupDatabase sets up the database connection
+func setupDatabase(cfg *config.Config, logger *logrus.Logger) (*sqlx.DB, error) {
+	db, err := sqlx.Connect("postgres", cfg.PostgresDSN)
+	if err != nil {
+		return nil, err
+	}
+
+	// Configure connection pool
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	// Test connection
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Connected to database")
+	return db, nil
+}
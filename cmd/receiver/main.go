@@ -0,0 +1,93 @@
+// Command receiver is a webhook consumer for local development and end-to-end tests.
+// It verifies signatures, logs what it receives and can inject failures to exercise
+// the worker's retry behaviour.
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	var addr string
+	var jsonLogs bool
+	opts := Options{}
+
+	flag.StringVar(&addr, "addr", ":9999", "address to listen on")
+	flag.StringVar(&opts.Secret, "secret", os.Getenv("RECEIVER_SECRET"), "verify X-Hub-Signature-256 or Standard Webhooks signatures with this secret and reject mismatches with 401 (default $RECEIVER_SECRET)")
+	flag.DurationVar(&opts.Latency, "latency", 0, "delay before every response")
+	flag.DurationVar(&opts.Jitter, "jitter", 0, "random extra delay up to this duration")
+	flag.IntVar(&opts.DropFirst, "drop-first", 0, "close the connection without a response for the first N requests")
+	flag.IntVar(&opts.RateLimitFirst, "rate-limit-first", 0, "respond 429 to the next N requests")
+	flag.DurationVar(&opts.RetryAfter, "retry-after", 30*time.Second, "Retry-After sent with 429 responses")
+	flag.IntVar(&opts.FailFirst, "fail-first", 0, "respond with -fail-status to the next N requests")
+	flag.IntVar(&opts.FailStatus, "fail-status", http.StatusInternalServerError, "status code for injected failures")
+	flag.Float64Var(&opts.FailRate, "fail-rate", 0, "probability between 0 and 1 of failing any later request")
+	flag.BoolVar(&opts.LogBody, "log-body", true, "log request bodies")
+	flag.BoolVar(&jsonLogs, "json", false, "log as JSON")
+	flag.Parse()
+
+	// Initialize logger
+	logger := logrus.New()
+	logger.SetOutput(os.Stdout)
+	if jsonLogs {
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	}
+
+	if opts.FailRate < 0 || opts.FailRate > 1 {
+		logger.Fatal("-fail-rate must be between 0 and 1")
+	}
+	if opts.FailStatus < 100 || opts.FailStatus > 599 {
+		logger.Fatal("-fail-status must be an HTTP status code")
+	}
+
+	receiver := NewReceiver(opts, logger)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_stats", receiver.ServeStats)
+	mux.Handle("/", receiver)
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	// Start the server in a goroutine
+	go func() {
+		logger.WithFields(logrus.Fields{
+			"addr":             addr,
+			"verify_signature": opts.Secret != "",
+			"drop_first":       opts.DropFirst,
+			"rate_limit_first": opts.RateLimitFirst,
+			"fail_first":       opts.FailFirst,
+			"fail_rate":        opts.FailRate,
+			"latency":          opts.Latency,
+		}).Info("Receiver listening")
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.WithError(err).Fatal("Failed to start receiver")
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shut down the server
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		logger.WithError(err).Error("Receiver forced to shutdown")
+	}
+
+	logger.WithField("stats", receiver.Stats()).Info("Receiver stopped")
+}
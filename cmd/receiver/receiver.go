@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Unic-X/webhook-delivery/pkg/webhook"
+)
+
+// Options configures the receiver and the failures it injects. The counted failure
+// phases run in order: the first DropFirst requests are dropped, the next
+// RateLimitFirst get a 429 and the next FailFirst get FailStatus. Later requests
+// fail at random with probability FailRate.
+type Options struct {
+	Secret         string
+	Latency        time.Duration
+	Jitter         time.Duration
+	DropFirst      int
+	RateLimitFirst int
+	RetryAfter     time.Duration
+	FailFirst      int
+	FailStatus     int
+	FailRate       float64
+	LogBody        bool
+}
+
+// Stats counts the requests the receiver has seen
+type Stats struct {
+	Received          int `json:"received"`
+	Accepted          int `json:"accepted"`
+	InvalidSignatures int `json:"invalid_signatures"`
+	Dropped           int `json:"dropped"`
+	RateLimited       int `json:"rate_limited"`
+	Failed            int `json:"failed"`
+}
+
+// Receiver is an HTTP handler that acts as a webhook consumer
+type Receiver struct {
+	opts     Options
+	logger   *logrus.Logger
+	verifier *webhook.Verifier
+
+	mu       sync.Mutex
+	stats    Stats
+	verified int
+	rand     *rand.Rand
+}
+
+// NewReceiver creates a new Receiver
+func NewReceiver(opts Options, logger *logrus.Logger) *Receiver {
+	r := &Receiver{
+		opts:   opts,
+		logger: logger,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	if opts.Secret != "" {
+		r.verifier, _ = webhook.NewVerifier(webhook.Options{Secrets: []string{opts.Secret}})
+	}
+	return r
+}
+
+// outcome is what the receiver does with a request
+type outcome int
+
+const (
+	outcomeAccept outcome = iota
+	outcomeDrop
+	outcomeRateLimit
+	outcomeFail
+)
+
+// next decides the outcome of a request that passed signature verification, so
+// that rejected requests do not use up the failure phases
+func (r *Receiver) next() outcome {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.verified++
+	n := r.verified
+
+	switch {
+	case n <= r.opts.DropFirst:
+		return outcomeDrop
+	case n <= r.opts.DropFirst+r.opts.RateLimitFirst:
+		return outcomeRateLimit
+	case n <= r.opts.DropFirst+r.opts.RateLimitFirst+r.opts.FailFirst:
+		return outcomeFail
+	case r.opts.FailRate > 0 && r.rand.Float64() < r.opts.FailRate:
+		return outcomeFail
+	default:
+		return outcomeAccept
+	}
+}
+
+// latency returns the configured latency plus a random jitter
+func (r *Receiver) latency() time.Duration {
+	if r.opts.Jitter <= 0 {
+		return r.opts.Latency
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.opts.Latency + time.Duration(r.rand.Int63n(int64(r.opts.Jitter)))
+}
+
+func (r *Receiver) count(field *int) {
+	r.mu.Lock()
+	*field++
+	r.mu.Unlock()
+}
+
+// Stats returns a snapshot of the request counts
+func (r *Receiver) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// ServeHTTP receives a webhook on any path
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		r.logger.WithError(err).Warn("Failed to read request body")
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	r.stats.Received++
+	n := r.stats.Received
+	r.mu.Unlock()
+
+	fields := logrus.Fields{
+		"request":     n,
+		"path":        req.URL.Path,
+		"event_type":  req.Header.Get(webhook.EventHeader),
+		"delivery_id": req.Header.Get(webhook.IDHeader),
+		"bytes":       len(body),
+	}
+	if r.opts.LogBody {
+		fields["body"] = string(body)
+	}
+	logger := r.logger.WithFields(fields)
+
+	if delay := r.latency(); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			logger.Warn("Sender gave up while waiting for injected latency")
+			return
+		}
+	}
+
+	if r.verifier != nil {
+		if _, err := r.verifier.Verify(req.Header, body); err != nil {
+			r.count(&r.stats.InvalidSignatures)
+			logger.WithError(err).Warn("Rejected webhook with invalid signature")
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	switch r.next() {
+	case outcomeDrop:
+		r.count(&r.stats.Dropped)
+		logger.Info("Dropping connection (injected)")
+		drop(w)
+	case outcomeRateLimit:
+		r.count(&r.stats.RateLimited)
+		retryAfter := strconv.Itoa(int(r.opts.RetryAfter.Seconds()))
+		logger.WithField("retry_after", retryAfter).Info("Responding 429 (injected)")
+		w.Header().Set("Retry-After", retryAfter)
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	case outcomeFail:
+		r.count(&r.stats.Failed)
+		logger.WithField("status", r.opts.FailStatus).Info("Responding with failure (injected)")
+		http.Error(w, "injected failure", r.opts.FailStatus)
+	default:
+		r.count(&r.stats.Accepted)
+		logger.Info("Webhook received")
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ServeStats reports the request counts as JSON
+func (r *Receiver) ServeStats(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(r.Stats())
+}
+
+// drop closes the connection without writing a response
+func drop(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		// HTTP/2 connections cannot be hijacked, abort the stream instead
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	conn.Close()
+}
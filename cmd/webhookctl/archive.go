@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+func (a *app) archive(args []string) error {
+	sub, args, err := subcommand(args, "restore")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "restore":
+		return a.restoreArchive(args)
+	default:
+		return fmt.Errorf("unknown archive subcommand %q", sub)
+	}
+}
+
+// restoreArchive reads back the deliveries archived for a day. With --file the
+// records are written there as JSON lines, one delivery with its attempts per line.
+func (a *app) restoreArchive(args []string) error {
+	var subscription, file string
+	fs := a.flagSet("archive restore DAY [--subscription ID] [--file PATH]")
+	fs.StringVar(&subscription, "subscription", "", "only restore deliveries of this subscription")
+	fs.StringVar(&file, "file", "", "write the archived deliveries to this file as JSON lines")
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("expected the day to restore, YYYY-MM-DD")
+	}
+	day, err := time.Parse("2006-01-02", positional[0])
+	if err != nil {
+		return errors.New("the day must be in YYYY-MM-DD format")
+	}
+
+	path := "/webhooks/archive/" + day.Format("2006-01-02")
+	if subscription != "" {
+		id, err := uuid.Parse(subscription)
+		if err != nil {
+			return errors.New("--subscription must be a subscription ID")
+		}
+		path += "?" + url.Values{"subscription_id": {id.String()}}.Encode()
+	}
+
+	body, err := a.client.stream(a.ctx, path, "application/x-ndjson")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	var out *os.File
+	if file != "" {
+		if out, err = os.Create(file); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+
+	var rows [][]string
+	count := 0
+	dec := json.NewDecoder(body)
+	for {
+		var record models.ArchivedDelivery
+		if err := dec.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("malformed archived delivery: %w", err)
+		}
+		count++
+
+		switch {
+		case out != nil:
+			if err := json.NewEncoder(out).Encode(record); err != nil {
+				return err
+			}
+		case a.printer.JSON():
+			encoded, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			a.printer.Println(string(encoded))
+		default:
+			d := record.Delivery
+			rows = append(rows, []string{
+				d.ID.String(),
+				d.SubscriptionID.String(),
+				formatString(d.EventType),
+				d.Status,
+				strconv.Itoa(len(record.Attempts)),
+				formatTime(d.CreatedAt),
+			})
+		}
+	}
+
+	if out != nil {
+		if err := out.Close(); err != nil {
+			return err
+		}
+		a.printer.Println(fmt.Sprintf("Restored %d deliveries to %s", count, file))
+		return nil
+	}
+	if a.printer.JSON() {
+		return nil
+	}
+	return a.printer.Table([]string{"ID", "SUBSCRIPTION", "EVENT TYPE", "STATUS", "ATTEMPTS", "CREATED"}, rows)
+}
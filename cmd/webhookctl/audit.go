@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+func (a *app) audit(args []string) error {
+	sub, args, err := subcommand(args, "list")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list", "ls":
+		return a.listAuditEvents(args)
+	default:
+		return fmt.Errorf("unknown audit subcommand %q", sub)
+	}
+}
+
+// listAuditEvents lists the audit log, newest first
+func (a *app) listAuditEvents(args []string) error {
+	var action, resourceType, resource, actor, requestID, since, until string
+	var limit int
+	fs := a.flagSet("audit list [--action ACTION] [--resource-type TYPE] [--resource ID] [--actor ACTOR] [--request-id ID] [--since TIME] [--until TIME] [--limit N]")
+	fs.StringVar(&action, "action", "", "only list events of this action, e.g. subscription.updated")
+	fs.StringVar(&resourceType, "resource-type", "", "only list events of this resource type: subscription, event_type or delivery")
+	fs.StringVar(&resource, "resource", "", "only list events of this resource ID or event type name")
+	fs.StringVar(&actor, "actor", "", "only list events of this actor")
+	fs.StringVar(&requestID, "request-id", "", "only list events of this request")
+	fs.StringVar(&since, "since", "", "only list events after this RFC3339 time or duration ago, e.g. 24h")
+	fs.StringVar(&until, "until", "", "only list events before this RFC3339 time or duration ago")
+	fs.IntVar(&limit, "limit", 100, "maximum number of events (at most 1000)")
+	if _, err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	query := url.Values{"limit": {strconv.Itoa(limit)}}
+	for key, value := range map[string]string{
+		"action":        action,
+		"resource_type": resourceType,
+		"resource_id":   resource,
+		"actor":         actor,
+		"request_id":    requestID,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	for key, value := range map[string]string{"since": since, "until": until} {
+		t, err := parseTimeFlag(key, value)
+		if err != nil {
+			return err
+		}
+		if t != nil {
+			query.Set(key, t.Format(time.RFC3339))
+		}
+	}
+
+	var events []models.AuditEvent
+	if err := a.client.do(a.ctx, http.MethodGet, "/audit?"+query.Encode(), nil, nil, &events); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(events)
+	}
+
+	rows := make([][]string, 0, len(events))
+	for _, e := range events {
+		rows = append(rows, []string{
+			formatTime(e.CreatedAt),
+			e.Action,
+			formatString(&e.ResourceID),
+			formatString(&e.Actor),
+			formatString(&e.SourceIP),
+			formatString(&e.RequestID),
+		})
+	}
+	return a.printer.Table([]string{"TIME", "ACTION", "RESOURCE", "ACTOR", "SOURCE IP", "REQUEST ID"}, rows)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client calls the webhook service REST API
+type Client struct {
+	server string
+	http   *http.Client
+}
+
+// NewClient creates a new Client for the given server URL
+func NewClient(server string) *Client {
+	return &Client{
+		server: strings.TrimRight(server, "/"),
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// apiError is the error body returned by the API
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// do sends a JSON request and decodes the JSON response into out, if given
+func (c *Client) do(ctx context.Context, method, path string, headers map[string]string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.server+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return responseError(resp)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// stream opens a streamed response, e.g. Server-Sent Events. The request is not bound
+// by the client timeout, it lasts until ctx is done.
+func (c *Client) stream(ctx context.Context, path, accept string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+
+	streamClient := &http.Client{Transport: c.http.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp.Body, nil
+}
+
+// responseError turns an unsuccessful response into an error
+func responseError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	var apiErr apiError
+	if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+		return fmt.Errorf("%s: %s", resp.Status, apiErr.Error)
+	}
+	return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+}
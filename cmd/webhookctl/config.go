@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultProfile = "default"
+	defaultServer  = "http://localhost:8080"
+)
+
+// Profile holds the settings for one environment
+type Profile struct {
+	Server string `yaml:"server"`
+	Output string `yaml:"output,omitempty"`
+}
+
+// Config is the webhookctl configuration file
+type Config struct {
+	CurrentProfile string             `yaml:"current_profile,omitempty"`
+	Profiles       map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+// configPath returns the configuration file path, $WEBHOOKCTL_CONFIG or
+// webhookctl/config.yaml in the user's configuration directory
+func configPath() (string, error) {
+	if path := os.Getenv("WEBHOOKCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "webhookctl", "config.yaml"), nil
+}
+
+// loadConfig reads the configuration file. A missing file is an empty configuration.
+func loadConfig(path string) (*Config, error) {
+	cfg := &Config{Profiles: map[string]Profile{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]Profile{}
+	}
+	return cfg, nil
+}
+
+// save writes the configuration file, creating its directory if needed
+func (c *Config) save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// profileName returns the profile selected by the flag, $WEBHOOKCTL_PROFILE or the
+// configuration file, in that order
+func (c *Config) profileName(flagValue string) string {
+	switch {
+	case flagValue != "":
+		return flagValue
+	case os.Getenv("WEBHOOKCTL_PROFILE") != "":
+		return os.Getenv("WEBHOOKCTL_PROFILE")
+	case c.CurrentProfile != "":
+		return c.CurrentProfile
+	default:
+		return defaultProfile
+	}
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+func (a *app) deliveries(args []string) error {
+	sub, args, err := subcommand(args, "get", "list", "tail", "replay", "scheduled", "cancel")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "get":
+		return a.getDelivery(args)
+	case "list", "ls":
+		return a.listDeliveries(args)
+	case "tail":
+		return a.tailDeliveries(args)
+	case "replay":
+		return a.replayDeliveries(args)
+	case "scheduled":
+		return a.scheduledDeliveries(args)
+	case "cancel":
+		return a.cancelDelivery(args)
+	default:
+		return fmt.Errorf("unknown deliveries subcommand %q", sub)
+	}
+}
+
+func (a *app) getDelivery(args []string) error {
+	fs := a.flagSet("deliveries get ID")
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := singleID(positional, "delivery")
+	if err != nil {
+		return err
+	}
+
+	var status models.DeliveryStatusResponse
+	if err := a.client.do(a.ctx, http.MethodGet, "/webhooks/deliveries/"+id.String(), nil, nil, &status); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(status)
+	}
+
+	d := status.Delivery
+	payload := string(d.Payload)
+	if d.IsOffloaded() {
+		payload = fmt.Sprintf("offloaded to %s (%d bytes, sha256 %s)", *d.PayloadRef, *d.PayloadSize, *d.PayloadSHA256)
+	} else if d.PayloadPurgedAt != nil {
+		payload = "removed by the retention policy at " + formatTime(*d.PayloadPurgedAt)
+	}
+	if err := a.printer.Fields([][2]string{
+		{"ID", d.ID.String()},
+		{"Subscription", d.SubscriptionID.String()},
+		{"Event type", formatString(d.EventType)},
+		{"Status", d.Status},
+		{"Retries", fmt.Sprintf("%d/%d", d.RetryCount, d.MaxRetries)},
+		{"Next retry", formatTimePtr(d.NextRetryAt)},
+		{"Created", formatTime(d.CreatedAt)},
+		{"Payload", payload},
+	}); err != nil {
+		return err
+	}
+
+	a.printer.Println()
+	if len(status.Attempts) == 0 {
+		a.printer.Println("No attempts yet")
+		return nil
+	}
+
+	rows := make([][]string, 0, len(status.Attempts))
+	for _, attempt := range status.Attempts {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", attempt.AttemptNumber),
+			attempt.Status,
+			formatInt(attempt.StatusCode),
+			formatTime(attempt.CreatedAt),
+			truncate(formatString(attempt.ErrorDetails), 80),
+		})
+	}
+	return a.printer.Table([]string{"ATTEMPT", "STATUS", "CODE", "TIME", "ERROR"}, rows)
+}
+
+func (a *app) listDeliveries(args []string) error {
+	var subscription string
+	var limit int
+	fs := a.flagSet("deliveries list --subscription ID [--limit N]")
+	fs.StringVar(&subscription, "subscription", "", "subscription ID (required)")
+	fs.IntVar(&limit, "limit", 20, "maximum number of deliveries")
+	if _, err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(subscription)
+	if err != nil {
+		return errors.New("--subscription must be a subscription ID")
+	}
+
+	var deliveries []models.WebhookDelivery
+	path := fmt.Sprintf("/subscriptions/%s/deliveries?limit=%d", id, limit)
+	if err := a.client.do(a.ctx, http.MethodGet, path, nil, nil, &deliveries); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(deliveries)
+	}
+
+	rows := make([][]string, 0, len(deliveries))
+	for _, d := range deliveries {
+		rows = append(rows, []string{
+			d.ID.String(),
+			d.Status,
+			formatString(d.EventType),
+			fmt.Sprintf("%d/%d", d.RetryCount, d.MaxRetries),
+			formatTime(d.CreatedAt),
+		})
+	}
+	return a.printer.Table([]string{"ID", "STATUS", "EVENT TYPE", "RETRIES", "CREATED"}, rows)
+}
+
+// scheduledDeliveries lists the deliveries waiting for their scheduled time
+func (a *app) scheduledDeliveries(args []string) error {
+	var subscription string
+	var limit int
+	fs := a.flagSet("deliveries scheduled [--subscription ID] [--limit N]")
+	fs.StringVar(&subscription, "subscription", "", "only list deliveries of this subscription")
+	fs.IntVar(&limit, "limit", 100, "maximum number of deliveries (at most 1000)")
+	if _, err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/webhooks/deliveries/scheduled?limit=%d", limit)
+	if subscription != "" {
+		id, err := uuid.Parse(subscription)
+		if err != nil {
+			return errors.New("--subscription must be a subscription ID")
+		}
+		path += "&subscription_id=" + id.String()
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := a.client.do(a.ctx, http.MethodGet, path, nil, nil, &deliveries); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(deliveries)
+	}
+
+	rows := make([][]string, 0, len(deliveries))
+	for _, d := range deliveries {
+		rows = append(rows, []string{
+			d.ID.String(),
+			d.SubscriptionID.String(),
+			formatString(d.EventType),
+			formatTimePtr(d.NextRetryAt),
+		})
+	}
+	return a.printer.Table([]string{"ID", "SUBSCRIPTION", "EVENT TYPE", "DELIVER AT"}, rows)
+}
+
+// cancelDelivery cancels a scheduled delivery
+func (a *app) cancelDelivery(args []string) error {
+	fs := a.flagSet("deliveries cancel ID")
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := singleID(positional, "delivery")
+	if err != nil {
+		return err
+	}
+
+	var delivery models.WebhookDelivery
+	if err := a.client.do(a.ctx, http.MethodPost, "/webhooks/deliveries/"+id.String()+"/cancel", nil, nil, &delivery); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(delivery)
+	}
+	a.printer.Println("Cancelled delivery", delivery.ID)
+	return nil
+}
+
+// tailDeliveries follows the delivery event stream of a subscription until interrupted
+func (a *app) tailDeliveries(args []string) error {
+	var subscription string
+	fs := a.flagSet("deliveries tail --subscription ID")
+	fs.StringVar(&subscription, "subscription", "", "subscription ID (required)")
+	if _, err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(subscription)
+	if err != nil {
+		return errors.New("--subscription must be a subscription ID")
+	}
+
+	body, err := a.client.stream(a.ctx, "/subscriptions/"+id.String()+"/deliveries/stream", "text/event-stream")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if !a.printer.JSON() {
+		fmt.Fprintf(os.Stderr, "Following deliveries for subscription %s, press Ctrl+C to stop\n", id)
+	}
+
+	// Each SSE event is a block of lines ending in a blank line. Only the data field
+	// is needed since it holds the full event.
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case line == "" && data.Len() > 0:
+			if err := a.printDeliveryEvent(data.String()); err != nil {
+				return err
+			}
+			data.Reset()
+		}
+	}
+
+	if a.ctx.Err() != nil {
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("event stream closed by the server")
+}
+
+func (a *app) printDeliveryEvent(data string) error {
+	var event models.DeliveryEvent
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return fmt.Errorf("malformed delivery event: %w", err)
+	}
+
+	if a.printer.JSON() {
+		encoded, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		a.printer.Println(string(encoded))
+		return nil
+	}
+
+	line := fmt.Sprintf("%s  %-24s  %s  %-10s", event.Timestamp.Local().Format(time.TimeOnly), event.Type, event.DeliveryID, event.Status)
+	if event.AttemptNumber > 0 {
+		line += fmt.Sprintf("  attempt=%d", event.AttemptNumber)
+	}
+	if event.StatusCode != nil {
+		line += fmt.Sprintf("  code=%d", *event.StatusCode)
+	}
+	if event.NextRetryAt != nil {
+		line += "  next_retry=" + formatTimePtr(event.NextRetryAt)
+	}
+	if event.ErrorDetails != nil {
+		line += "  error=" + truncate(*event.ErrorDetails, 80)
+	}
+	a.printer.Println(line)
+	return nil
+}
+
+// replayDeliveries replays the given failed deliveries, or those matching the filter flags
+func (a *app) replayDeliveries(args []string) error {
+	var subscription, eventType, since, until string
+	var limit int
+	fs := a.flagSet("deliveries replay ID... | deliveries replay [--subscription ID] [--event-type TYPE] [--since TIME] [--until TIME] [--limit N]")
+	fs.StringVar(&subscription, "subscription", "", "only replay deliveries of this subscription")
+	fs.StringVar(&eventType, "event-type", "", "only replay deliveries of this event type")
+	fs.StringVar(&since, "since", "", "only replay deliveries created after this RFC3339 time or duration ago, e.g. 24h")
+	fs.StringVar(&until, "until", "", "only replay deliveries created before this RFC3339 time or duration ago")
+	fs.IntVar(&limit, "limit", 100, "maximum number of deliveries to replay (at most 1000)")
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var resp models.ReplayResponse
+
+	if len(positional) > 0 {
+		filtered := false
+		fs.Visit(func(fl *flag.Flag) {
+			if fl.Name != "o" && fl.Name != "output" {
+				filtered = true
+			}
+		})
+		if filtered {
+			return errors.New("delivery IDs cannot be combined with filter flags")
+		}
+
+		for _, raw := range positional {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return fmt.Errorf("invalid delivery ID %q", raw)
+			}
+			var one models.ReplayResponse
+			if err := a.client.do(a.ctx, http.MethodPost, "/webhooks/deliveries/"+id.String()+"/replay", nil, nil, &one); err != nil {
+				return fmt.Errorf("delivery %s: %w", id, err)
+			}
+			resp.DeliveryIDs = append(resp.DeliveryIDs, one.DeliveryIDs...)
+		}
+		resp.Message = fmt.Sprintf("%d deliveries queued for replay", len(resp.DeliveryIDs))
+	} else {
+		req := models.ReplayRequest{EventType: eventType, Limit: limit}
+		if subscription != "" {
+			id, err := uuid.Parse(subscription)
+			if err != nil {
+				return errors.New("--subscription must be a subscription ID")
+			}
+			req.SubscriptionID = &id
+		}
+		if req.Since, err = parseTimeFlag("since", since); err != nil {
+			return err
+		}
+		if req.Until, err = parseTimeFlag("until", until); err != nil {
+			return err
+		}
+
+		if err := a.client.do(a.ctx, http.MethodPost, "/webhooks/deliveries/replay", nil, req, &resp); err != nil {
+			return err
+		}
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(resp)
+	}
+	a.printer.Println(resp.Message)
+	for _, id := range resp.DeliveryIDs {
+		a.printer.Println(id)
+	}
+	return nil
+}
+
+// parseTimeFlag parses an RFC3339 time or a duration before now
+func parseTimeFlag(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		t := time.Now().Add(-d)
+		return &t, nil
+	}
+	return nil, fmt.Errorf("--%s must be an RFC3339 time or a duration such as 24h", name)
+}
+
+func truncate(s string, n int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/pkg/signature"
+)
+
+// payloadFlags are the flags that supply an event payload
+type payloadFlags struct {
+	data     string
+	dataFile string
+}
+
+func (f *payloadFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.data, "data", "", "JSON payload (default a test payload)")
+	fs.StringVar(&f.dataFile, "data-file", "", "file with the JSON payload, - for stdin")
+}
+
+// payload returns the compacted JSON payload. It is compacted so that the signature
+// covers exactly the bytes the API receives.
+func (f *payloadFlags) payload(eventType string) (json.RawMessage, error) {
+	var data []byte
+	switch {
+	case f.data != "" && f.dataFile != "":
+		return nil, errors.New("--data and --data-file are mutually exclusive")
+	case f.data != "":
+		data = []byte(f.data)
+	case f.dataFile == "-":
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(os.Stdin); err != nil {
+			return nil, err
+		}
+		data = buf.Bytes()
+	case f.dataFile != "":
+		var err error
+		if data, err = os.ReadFile(f.dataFile); err != nil {
+			return nil, err
+		}
+	default:
+		test := map[string]interface{}{
+			"test":    true,
+			"type":    eventType,
+			"sent_at": time.Now().UTC().Format(time.RFC3339),
+		}
+		return json.Marshal(test)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return nil, fmt.Errorf("payload is not valid JSON: %w", err)
+	}
+	return compact.Bytes(), nil
+}
+
+// scheduleFlags are the flags that schedule a delivery for later or let it expire
+type scheduleFlags struct {
+	deliverAt string
+	delay     time.Duration
+	expiresAt string
+}
+
+func (f *scheduleFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.deliverAt, "deliver-at", "", "RFC3339 time to deliver at")
+	fs.DurationVar(&f.delay, "delay", 0, "delay before delivering, e.g. 24h")
+	fs.StringVar(&f.expiresAt, "expires-at", "", "RFC3339 time or duration from now, e.g. 15m, after which the event is not delivered")
+}
+
+// apply sets the schedule of a webhook request
+func (f *scheduleFlags) apply(req *models.WebhookRequest) error {
+	if f.deliverAt != "" {
+		t, err := time.Parse(time.RFC3339, f.deliverAt)
+		if err != nil {
+			return errors.New("--deliver-at must be an RFC3339 time")
+		}
+		req.DeliverAt = &t
+	}
+	if f.delay != 0 {
+		req.Delay = f.delay.String()
+	}
+	if f.expiresAt != "" {
+		if t, err := time.Parse(time.RFC3339, f.expiresAt); err == nil {
+			req.ExpiresAt = &t
+		} else if d, err := time.ParseDuration(f.expiresAt); err == nil {
+			t := time.Now().Add(d)
+			req.ExpiresAt = &t
+		} else {
+			return errors.New("--expires-at must be an RFC3339 time or a duration")
+		}
+	}
+	return nil
+}
+
+func (a *app) send(args []string) error {
+	var f payloadFlags
+	var schedule scheduleFlags
+	var subscription, eventType, secret string
+	fs := a.flagSet("send --subscription ID [--event-type TYPE] [--data JSON | --data-file FILE] [--secret KEY] [--deliver-at TIME | --delay DURATION] [--expires-at TIME]")
+	fs.StringVar(&subscription, "subscription", "", "subscription ID (required)")
+	fs.StringVar(&eventType, "event-type", "", "event type")
+	fs.StringVar(&secret, "secret", "", "secret key to sign the payload with")
+	f.register(fs)
+	schedule.register(fs)
+	if _, err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(subscription)
+	if err != nil {
+		return errors.New("--subscription must be a subscription ID")
+	}
+
+	payload, err := f.payload(eventType)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{}
+	if eventType != "" {
+		headers["X-Event-Type"] = eventType
+	}
+	if secret != "" {
+		headers[signature.HubHeader] = signature.Hub(secret, payload)
+	}
+
+	var resp apiMessage
+	req := models.WebhookRequest{Payload: payload}
+	if err := schedule.apply(&req); err != nil {
+		return err
+	}
+	if err := a.client.do(a.ctx, http.MethodPost, "/webhooks/ingest/"+id.String(), headers, req, &resp); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(resp)
+	}
+	a.printer.Println(resp.Message)
+	return nil
+}
+
+func (a *app) publish(args []string) error {
+	var f payloadFlags
+	var schedule scheduleFlags
+	var eventType string
+	fs := a.flagSet("publish --event-type TYPE [--data JSON | --data-file FILE] [--deliver-at TIME | --delay DURATION] [--expires-at TIME]")
+	fs.StringVar(&eventType, "event-type", "", "event type (required)")
+	f.register(fs)
+	schedule.register(fs)
+	if _, err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	if eventType == "" {
+		return errors.New("--event-type is required")
+	}
+
+	payload, err := f.payload(eventType)
+	if err != nil {
+		return err
+	}
+
+	var resp models.PublishResponse
+	req := models.WebhookRequest{Payload: payload}
+	if err := schedule.apply(&req); err != nil {
+		return err
+	}
+	headers := map[string]string{"X-Event-Type": eventType}
+	if err := a.client.do(a.ctx, http.MethodPost, "/webhooks/publish", headers, req, &resp); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(resp)
+	}
+	a.printer.Println(resp.Message)
+	for _, id := range resp.DeliveryIDs {
+		a.printer.Println(id)
+	}
+	return nil
+}
+
+// apiMessage is the success body returned by the API
+type apiMessage struct {
+	Message string `json:"message"`
+}
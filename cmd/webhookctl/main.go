@@ -0,0 +1,199 @@
+// Command webhookctl manages subscriptions and deliveries through the webhook
+// service REST API.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+const usage = `webhookctl manages webhook subscriptions and deliveries.
+
+Usage:
+  webhookctl [global flags] <command> [subcommand] [flags] [args]
+
+Commands:
+  subscriptions (subs)  create, list, get, update or delete subscriptions, rotate or reveal a secret
+  send                  send a test event to a subscription
+  publish               publish an event to every subscription that accepts it
+  deliveries            get, list, tail, replay or cancel deliveries
+  archive               restore the deliveries archived for a day
+  audit                 list the audit log of management operations
+  profiles              list, set, use or delete configuration profiles
+
+Global flags:
+  --profile string   configuration profile (default $WEBHOOKCTL_PROFILE, then the current profile)
+  --server string    API server URL, overrides the profile
+  -o, --output fmt   output format: table or json
+
+Run 'webhookctl <command> -h' for the flags of a command.
+`
+
+// app holds the state shared by all commands
+type app struct {
+	ctx        context.Context
+	client     *Client
+	printer    *Printer
+	config     *Config
+	configPath string
+	profile    string
+	output     string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	global := flag.NewFlagSet("webhookctl", flag.ContinueOnError)
+	global.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+
+	var profile, server, output string
+	global.StringVar(&profile, "profile", "", "configuration profile")
+	global.StringVar(&server, "server", "", "API server URL")
+	global.StringVar(&output, "output", "", "output format")
+	global.StringVar(&output, "o", "", "output format")
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	a := &app{
+		ctx:        ctx,
+		config:     cfg,
+		configPath: path,
+		profile:    cfg.profileName(profile),
+		output:     output,
+	}
+
+	selected := cfg.Profiles[a.profile]
+	switch {
+	case server != "":
+	case os.Getenv("WEBHOOKCTL_SERVER") != "":
+		server = os.Getenv("WEBHOOKCTL_SERVER")
+	case selected.Server != "":
+		server = selected.Server
+	default:
+		server = defaultServer
+	}
+	if a.output == "" {
+		a.output = selected.Output
+	}
+
+	a.client = NewClient(server)
+
+	rest := global.Args()
+	if len(rest) == 0 {
+		global.Usage()
+		return flag.ErrHelp
+	}
+
+	command, rest := rest[0], rest[1:]
+	switch command {
+	case "subscriptions", "subscription", "subs", "sub":
+		return a.subscriptions(rest)
+	case "send":
+		return a.send(rest)
+	case "publish":
+		return a.publish(rest)
+	case "deliveries", "delivery":
+		return a.deliveries(rest)
+	case "archive":
+		return a.archive(rest)
+	case "audit":
+		return a.audit(rest)
+	case "profiles", "profile":
+		return a.profiles(rest)
+	case "help":
+		global.Usage()
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, run 'webhookctl help' for usage", command)
+	}
+}
+
+// flagSet creates the flag set for a command, including the output flags so they can
+// be given after the command as well
+func (a *app) flagSet(synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet("webhookctl", flag.ContinueOnError)
+	fs.StringVar(&a.output, "output", a.output, "output format: table or json")
+	fs.StringVar(&a.output, "o", a.output, "output format: table or json")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage:\n  webhookctl %s\n\nFlags:\n", synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses flags that may be interleaved with positional arguments and sets up
+// the printer for the selected output format
+func (a *app) parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	switch a.output {
+	case "", outputTable:
+		a.printer = &Printer{out: os.Stdout, format: outputTable}
+	case outputJSON:
+		a.printer = &Printer{out: os.Stdout, format: outputJSON}
+	default:
+		return nil, fmt.Errorf("unknown output format %q, expected table or json", a.output)
+	}
+	return positional, nil
+}
+
+// subcommand splits a subcommand from its arguments
+func subcommand(args []string, names ...string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("expected a subcommand: %s", strings.Join(names, ", "))
+	}
+	return args[0], args[1:], nil
+}
+
+// stringList is a repeatable flag that also accepts comma separated values
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*s = append(*s, v)
+		}
+	}
+	return nil
+}
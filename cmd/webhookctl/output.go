@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Output formats
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// Printer writes command results as tables or JSON
+type Printer struct {
+	out    io.Writer
+	format string
+}
+
+// JSON reports whether results are printed as JSON
+func (p *Printer) JSON() bool {
+	return p.format == outputJSON
+}
+
+// PrintJSON writes v as indented JSON
+func (p *Printer) PrintJSON(v interface{}) error {
+	enc := json.NewEncoder(p.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Table writes rows under a header row, aligning the columns
+func (p *Printer) Table(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// Fields writes name/value pairs, one per line
+func (p *Printer) Fields(fields [][2]string) error {
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	for _, field := range fields {
+		fmt.Fprintf(w, "%s:\t%s\n", field[0], field[1])
+	}
+	return w.Flush()
+}
+
+// Println writes a line of plain text
+func (p *Printer) Println(a ...interface{}) {
+	fmt.Fprintln(p.out, a...)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return formatTime(*t)
+}
+
+func formatString(s *string) string {
+	if s == nil || *s == "" {
+		return "-"
+	}
+	return *s
+}
+
+func formatInt(i *int) string {
+	if i == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d", *i)
+}
+
+func formatList(values []string) string {
+	if len(values) == 0 {
+		return "*"
+	}
+	return strings.Join(values, ",")
+}
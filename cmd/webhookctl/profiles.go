@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+func (a *app) profiles(args []string) error {
+	sub, args, err := subcommand(args, "list", "set", "use", "delete")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list", "ls":
+		return a.listProfiles(args)
+	case "set":
+		return a.setProfile(args)
+	case "use":
+		return a.useProfile(args)
+	case "delete", "rm":
+		return a.deleteProfile(args)
+	default:
+		return fmt.Errorf("unknown profiles subcommand %q", sub)
+	}
+}
+
+func (a *app) listProfiles(args []string) error {
+	fs := a.flagSet("profiles list")
+	if _, err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(a.config)
+	}
+
+	names := make([]string, 0, len(a.config.Profiles))
+	for name := range a.config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		current := ""
+		if name == a.profile {
+			current = "*"
+		}
+		profile := a.config.Profiles[name]
+		output := profile.Output
+		if output == "" {
+			output = outputTable
+		}
+		rows = append(rows, []string{current, name, profile.Server, output})
+	}
+	return a.printer.Table([]string{"CURRENT", "NAME", "SERVER", "OUTPUT"}, rows)
+}
+
+// setProfile creates or updates a profile
+func (a *app) setProfile(args []string) error {
+	var server, output string
+	fs := a.flagSet("profiles set NAME [--server URL] [--default-output table|json]")
+	fs.StringVar(&server, "server", "", "API server URL")
+	fs.StringVar(&output, "default-output", "", "default output format: table or json")
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("expected exactly one profile name")
+	}
+	name := positional[0]
+
+	if output != "" && output != outputTable && output != outputJSON {
+		return fmt.Errorf("unknown output format %q, expected table or json", output)
+	}
+
+	profile, exists := a.config.Profiles[name]
+	if server != "" {
+		profile.Server = server
+	}
+	if output != "" {
+		profile.Output = output
+	}
+	if profile.Server == "" {
+		return errors.New("--server is required for a new profile")
+	}
+
+	a.config.Profiles[name] = profile
+	if a.config.CurrentProfile == "" {
+		a.config.CurrentProfile = name
+	}
+	if err := a.config.save(a.configPath); err != nil {
+		return err
+	}
+
+	if exists {
+		a.printer.Println("Updated profile", name)
+	} else {
+		a.printer.Println("Created profile", name)
+	}
+	return nil
+}
+
+// useProfile makes a profile the current one
+func (a *app) useProfile(args []string) error {
+	fs := a.flagSet("profiles use NAME")
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("expected exactly one profile name")
+	}
+	name := positional[0]
+
+	if _, ok := a.config.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+
+	a.config.CurrentProfile = name
+	if err := a.config.save(a.configPath); err != nil {
+		return err
+	}
+	a.printer.Println("Using profile", name)
+	return nil
+}
+
+func (a *app) deleteProfile(args []string) error {
+	fs := a.flagSet("profiles delete NAME")
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("expected exactly one profile name")
+	}
+	name := positional[0]
+
+	if _, ok := a.config.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+
+	delete(a.config.Profiles, name)
+	if a.config.CurrentProfile == name {
+		a.config.CurrentProfile = ""
+	}
+	if err := a.config.save(a.configPath); err != nil {
+		return err
+	}
+	a.printer.Println("Deleted profile", name)
+	return nil
+}
@@ -0,0 +1,509 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+func (a *app) subscriptions(args []string) error {
+	sub, args, err := subcommand(args, "create", "list", "get", "update", "delete", "rotate-secret", "reveal-secret")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "create":
+		return a.createSubscription(args)
+	case "list", "ls":
+		return a.listSubscriptions(args)
+	case "get":
+		return a.getSubscription(args)
+	case "update":
+		return a.updateSubscription(args)
+	case "delete", "rm":
+		return a.deleteSubscription(args)
+	case "rotate-secret":
+		return a.rotateSubscriptionSecret(args)
+	case "reveal-secret":
+		return a.revealSubscriptionSecret(args)
+	default:
+		return fmt.Errorf("unknown subscriptions subcommand %q", sub)
+	}
+}
+
+// subscriptionFlags are the flags that set subscription fields
+type subscriptionFlags struct {
+	file            string
+	targetURL       string
+	secret          string
+	eventTypes      stringList
+	destinationType string
+	topic           string
+	key             string
+	stream          string
+	maxLen          int64
+	signatureScheme string
+	maxEventAge     time.Duration
+	retention       stringList
+	retentionMode   string
+}
+
+func (f *subscriptionFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.file, "f", "", "YAML or JSON file with the subscription, flags override its fields")
+	fs.StringVar(&f.targetURL, "url", "", "target URL for http destinations")
+	fs.StringVar(&f.secret, "secret", "", "secret key used to sign deliveries (generated on create, unchanged on update when not set)")
+	fs.Var(&f.eventTypes, "event-type", "event type to receive, repeatable (default all)")
+	fs.StringVar(&f.destinationType, "destination-type", "", "http, kafka or redis_stream")
+	fs.StringVar(&f.topic, "topic", "", "Kafka topic for kafka destinations")
+	fs.StringVar(&f.key, "key", "", "Kafka message key for kafka destinations")
+	fs.StringVar(&f.stream, "stream", "", "Redis stream for redis_stream destinations")
+	fs.Int64Var(&f.maxLen, "max-len", 0, "approximate Redis stream length cap for redis_stream destinations")
+	fs.StringVar(&f.signatureScheme, "signature-scheme", "", "github, standard_webhooks or ed25519 for http destinations (default github)")
+	fs.DurationVar(&f.maxEventAge, "max-event-age", 0, "expire deliveries not sent this long after the event, e.g. 15m (0 removes the limit)")
+	fs.Var(&f.retention, "retention", "retention of deliveries by terminal status as STATUS=DURATION, e.g. delivered=168h,failed=720h (default the server's)")
+	fs.StringVar(&f.retentionMode, "retention-mode", "", "delete or strip_payload, what happens to deliveries past their retention (default the server's)")
+}
+
+// apply overlays the file and then the flags that were set onto req
+func (f *subscriptionFlags) apply(fs *flag.FlagSet, req *models.SubscriptionRequest) error {
+	if f.file != "" {
+		if err := readSubscriptionFile(f.file, req); err != nil {
+			return err
+		}
+	}
+
+	destination := func() *models.DestinationConfig {
+		if req.DestinationConfig == nil {
+			req.DestinationConfig = &models.DestinationConfig{}
+		}
+		return req.DestinationConfig
+	}
+	retention := func() *models.RetentionPolicy {
+		if req.RetentionPolicy == nil {
+			req.RetentionPolicy = &models.RetentionPolicy{}
+		}
+		return req.RetentionPolicy
+	}
+
+	var err error
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "url":
+			req.TargetURL = f.targetURL
+		case "secret":
+			secret := f.secret
+			req.SecretKey = &secret
+		case "event-type":
+			req.EventTypes = f.eventTypes
+		case "destination-type":
+			req.DestinationType = f.destinationType
+		case "topic":
+			destination().Topic = f.topic
+		case "key":
+			destination().Key = f.key
+		case "stream":
+			destination().Stream = f.stream
+		case "max-len":
+			destination().MaxLen = f.maxLen
+		case "signature-scheme":
+			req.SignatureScheme = f.signatureScheme
+		case "max-event-age":
+			seconds := int(f.maxEventAge / time.Second)
+			req.MaxEventAgeSeconds = &seconds
+		case "retention":
+			for _, entry := range f.retention {
+				if setErr := setRetention(retention(), entry); setErr != nil {
+					err = setErr
+				}
+			}
+		case "retention-mode":
+			retention().Mode = f.retentionMode
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if f.maxEventAge < 0 || (f.maxEventAge > 0 && f.maxEventAge < time.Second) {
+		return errors.New("--max-event-age must be 0 or at least 1s")
+	}
+	return nil
+}
+
+// setRetention sets the retention of one terminal status from a STATUS=DURATION entry
+func setRetention(policy *models.RetentionPolicy, entry string) error {
+	status, value, ok := strings.Cut(entry, "=")
+	d, err := time.ParseDuration(value)
+	if !ok || err != nil || d < time.Second {
+		return fmt.Errorf("--retention %q must be STATUS=DURATION with a duration of at least 1s", entry)
+	}
+
+	seconds := int(d / time.Second)
+	switch strings.ToUpper(status) {
+	case models.StatusDelivered:
+		policy.DeliveredSeconds = seconds
+	case models.StatusFailed:
+		policy.FailedSeconds = seconds
+	case models.StatusCancelled:
+		policy.CancelledSeconds = seconds
+	case models.StatusExpired:
+		policy.ExpiredSeconds = seconds
+	default:
+		return fmt.Errorf("--retention status must be delivered, failed, cancelled or expired, not %q", status)
+	}
+	return nil
+}
+
+// readSubscriptionFile decodes a YAML (or JSON) subscription file onto req, using the
+// same field names as the API
+func readSubscriptionFile(path string, req *models.SubscriptionRequest) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// Round trip through JSON so the API's json field names apply
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := json.Unmarshal(encoded, req); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+func (a *app) createSubscription(args []string) error {
+	var f subscriptionFlags
+	fs := a.flagSet("subscriptions create [-f file] [--url URL] [flags]")
+	f.register(fs)
+	if _, err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	var req models.SubscriptionRequest
+	if err := f.apply(fs, &req); err != nil {
+		return err
+	}
+
+	var sub models.CreatedSubscription
+	if err := a.client.do(a.ctx, http.MethodPost, "/subscriptions/", nil, req, &sub); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(sub)
+	}
+
+	secret := "-"
+	if sub.SecretKey != nil {
+		secret = *sub.SecretKey
+	}
+	if err := a.printer.Fields(subscriptionFields(sub.Subscription, secret)); err != nil {
+		return err
+	}
+	if sub.SecretKey != nil {
+		a.printer.Println("\nStore the secret now, it is not shown again.")
+	}
+	return nil
+}
+
+func (a *app) listSubscriptions(args []string) error {
+	fs := a.flagSet("subscriptions list")
+	if _, err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	var subs []models.Subscription
+	if err := a.client.do(a.ctx, http.MethodGet, "/subscriptions/", nil, nil, &subs); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(subs)
+	}
+
+	rows := make([][]string, 0, len(subs))
+	for _, sub := range subs {
+		rows = append(rows, []string{
+			sub.ID.String(),
+			sub.DestinationType,
+			destinationTarget(sub),
+			formatList(sub.EventTypes),
+			formatTime(sub.CreatedAt),
+		})
+	}
+	return a.printer.Table([]string{"ID", "TYPE", "DESTINATION", "EVENT TYPES", "CREATED"}, rows)
+}
+
+func (a *app) getSubscription(args []string) error {
+	fs := a.flagSet("subscriptions get ID")
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := singleID(positional, "subscription")
+	if err != nil {
+		return err
+	}
+
+	sub, err := a.fetchSubscription(id)
+	if err != nil {
+		return err
+	}
+	return a.printSubscription(sub)
+}
+
+func (a *app) updateSubscription(args []string) error {
+	var f subscriptionFlags
+	fs := a.flagSet("subscriptions update ID [-f file] [flags]")
+	f.register(fs)
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := singleID(positional, "subscription")
+	if err != nil {
+		return err
+	}
+
+	// The API replaces the whole subscription, so start from its current state. The
+	// secret is kept unless --secret is set.
+	current, err := a.fetchSubscription(id)
+	if err != nil {
+		return err
+	}
+	req := models.SubscriptionRequest{
+		TargetURL:         current.TargetURL,
+		EventTypes:        current.EventTypes,
+		DestinationType:   current.DestinationType,
+		DestinationConfig: current.DestinationConfig,
+		SignatureScheme:   current.SignatureScheme,
+	}
+	if err := f.apply(fs, &req); err != nil {
+		return err
+	}
+
+	var sub models.Subscription
+	if err := a.client.do(a.ctx, http.MethodPut, "/subscriptions/"+id.String(), nil, req, &sub); err != nil {
+		return err
+	}
+	return a.printSubscription(sub)
+}
+
+func (a *app) deleteSubscription(args []string) error {
+	fs := a.flagSet("subscriptions delete ID...")
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("expected at least one subscription ID")
+	}
+
+	for _, raw := range positional {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("invalid subscription ID %q", raw)
+		}
+		if err := a.client.do(a.ctx, http.MethodDelete, "/subscriptions/"+id.String(), nil, nil, nil); err != nil {
+			return err
+		}
+		if !a.printer.JSON() {
+			a.printer.Println("Deleted subscription", id)
+		}
+	}
+	return nil
+}
+
+func (a *app) rotateSubscriptionSecret(args []string) error {
+	var secret string
+	var overlap time.Duration
+	fs := a.flagSet("subscriptions rotate-secret ID [--secret KEY] [--overlap DURATION]")
+	fs.StringVar(&secret, "secret", "", "new secret key (default generated)")
+	fs.DurationVar(&overlap, "overlap", 0, "how long the old secret stays valid, e.g. 48h (default the server's SECRET_ROTATION_OVERLAP)")
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := singleID(positional, "subscription")
+	if err != nil {
+		return err
+	}
+
+	var req models.RotateSecretRequest
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "secret":
+			req.SecretKey = &secret
+		case "overlap":
+			seconds := int(overlap / time.Second)
+			req.OverlapSeconds = &seconds
+		}
+	})
+
+	var resp models.RotateSecretResponse
+	if err := a.client.do(a.ctx, http.MethodPost, "/subscriptions/"+id.String()+"/secret/rotate", nil, req, &resp); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(resp)
+	}
+
+	previous := "none"
+	if resp.PreviousSecretExpiresAt != nil {
+		previous = "valid until " + formatTime(*resp.PreviousSecretExpiresAt)
+	}
+	if err := a.printer.Fields([][2]string{
+		{"ID", resp.SubscriptionID.String()},
+		{"Secret", resp.SecretKey},
+		{"Previous secret", previous},
+	}); err != nil {
+		return err
+	}
+	a.printer.Println("\nStore the secret now, it is not shown again.")
+	return nil
+}
+
+func (a *app) revealSubscriptionSecret(args []string) error {
+	var reason, token string
+	fs := a.flagSet("subscriptions reveal-secret ID --reason TEXT [--token TOKEN]")
+	fs.StringVar(&reason, "reason", "", "why the secret is needed, recorded in the audit log (required)")
+	fs.StringVar(&token, "token", os.Getenv("WEBHOOKCTL_REVEAL_TOKEN"), "secret reveal token (default $WEBHOOKCTL_REVEAL_TOKEN)")
+	positional, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := singleID(positional, "subscription")
+	if err != nil {
+		return err
+	}
+	if reason == "" {
+		return errors.New("--reason is required")
+	}
+	if token == "" {
+		return errors.New("--token or $WEBHOOKCTL_REVEAL_TOKEN is required")
+	}
+
+	var resp models.RevealSecretResponse
+	headers := map[string]string{"Authorization": "Bearer " + token}
+	req := models.RevealSecretRequest{Reason: reason}
+	if err := a.client.do(a.ctx, http.MethodPost, "/subscriptions/"+id.String()+"/secret/reveal", headers, req, &resp); err != nil {
+		return err
+	}
+
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(resp)
+	}
+	a.printer.Println(resp.SecretKey)
+	return nil
+}
+
+func (a *app) fetchSubscription(id uuid.UUID) (models.Subscription, error) {
+	var sub models.Subscription
+	err := a.client.do(a.ctx, http.MethodGet, "/subscriptions/"+id.String(), nil, nil, &sub)
+	return sub, err
+}
+
+func (a *app) printSubscription(sub models.Subscription) error {
+	if a.printer.JSON() {
+		return a.printer.PrintJSON(sub)
+	}
+
+	secret := "-"
+	if sub.SecretKeyHint != "" {
+		secret = sub.SecretKeyHint
+	}
+	if sub.PreviousSecretExpiresAt != nil && time.Now().Before(*sub.PreviousSecretExpiresAt) {
+		secret += ", previous valid until " + formatTime(*sub.PreviousSecretExpiresAt)
+	}
+	return a.printer.Fields(subscriptionFields(sub, secret))
+}
+
+// subscriptionFields are the fields shown for a subscription
+func subscriptionFields(sub models.Subscription, secret string) [][2]string {
+	return [][2]string{
+		{"ID", sub.ID.String()},
+		{"Type", sub.DestinationType},
+		{"Destination", destinationTarget(sub)},
+		{"Event types", formatList(sub.EventTypes)},
+		{"Secret", secret},
+		{"Signature scheme", sub.SignatureScheme},
+		{"Max event age", formatMaxEventAge(sub)},
+		{"Retention", formatRetention(sub.RetentionPolicy)},
+		{"Created", formatTime(sub.CreatedAt)},
+		{"Updated", formatTime(sub.UpdatedAt)},
+	}
+}
+
+// formatMaxEventAge formats how long after the event a subscription's deliveries expire
+func formatMaxEventAge(sub models.Subscription) string {
+	if sub.MaxEventAgeSeconds == nil {
+		return "-"
+	}
+	return sub.MaxEventAge().String()
+}
+
+// formatRetention formats a subscription's overrides of the global retention policy
+func formatRetention(policy *models.RetentionPolicy) string {
+	if policy == nil {
+		return "-"
+	}
+
+	var parts []string
+	for _, status := range models.RetentionStatuses {
+		if d := policy.Retention(status); d > 0 {
+			parts = append(parts, strings.ToLower(status)+"="+d.String())
+		}
+	}
+	if policy.Mode != "" {
+		parts = append(parts, "mode="+policy.Mode)
+	}
+	return strings.Join(parts, " ")
+}
+
+// destinationTarget describes where a subscription delivers to
+func destinationTarget(sub models.Subscription) string {
+	cfg := sub.DestinationConfig
+	switch sub.DestinationType {
+	case models.DestinationKafka:
+		if cfg != nil {
+			return "topic " + cfg.Topic
+		}
+	case models.DestinationRedisStream:
+		if cfg != nil {
+			return "stream " + cfg.Stream
+		}
+	default:
+		return sub.TargetURL
+	}
+	return "-"
+}
+
+// singleID parses the single ID argument of a command
+func singleID(positional []string, kind string) (uuid.UUID, error) {
+	if len(positional) != 1 {
+		return uuid.Nil, fmt.Errorf("expected exactly one %s ID", kind)
+	}
+	id, err := uuid.Parse(positional[0])
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid %s ID %q", kind, positional[0])
+	}
+	return id, nil
+}
@@ -10,13 +10,26 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
+	"github.com/hibiken/asynq"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 
+	kafkaconfig "github.com/Unic-X/webhook-delivery/config"
+	"github.com/Unic-X/webhook-delivery/internal/archive"
+	"github.com/Unic-X/webhook-delivery/internal/blob"
 	"github.com/Unic-X/webhook-delivery/internal/config"
+	"github.com/Unic-X/webhook-delivery/internal/destination"
+	"github.com/Unic-X/webhook-delivery/internal/envelope"
+	"github.com/Unic-X/webhook-delivery/internal/kafka"
+	"github.com/Unic-X/webhook-delivery/internal/metrics"
+	"github.com/Unic-X/webhook-delivery/internal/models"
 	"github.com/Unic-X/webhook-delivery/internal/repository"
 	"github.com/Unic-X/webhook-delivery/internal/service"
+	"github.com/Unic-X/webhook-delivery/internal/signing"
+	"github.com/Unic-X/webhook-delivery/internal/tracing"
 	"github.com/Unic-X/webhook-delivery/internal/worker"
 )
 
@@ -32,6 +45,17 @@ func main() {
 		logger.WithError(err).Fatal("Failed to load configuration")
 	}
 
+	// Set up tracing
+	shutdownTracing, err := tracing.Init(context.Background(), cfg, "webhook-worker")
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up tracing")
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.WithError(err).Error("Failed to flush traces")
+		}
+	}()
+
 	// Set up database connection
 	db, err := setupDatabase(cfg, logger)
 	if err != nil {
@@ -55,15 +79,68 @@ func main() {
 		logger.WithError(err).Fatal("Failed to connect to Redis")
 	}
 
-	// Initialize repository
-	repo := repository.NewPostgresRepository(db)
+	// Initialize repository, encrypting secrets and payloads at rest when master keys are configured
+	keyring, err := envelope.LoadKeyring(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load encryption keys")
+	}
+	repo := repository.New(db, keyring)
 
 	// Initialize service
 	svc := service.NewWebhookService(repo, redisClient, cfg, logger)
 
+	// Set up the blob store large payloads are offloaded to
+	blobStore, err := blob.Open(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up blob store")
+	}
+	svc.SetBlobStore(blobStore)
+
+	// Set up the sink deliveries are archived to before retention removes them
+	archiveSink, err := archive.Open(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up archive sink")
+	}
+	svc.SetArchiveSink(archiveSink)
+
+	// Load the Ed25519 keys of the ed25519 signature scheme
+	signingKeys, err := signing.LoadKeyring(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load signing keys")
+	}
+	svc.SetSigningKeys(signingKeys)
+
+	// Set up the Kafka producer for kafka destinations
+	if cfg.KafkaBrokers != "" {
+		producer, err := kafkaconfig.KafkaInit(cfg.KafkaBrokers)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to set up Kafka producer")
+		}
+		defer func() {
+			producer.Flush(5000)
+			producer.Close()
+		}()
+
+		go kafka.LogEvents(producer, logger)
+		svc.RegisterDestination(models.DestinationKafka, destination.NewKafka(kafka.NewPublisher(producer)))
+	}
+
 	// Initialize worker
 	wkr := worker.NewWorker(svc, cfg, logger)
 
+	// Register queue depth and DLQ size collectors
+	inspector := asynq.NewInspector(asynq.RedisClientOpt{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+	defer inspector.Close()
+
+	prometheus.MustRegister(
+		metrics.NewQueueCollector(inspector, logger),
+		metrics.NewDeadLetterCollector(repo, logger),
+	)
+
 	// Set up graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -76,6 +153,7 @@ func main() {
 	router := gin.Default()
 
 	router.HEAD("/healthz", func(ctx *gin.Context) { ctx.JSON(http.StatusOK, "Ready") })
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	server := &http.Server{
 		Addr:    ":" + "9090",
@@ -6,21 +6,36 @@ import (
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
 
-func KafkaInit() (*kafka.Producer, error) {
-	const (
-		KafkaServer = "localhost:9092"
-	)
-
+// KafkaInit creates a Kafka producer connected to the given comma separated brokers
+func KafkaInit(brokers string) (*kafka.Producer, error) {
 	p, err := kafka.NewProducer(&kafka.ConfigMap{
-		"bootstrap.servers": KafkaServer,
+		"bootstrap.servers": brokers,
 	})
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
 	}
 
-	fmt.Println("Connected to Kafka Server", KafkaServer)
+	fmt.Println("Connected to Kafka Server", brokers)
 
 	return p, nil
 }
 
+// KafkaConsumerInit creates a Kafka consumer in the given consumer group. Offsets are
+// not committed automatically, callers commit them once a message has been handled.
+func KafkaConsumerInit(brokers string, group string) (*kafka.Consumer, error) {
+	c, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  brokers,
+		"group.id":           group,
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka consumer: %w", err)
+	}
+
+	fmt.Println("Connected to Kafka Server", brokers, "as consumer group", group)
+
+	return c, nil
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/internal/service"
+)
+
+// requestIDHeader carries the ID of a request, generated when the caller sends none
+const requestIDHeader = "X-Request-ID"
+
+// auditContext identifies the caller of a request for the audit log. The request ID
+// is echoed back so a caller can find the events of its request.
+func (h *Handler) auditContext(c *gin.Context) {
+	requestID := c.GetHeader(requestIDHeader)
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	c.Header(requestIDHeader, requestID)
+
+	actor := service.AuditActor{
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		RequestID:  requestID,
+	}
+	if h.config.AuditActorHeader != "" {
+		actor.Actor = c.GetHeader(h.config.AuditActorHeader)
+	}
+	c.Request = c.Request.WithContext(service.WithAuditActor(c.Request.Context(), actor))
+	c.Next()
+}
+
+// ListAuditEvents lists the audit log
+// @Summary List audit events
+// @Description List the management operations made through the API, newest first, with who made them and the state of the resource before and after. Signing secrets are redacted.
+// @Tags audit
+// @Produce json
+// @Param action query string false "Only events of this action, e.g. subscription.updated"
+// @Param resource_type query string false "Only events of this resource type: subscription, event_type or delivery"
+// @Param resource_id query string false "Only events of this resource"
+// @Param actor query string false "Only events of this actor"
+// @Param request_id query string false "Only events of this request"
+// @Param since query string false "Only events at or after this time (RFC 3339)"
+// @Param until query string false "Only events before this time (RFC 3339)"
+// @Param limit query int false "Limit results (default 100)"
+// @Success 200 {array} models.AuditEvent
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /audit [get]
+func (h *Handler) ListAuditEvents(c *gin.Context) {
+	var req models.AuditEventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.WithError(err).Warn("Invalid audit events request")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + err.Error()})
+		return
+	}
+
+	events, err := h.service.ListAuditEvents(c.Request.Context(), req)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list audit events")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list audit events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
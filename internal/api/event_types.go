@@ -0,0 +1,167 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/internal/service"
+)
+
+// SchemaErrorResponse is returned when a payload does not match its event type's schema
+type SchemaErrorResponse struct {
+	Error      string                   `json:"error"`
+	EventType  string                   `json:"event_type"`
+	Version    int                      `json:"version"`
+	Violations []models.SchemaViolation `json:"violations"`
+}
+
+// CreateEventType registers an event type in the catalog
+// @Summary Register an event type
+// @Description Register an event type with a description, the JSON Schema of its payload as version 1 and example payloads. Examples must match the schema.
+// @Tags event-types
+// @Accept json
+// @Produce json
+// @Param event_type body models.EventTypeRequest true "Event type"
+// @Success 201 {object} models.EventType
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /event-types [post]
+func (h *Handler) CreateEventType(c *gin.Context) {
+	var req models.EventTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Warn("Invalid event type request")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + err.Error()})
+		return
+	}
+
+	eventType, err := h.service.CreateEventType(c.Request.Context(), req)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to create event type")
+		var validationErr *service.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + validationErr.Message})
+		case errors.Is(err, service.ErrEventTypeExists):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Event type already exists"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create event type"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, eventType)
+}
+
+// AddEventTypeVersion adds a schema version to an event type
+// @Summary Add an event type schema version
+// @Description Add the next schema version of an event type. Payloads are validated against the latest version.
+// @Tags event-types
+// @Accept json
+// @Produce json
+// @Param name path string true "Event type name"
+// @Param version body models.EventTypeVersionRequest true "Schema version"
+// @Success 201 {object} models.EventTypeVersion
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /event-types/{name}/versions [post]
+func (h *Handler) AddEventTypeVersion(c *gin.Context) {
+	var req models.EventTypeVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Warn("Invalid event type version request")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + err.Error()})
+		return
+	}
+
+	version, err := h.service.AddEventTypeVersion(c.Request.Context(), c.Param("name"), req)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to add event type version")
+		var validationErr *service.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + validationErr.Message})
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Event type not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to add event type version"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, version)
+}
+
+// GetEventType gets an event type with all of its schema versions
+// @Summary Get an event type
+// @Description Get an event type with all of its schema versions and examples, newest first
+// @Tags event-types
+// @Produce json
+// @Param name path string true "Event type name"
+// @Success 200 {object} models.EventType
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /event-types/{name} [get]
+func (h *Handler) GetEventType(c *gin.Context) {
+	eventType, err := h.service.GetEventType(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get event type")
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Event type not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get event type"})
+		return
+	}
+
+	c.JSON(http.StatusOK, eventType)
+}
+
+// ListEventTypes lists the event type catalog
+// @Summary List event types
+// @Description List the event types subscriptions can subscribe to, with their latest schema version
+// @Tags event-types
+// @Produce json
+// @Success 200 {array} models.EventType
+// @Failure 500 {object} ErrorResponse
+// @Router /event-types [get]
+func (h *Handler) ListEventTypes(c *gin.Context) {
+	eventTypes, err := h.service.ListEventTypes(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list event types")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list event types"})
+		return
+	}
+
+	c.JSON(http.StatusOK, eventTypes)
+}
+
+// payloadError writes the response for a payload rejected by the service and reports
+// whether err was such a rejection
+func payloadError(c *gin.Context, err error) bool {
+	if payloadTooLarge(c, err) {
+		return true
+	}
+
+	var schemaErr *service.SchemaValidationError
+	if errors.As(err, &schemaErr) {
+		c.JSON(http.StatusUnprocessableEntity, SchemaErrorResponse{
+			Error:      "Payload does not match the event type schema",
+			EventType:  schemaErr.EventType,
+			Version:    schemaErr.Version,
+			Violations: schemaErr.Violations,
+		})
+		return true
+	}
+
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + validationErr.Message})
+		return true
+	}
+	return false
+}
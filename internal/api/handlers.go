@@ -1,15 +1,26 @@
 package api
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	"github.com/Unic-X/webhook-delivery/internal/archive"
+	"github.com/Unic-X/webhook-delivery/internal/config"
 	_ "github.com/Unic-X/webhook-delivery/internal/docs"
 	"github.com/Unic-X/webhook-delivery/internal/models"
 	"github.com/Unic-X/webhook-delivery/internal/service"
@@ -17,18 +28,35 @@ import (
 
 // Handler contains the API handlers and dependencies
 type Handler struct {
-	service service.Service
-	logger  *logrus.Logger
+	service     service.Service
+	config      *config.Config
+	logger      *logrus.Logger
+	idempotency *redis.Client
+
+	// streams is cancelled on shutdown to end the open SSE streams, which otherwise
+	// only end when their client disconnects
+	streams     context.Context
+	stopStreams context.CancelFunc
 }
 
 // NewHandler creates a new Handler
-func NewHandler(service service.Service, logger *logrus.Logger) *Handler {
+func NewHandler(service service.Service, cfg *config.Config, logger *logrus.Logger) *Handler {
+	streams, stopStreams := context.WithCancel(context.Background())
 	return &Handler{
-		service: service,
-		logger:  logger,
+		service:     service,
+		config:      cfg,
+		logger:      logger,
+		streams:     streams,
+		stopStreams: stopStreams,
 	}
 }
 
+// CloseStreams ends the open SSE streams so that a graceful shutdown does not wait
+// for their clients to disconnect
+func (h *Handler) CloseStreams() {
+	h.stopStreams()
+}
+
 // SetupRoutes sets up the API routes
 // @title Webhook Delivery Service API
 // @version 1.0
@@ -36,7 +64,7 @@ func NewHandler(service service.Service, logger *logrus.Logger) *Handler {
 // @host localhost:8080
 // @BasePath /api/v1
 func (h *Handler) SetupRoutes(router *gin.Engine) {
-	r := router.Group("/")
+	r := router.Group("/", h.auditContext)
 	{
 		// Subscriptions
 		subs := r.Group("/subscriptions")
@@ -48,16 +76,44 @@ func (h *Handler) SetupRoutes(router *gin.Engine) {
 			subs.PUT("/:id", h.UpdateSubscription)
 			subs.DELETE("/:id", h.DeleteSubscription)
 			subs.GET("/:id/deliveries", h.GetSubscriptionDeliveries)
+			subs.GET("/:id/deliveries/stream", h.StreamSubscriptionDeliveries)
+			subs.GET("/:id/stats", h.GetSubscriptionStats)
+			subs.POST("/:id/secret/reveal", h.RevealSubscriptionSecret)
+			subs.POST("/:id/secret/rotate", h.RotateSubscriptionSecret)
+		}
+
+		// Event type catalog
+		eventTypes := r.Group("/event-types")
+		{
+			eventTypes.POST("/", h.CreateEventType)
+			eventTypes.GET("/", h.ListEventTypes)
+			eventTypes.GET("/:name", h.GetEventType)
+			eventTypes.POST("/:name/versions", h.AddEventTypeVersion)
 		}
 
 		// Webhooks
 		webhooks := r.Group("/webhooks")
 		{
-			webhooks.POST("/ingest/:subscription_id", h.IngestWebhook)
+			webhooks.POST("/ingest/:subscription_id", h.limitBody, h.idempotent, h.IngestWebhook)
+			webhooks.POST("/publish", h.limitBody, h.idempotent, h.PublishEvent)
 			webhooks.GET("/deliveries/:id", h.GetDeliveryStatus)
+			webhooks.POST("/deliveries/replay", h.ReplayDeliveries)
+			webhooks.POST("/deliveries/:id/replay", h.ReplayDelivery)
+			webhooks.GET("/deliveries/scheduled", h.ListScheduledDeliveries)
+			webhooks.POST("/deliveries/:id/cancel", h.CancelDelivery)
+			webhooks.GET("/archive/:day", h.RestoreArchive)
 		}
+
+		// Audit log
+		r.GET("/audit", h.ListAuditEvents)
 	}
 
+	// Public keys of the ed25519 signature scheme
+	router.GET("/.well-known/webhook-keys", h.GetWebhookKeys)
+
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -68,12 +124,12 @@ func (h *Handler) SetupRoutes(router *gin.Engine) {
 
 // CreateSubscription creates a new webhook subscription
 // @Summary Create a new webhook subscription
-// @Description Create a new webhook subscription with the provided details
+// @Description Create a new webhook subscription with the provided details. A signing secret is generated when none is given. The secret is only returned in this response.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param subscription body models.SubscriptionRequest true "Subscription details"
-// @Success 201 {object} models.Subscription
+// @Success 201 {object} models.CreatedSubscription
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /subscriptions [post]
@@ -87,12 +143,21 @@ func (h *Handler) CreateSubscription(c *gin.Context) {
 
 	subscription, err := h.service.CreateSubscription(c.Request.Context(), req)
 	if err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + validationErr.Message})
+			return
+		}
 		h.logger.WithError(err).Error("Failed to create subscription")
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create subscription"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, subscription)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, models.CreatedSubscription{
+		Subscription: subscription,
+		SecretKey:    subscription.SecretKey,
+	})
 }
 
 // GetSubscription retrieves a webhook subscription by ID
@@ -154,6 +219,11 @@ func (h *Handler) UpdateSubscription(c *gin.Context) {
 
 	subscription, err := h.service.UpdateSubscription(c.Request.Context(), id, req)
 	if err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + validationErr.Message})
+			return
+		}
 		h.logger.WithError(err).Error("Failed to update subscription")
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Subscription not found or update failed"})
 		return
@@ -210,17 +280,21 @@ func (h *Handler) ListSubscriptions(c *gin.Context) {
 
 // IngestWebhook ingests a webhook for delivery
 // @Summary Ingest a webhook
-// @Description Ingest a webhook payload for a subscription
+// @Description Ingest a webhook payload for a subscription. Set deliver_at or delay to deliver it later, and expires_at to drop it if it cannot be delivered in time.
 // @Tags webhooks
 // @Accept json
 // @Produce json
 // @Param subscription_id path string true "Subscription ID"
 // @Param X-Event-Type header string false "Event Type"
 // @Param X-Hub-Signature-256 header string false "Webhook Signature"
+// @Param Idempotency-Key header string false "Replays the stored response when the key is repeated"
 // @Param payload body models.WebhookRequest true "Webhook payload"
 // @Success 202 {object} SuccessResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 422 {object} SchemaErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /webhooks/ingest/{subscription_id} [post]
 func (h *Handler) IngestWebhook(c *gin.Context) {
@@ -235,22 +309,34 @@ func (h *Handler) IngestWebhook(c *gin.Context) {
 	var reqBody models.WebhookRequest
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
 		h.logger.WithError(err).Warn("Invalid webhook payload")
+		if payloadTooLarge(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid webhook payload"})
 		return
 	}
 
+	schedule, err := reqBody.Schedule(time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + err.Error()})
+		return
+	}
+
 	// Get headers
 	eventType := c.GetHeader("X-Event-Type")
 	signature := c.GetHeader("X-Hub-Signature-256")
 
 	// Process the webhook
-	err = h.service.IngestWebhook(c.Request.Context(), id, eventType, reqBody.Payload, signature)
+	err = h.service.IngestWebhook(c.Request.Context(), id, eventType, reqBody.Payload, signature, schedule)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to ingest webhook")
 		if err.Error() == "invalid signature" {
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid signature"})
 			return
 		}
+		if validationError(c, err) || payloadError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process webhook"})
 		return
 	}
@@ -258,6 +344,66 @@ func (h *Handler) IngestWebhook(c *gin.Context) {
 	c.JSON(http.StatusAccepted, SuccessResponse{Message: "Webhook accepted for processing"})
 }
 
+// PublishEvent fans an event out to all matching subscriptions
+// @Summary Publish an event
+// @Description Queue an event for delivery to every subscription that accepts its event type. Set deliver_at or delay to deliver it later, and expires_at to drop it if it cannot be delivered in time.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Event-Type header string true "Event Type"
+// @Param Idempotency-Key header string false "Replays the stored response when the key is repeated"
+// @Param payload body models.WebhookRequest true "Event payload"
+// @Success 202 {object} models.PublishResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 422 {object} SchemaErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks/publish [post]
+func (h *Handler) PublishEvent(c *gin.Context) {
+	eventType := c.GetHeader("X-Event-Type")
+	if eventType == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "X-Event-Type header is required"})
+		return
+	}
+
+	var reqBody models.WebhookRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		h.logger.WithError(err).Warn("Invalid event payload")
+		if payloadTooLarge(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid event payload"})
+		return
+	}
+
+	schedule, err := reqBody.Schedule(time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + err.Error()})
+		return
+	}
+
+	deliveries, err := h.service.PublishEvent(c.Request.Context(), eventType, reqBody.Payload, schedule)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to publish event")
+		if validationError(c, err) || payloadError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to publish event"})
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.ID)
+	}
+
+	c.JSON(http.StatusAccepted, models.PublishResponse{
+		Message:     "Event accepted for processing",
+		DeliveryIDs: ids,
+	})
+}
+
 // GetDeliveryStatus gets the status of a webhook delivery
 // @Summary Get webhook delivery status
 // @Description Get the status and attempt history of a webhook delivery
@@ -287,6 +433,213 @@ func (h *Handler) GetDeliveryStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
+// ReplayDelivery queues a failed delivery for delivery again
+// @Summary Replay a failed delivery
+// @Description Queue a delivery in the FAILED state for delivery again with a fresh retry budget
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Success 202 {object} models.ReplayResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks/deliveries/{id}/replay [post]
+func (h *Handler) ReplayDelivery(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Warn("Invalid delivery ID")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid delivery ID"})
+		return
+	}
+
+	delivery, err := h.service.ReplayDelivery(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to replay delivery")
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Delivery not found"})
+		case errors.Is(err, service.ErrNotReplayable):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Only failed deliveries can be replayed"})
+		case errors.Is(err, service.ErrPayloadPurged):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "The delivery's payload was removed by the retention policy"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to replay delivery"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.ReplayResponse{
+		Message:     "Delivery queued for replay",
+		DeliveryIDs: []uuid.UUID{delivery.ID},
+	})
+}
+
+// ReplayDeliveries queues the failed deliveries matching a filter for delivery again
+// @Summary Replay failed deliveries
+// @Description Queue failed deliveries matching the filter for delivery again, oldest first. At most 'limit' deliveries (default 100) are replayed per request.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param filter body models.ReplayRequest true "Replay filter"
+// @Success 202 {object} models.ReplayResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks/deliveries/replay [post]
+func (h *Handler) ReplayDeliveries(c *gin.Context) {
+	var req models.ReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Warn("Invalid replay request")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + err.Error()})
+		return
+	}
+
+	deliveries, err := h.service.ReplayDeliveries(c.Request.Context(), req)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to replay deliveries")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to replay deliveries"})
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.ID)
+	}
+
+	c.JSON(http.StatusAccepted, models.ReplayResponse{
+		Message:     fmt.Sprintf("%d deliveries queued for replay", len(ids)),
+		DeliveryIDs: ids,
+	})
+}
+
+// ListScheduledDeliveries lists the deliveries waiting for their scheduled time
+// @Summary List scheduled deliveries
+// @Description List the deliveries waiting for their deliver_at time, the next to fire first. At most 'limit' deliveries (default 100) are returned.
+// @Tags webhooks
+// @Produce json
+// @Param subscription_id query string false "Only list deliveries of this subscription"
+// @Param limit query int false "Limit results (default 100)"
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks/deliveries/scheduled [get]
+func (h *Handler) ListScheduledDeliveries(c *gin.Context) {
+	var req models.ScheduledDeliveriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.WithError(err).Warn("Invalid scheduled deliveries request")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + err.Error()})
+		return
+	}
+
+	deliveries, err := h.service.ListScheduledDeliveries(c.Request.Context(), req)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list scheduled deliveries")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list scheduled deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// CancelDelivery cancels a scheduled delivery
+// @Summary Cancel a scheduled delivery
+// @Description Cancel a delivery in the SCHEDULED state before its deliver_at time
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} models.WebhookDelivery
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks/deliveries/{id}/cancel [post]
+func (h *Handler) CancelDelivery(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Warn("Invalid delivery ID")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid delivery ID"})
+		return
+	}
+
+	delivery, err := h.service.CancelDelivery(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Delivery not found"})
+		case errors.Is(err, service.ErrNotCancellable):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Only scheduled deliveries can be cancelled"})
+		default:
+			h.logger.WithError(err).Error("Failed to cancel delivery")
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to cancel delivery"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// RestoreArchive streams the deliveries archived for a day
+// @Summary Restore archived deliveries
+// @Description Stream the deliveries archived for a day (UTC) before the retention policy removed them, with their attempts, as JSON lines. Offloaded payloads are inlined. The archive is read only, nothing is written back to the database.
+// @Tags webhooks
+// @Produce application/x-ndjson
+// @Param day path string true "Day the deliveries were created, YYYY-MM-DD"
+// @Param subscription_id query string false "Only the deliveries of this subscription"
+// @Success 200 {object} models.ArchivedDelivery
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks/archive/{day} [get]
+func (h *Handler) RestoreArchive(c *gin.Context) {
+	day, err := time.Parse(archive.DayLayout, c.Param("day"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid day, expected YYYY-MM-DD"})
+		return
+	}
+
+	var subscriptionID *uuid.UUID
+	if idStr := c.Query("subscription_id"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid subscription ID"})
+			return
+		}
+		subscriptionID = &id
+	}
+
+	// Errors can only be reported until the first record is written
+	streaming := false
+	enc := json.NewEncoder(c.Writer)
+	err = h.service.RestoreArchive(c.Request.Context(), day, subscriptionID, func(record models.ArchivedDelivery) error {
+		if !streaming {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+			streaming = true
+		}
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err == nil {
+		return
+	}
+	if streaming {
+		h.logger.WithError(err).Error("Failed to stream archived deliveries")
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrArchiveDisabled):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Delivery archiving is not configured"})
+	case errors.Is(err, service.ErrArchiveNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "No archived deliveries for this day"})
+	default:
+		h.logger.WithError(err).Error("Failed to restore archived deliveries")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to restore archived deliveries"})
+	}
+}
+
 // GetSubscriptionDeliveries gets recent deliveries for a subscription
 // @Summary Get recent deliveries
 // @Description Get recent webhook deliveries for a subscription
@@ -324,6 +677,121 @@ func (h *Handler) GetSubscriptionDeliveries(c *gin.Context) {
 	c.JSON(http.StatusOK, deliveries)
 }
 
+// streamHeartbeatInterval is how often a comment is sent on idle event streams so
+// that proxies do not close the connection
+const streamHeartbeatInterval = 15 * time.Second
+
+// StreamSubscriptionDeliveries streams delivery events for a subscription
+// @Summary Stream delivery events
+// @Description Stream delivery state changes and attempt results for a subscription as Server-Sent Events. The SSE event name is the delivery event type.
+// @Tags subscriptions
+// @Produce text/event-stream
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} models.DeliveryEvent
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /subscriptions/{id}/deliveries/stream [get]
+func (h *Handler) StreamSubscriptionDeliveries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Warn("Invalid subscription ID")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid subscription ID"})
+		return
+	}
+
+	if _, err := h.service.GetSubscription(c.Request.Context(), id); err != nil {
+		h.logger.WithError(err).Error("Failed to get subscription")
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Subscription not found"})
+		return
+	}
+
+	events, err := h.service.StreamDeliveryEvents(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to stream delivery events")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to stream delivery events"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-h.streams.Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.Render(-1, sse.Event{
+				Id:    event.ID.String(),
+				Event: event.Type,
+				Data:  event,
+			})
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		}
+	})
+}
+
+// GetSubscriptionStats gets hourly delivery statistics for a subscription
+// @Summary Get delivery statistics
+// @Description Get hourly delivery and attempt counts for a subscription, grouped by event type
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param from query string false "Start of the window in RFC3339 (default 24 hours ago)"
+// @Param to query string false "End of the window in RFC3339 (default now)"
+// @Success 200 {object} models.DeliveryStatsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /subscriptions/{id}/stats [get]
+func (h *Handler) GetSubscriptionStats(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Warn("Invalid subscription ID")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid subscription ID"})
+		return
+	}
+
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		if to, err = time.Parse(time.RFC3339, toStr); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid 'to' timestamp, expected RFC3339"})
+			return
+		}
+	}
+
+	from := to.Add(-24 * time.Hour)
+	if fromStr := c.Query("from"); fromStr != "" {
+		if from, err = time.Parse(time.RFC3339, fromStr); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid 'from' timestamp, expected RFC3339"})
+			return
+		}
+	}
+
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "'from' must be before 'to'"})
+		return
+	}
+
+	stats, err := h.service.GetDeliveryStats(c.Request.Context(), id, from, to)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get delivery stats")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get delivery stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
 // ErrorResponse is the standard error response format
 type ErrorResponse struct {
 	Error string `json:"error"`
@@ -333,3 +801,34 @@ type ErrorResponse struct {
 type SuccessResponse struct {
 	Message string `json:"message"`
 }
+
+// limitBody caps the request body at the maximum ingest size, so oversized payloads
+// are rejected without being read into memory
+func (h *Handler) limitBody(c *gin.Context) {
+	if h.config.MaxIngestBytes > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(h.config.MaxIngestBytes))
+	}
+	c.Next()
+}
+
+// validationError writes a 400 response when err is a service validation error and
+// reports whether it did
+func validationError(c *gin.Context, err error) bool {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + validationErr.Message})
+		return true
+	}
+	return false
+}
+
+// payloadTooLarge writes a 413 response when err is caused by a payload above the
+// maximum ingest size and reports whether it did
+func payloadTooLarge(c *gin.Context, err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || errors.Is(err, service.ErrPayloadTooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Payload exceeds the maximum ingest size"})
+		return true
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+// IdempotencyKeyHeader lets clients retry a request without it taking effect twice
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed for a repeated Idempotency-Key
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength caps the length of an Idempotency-Key
+const maxIdempotencyKeyLength = 255
+
+// idempotentResponse is the response stored for an Idempotency-Key. Status is zero
+// while the first request is still being handled.
+type idempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// bodyRecorder keeps a copy of the response body
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// SetIdempotencyStore enables Idempotency-Key support on the ingest endpoints,
+// remembering responses in Redis for IDEMPOTENCY_KEY_TTL
+func (h *Handler) SetIdempotencyStore(client *redis.Client) {
+	h.idempotency = client
+}
+
+// idempotent replays the stored response when a request repeats an Idempotency-Key,
+// so a client can retry an ingest whose response it did not receive. Responses
+// with a 5xx status are not stored, the request can be retried with the same key.
+// The key is scoped to the route and may only be reused with the same body.
+func (h *Handler) idempotent(c *gin.Context) {
+	key := c.GetHeader(IdempotencyKeyHeader)
+	if key == "" || h.idempotency == nil {
+		c.Next()
+		return
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Error: "Idempotency-Key must be at most 255 characters"})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		if !payloadTooLarge(c, err) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to read request body"})
+		}
+		c.Abort()
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	sum := sha256.Sum256(append([]byte(c.GetHeader("X-Event-Type")+"\n"), body...))
+	fingerprint := hex.EncodeToString(sum[:])
+	ctx := c.Request.Context()
+	redisKey := "idempotency:" + c.FullPath() + ":" + c.Param("subscription_id") + ":" + key
+
+	pending, _ := json.Marshal(idempotentResponse{Fingerprint: fingerprint})
+	claimed, err := h.idempotency.SetNX(ctx, redisKey, pending, h.config.IdempotencyKeyTTL).Result()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to check idempotency key")
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, ErrorResponse{Error: "Failed to check idempotency key"})
+		return
+	}
+
+	if !claimed {
+		h.replayIdempotent(c, redisKey, fingerprint)
+		return
+	}
+
+	// The key must be stored or released even when the client went away, otherwise it
+	// stays pending and every retry is rejected until it expires. It is also released
+	// when the handler panics.
+	ctx = context.WithoutCancel(ctx)
+	handled := false
+	defer func() {
+		if handled {
+			return
+		}
+		if err := h.idempotency.Del(ctx, redisKey).Err(); err != nil {
+			h.logger.WithError(err).Error("Failed to release idempotency key")
+		}
+	}()
+
+	recorder := &bodyRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Next()
+
+	status := c.Writer.Status()
+	if status >= http.StatusInternalServerError {
+		return
+	}
+	// The request took effect, a failed store must not release the key for a retry
+	handled = true
+
+	response, _ := json.Marshal(idempotentResponse{
+		Fingerprint: fingerprint,
+		Status:      status,
+		ContentType: c.Writer.Header().Get("Content-Type"),
+		Body:        recorder.body.Bytes(),
+	})
+	if err := h.idempotency.Set(ctx, redisKey, response, h.config.IdempotencyKeyTTL).Err(); err != nil {
+		h.logger.WithError(err).Error("Failed to store idempotent response")
+	}
+}
+
+// replayIdempotent answers a request that repeats an Idempotency-Key
+func (h *Handler) replayIdempotent(c *gin.Context, redisKey, fingerprint string) {
+	defer c.Abort()
+
+	data, err := h.idempotency.Get(c.Request.Context(), redisKey).Bytes()
+	if err == redis.Nil {
+		// The first request failed and released the key in the meantime
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "A request with this Idempotency-Key is being processed, retry later"})
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get idempotent response")
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "Failed to check idempotency key"})
+		return
+	}
+
+	var stored idempotentResponse
+	if err := json.Unmarshal(data, &stored); err != nil {
+		h.logger.WithError(err).Error("Failed to decode idempotent response")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to check idempotency key"})
+		return
+	}
+
+	switch {
+	case stored.Fingerprint != fingerprint:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: "Idempotency-Key was already used with a different request"})
+	case stored.Status == 0:
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "A request with this Idempotency-Key is being processed, retry later"})
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(stored.Status, stored.ContentType, stored.Body)
+	}
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Unic-X/webhook-delivery/pkg/signature"
+	"github.com/gin-gonic/gin"
+)
+
+// GetWebhookKeys publishes the public keys deliveries are signed with
+// @Summary Get the webhook signing keys
+// @Description Get the Ed25519 public keys deliveries of the ed25519 signature scheme are signed with, as a JSON Web Key Set. During a rotation the previous keys are listed after the current one, and deliveries carry a signature from each key.
+// @Tags keys
+// @Produce json
+// @Success 200 {object} signature.JWKS
+// @Router /.well-known/webhook-keys [get]
+func (h *Handler) GetWebhookKeys(c *gin.Context) {
+	var keys signature.JWKS = h.service.PublicSigningKeys()
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keys)
+}
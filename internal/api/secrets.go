@@ -0,0 +1,140 @@
+package api
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/internal/service"
+)
+
+// authorizeSecretReveal checks the bearer token of a secret reveal request against
+// SECRET_REVEAL_TOKEN. Reveals are disabled when no token is configured.
+func (h *Handler) authorizeSecretReveal(c *gin.Context) bool {
+	if h.config.SecretRevealToken == "" {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Secret reveal is disabled"})
+		return false
+	}
+
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.config.SecretRevealToken)) != 1 {
+		h.logger.WithField("remote_addr", c.ClientIP()).Warn("Unauthorized secret reveal request")
+		c.Header("WWW-Authenticate", "Bearer")
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return false
+	}
+	return true
+}
+
+// RevealSubscriptionSecret returns a subscription's signing secret
+// @Summary Reveal a subscription's signing secret
+// @Description Return the signing secret of a subscription. Requires the SECRET_REVEAL_TOKEN bearer token and a reason, every reveal is written to the audit log.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param Authorization header string true "Bearer token"
+// @Param request body models.RevealSecretRequest true "Reveal reason"
+// @Success 200 {object} models.RevealSecretResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /subscriptions/{id}/secret/reveal [post]
+func (h *Handler) RevealSubscriptionSecret(c *gin.Context) {
+	if !h.authorizeSecretReveal(c) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Warn("Invalid subscription ID")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid subscription ID"})
+		return
+	}
+
+	var req models.RevealSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Warn("Invalid secret reveal request")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + err.Error()})
+		return
+	}
+
+	secret, err := h.service.RevealSubscriptionSecret(c.Request.Context(), id, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Subscription not found"})
+		case errors.Is(err, service.ErrNoSecretKey):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Subscription has no signing secret"})
+		default:
+			h.logger.WithError(err).WithField("subscription_id", id).Error("Failed to reveal subscription secret")
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to reveal subscription secret"})
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, models.RevealSecretResponse{SubscriptionID: id, SecretKey: secret})
+}
+
+// RotateSubscriptionSecret replaces a subscription's signing secret
+// @Summary Rotate a subscription's signing secret
+// @Description Replace the signing secret of a subscription. The new secret is generated unless one is given, and is only returned in this response. The old secret stays valid for the overlap, during which deliveries carry a signature from each secret in X-Hub-Signature-256, separated by spaces.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param request body models.RotateSecretRequest false "New secret and overlap"
+// @Success 200 {object} models.RotateSecretResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /subscriptions/{id}/secret/rotate [post]
+func (h *Handler) RotateSubscriptionSecret(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Warn("Invalid subscription ID")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid subscription ID"})
+		return
+	}
+
+	// The body is optional, an empty request generates a secret with the default overlap
+	var req models.RotateSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.WithError(err).Warn("Invalid secret rotation request")
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + err.Error()})
+		return
+	}
+
+	subscription, err := h.service.RotateSubscriptionSecret(c.Request.Context(), id, req)
+	if err != nil {
+		var validationErr *service.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request: " + validationErr.Message})
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Subscription not found"})
+		default:
+			h.logger.WithError(err).WithField("subscription_id", id).Error("Failed to rotate subscription secret")
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to rotate subscription secret"})
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, models.RotateSecretResponse{
+		SubscriptionID:          subscription.ID,
+		SecretKey:               *subscription.SecretKey,
+		SecretKeyHint:           subscription.SecretKeyHint,
+		PreviousSecretExpiresAt: subscription.PreviousSecretExpiresAt,
+	})
+}
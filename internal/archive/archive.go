@@ -0,0 +1,125 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/config"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// Sink kinds accepted in ARCHIVE_SINK
+const (
+	KindNone       = "none"
+	KindFilesystem = "filesystem"
+)
+
+// DayLayout is the format of the day part of archive keys
+const DayLayout = "2006-01-02"
+
+// ErrNotFound is returned when an archive file does not exist
+var ErrNotFound = errors.New("archive file not found")
+
+// Sink holds archive files. Files are written once under a key of the form
+// DAY/SUBSCRIPTION_ID/NAME.jsonl.gz and never modified in place.
+type Sink interface {
+	// Put writes the content of r under key, replacing any file already there
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open opens the file under key for reading
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// List returns the keys of the files under a key prefix, in lexical order
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Open returns the Sink configured by ARCHIVE_SINK, or nil when archiving is disabled
+func Open(cfg *config.Config) (Sink, error) {
+	switch cfg.ArchiveSink {
+	case "", KindNone:
+		return nil, nil
+	case KindFilesystem:
+		sink, err := NewFilesystem(cfg.ArchiveDir)
+		if err != nil {
+			return nil, err
+		}
+		return sink, nil
+	default:
+		return nil, fmt.Errorf("unknown archive sink %q", cfg.ArchiveSink)
+	}
+}
+
+// Key returns the key of an archive file of a subscription's deliveries created on a
+// day, in UTC
+func Key(day time.Time, subscriptionID uuid.UUID, name string) string {
+	return path.Join(Prefix(day, &subscriptionID), name+".jsonl.gz")
+}
+
+// Prefix returns the key prefix of the archive files of a day, limited to one
+// subscription when subscriptionID is set
+func Prefix(day time.Time, subscriptionID *uuid.UUID) string {
+	prefix := day.UTC().Format(DayLayout)
+	if subscriptionID != nil {
+		prefix = path.Join(prefix, subscriptionID.String())
+	}
+	return prefix
+}
+
+// Write writes records to the sink under key as gzipped JSON lines
+func Write(ctx context.Context, sink Sink, key string, records []models.ArchivedDelivery) error {
+	pr, pw := io.Pipe()
+	go func() {
+		gz := gzip.NewWriter(pw)
+		enc := json.NewEncoder(gz)
+		for i := range records {
+			if err := enc.Encode(&records[i]); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.CloseWithError(gz.Close())
+	}()
+
+	err := sink.Put(ctx, key, pr)
+	// Unblocks the encoder when Put returned before reading everything
+	pr.CloseWithError(err)
+	return err
+}
+
+// Reader reads the records of an archive file
+type Reader struct {
+	gz  *gzip.Reader
+	dec *json.Decoder
+}
+
+// NewReader returns a Reader of the gzipped JSON lines read from r
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive file: %w", err)
+	}
+	return &Reader{gz: gz, dec: json.NewDecoder(gz)}, nil
+}
+
+// Next returns the next record, or io.EOF at the end of the file
+func (r *Reader) Next() (models.ArchivedDelivery, error) {
+	var record models.ArchivedDelivery
+	if err := r.dec.Decode(&record); err != nil {
+		if err == io.EOF {
+			return record, io.EOF
+		}
+		return record, fmt.Errorf("failed to read archive record: %w", err)
+	}
+	return record, nil
+}
+
+// Close releases the decompressor. It does not close the underlying reader.
+func (r *Reader) Close() error {
+	return r.gz.Close()
+}
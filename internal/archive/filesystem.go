@@ -0,0 +1,117 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Filesystem stores archive files under a root directory
+type Filesystem struct {
+	root string
+}
+
+// NewFilesystem creates a Filesystem sink rooted at dir, creating the directory if needed
+func NewFilesystem(dir string) (*Filesystem, error) {
+	if dir == "" {
+		return nil, errors.New("archive directory is not set")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	return &Filesystem{root: dir}, nil
+}
+
+// Put implements Sink. The file is written to a temporary file and renamed into
+// place, so readers never see a partial file.
+func (f *Filesystem) Put(_ context.Context, key string, r io.Reader) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync archive file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store archive file: %w", err)
+	}
+	return nil
+}
+
+// Open implements Sink
+func (f *Filesystem) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// List implements Sink. The prefix is a directory, e.g. a day or a day and
+// subscription; a missing directory has no files.
+func (f *Filesystem) List(_ context.Context, prefix string) ([]string, error) {
+	dir, err := f.path(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(f.root, path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive files: %w", err)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// path maps a key to a file under the root, rejecting keys that would escape it
+func (f *Filesystem) path(key string) (string, error) {
+	if key == "" || !filepath.IsLocal(key) || strings.Contains(key, `\`) {
+		return "", fmt.Errorf("invalid archive key %q", key)
+	}
+	return filepath.Join(f.root, filepath.FromSlash(key)), nil
+}
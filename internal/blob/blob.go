@@ -0,0 +1,105 @@
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/Unic-X/webhook-delivery/internal/config"
+)
+
+// Store kinds accepted in BLOB_STORE
+const (
+	KindNone       = "none"
+	KindFilesystem = "filesystem"
+)
+
+// ErrNotFound is returned when a blob does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// ErrHashMismatch is returned when a blob's content does not match its hash
+var ErrHashMismatch = errors.New("blob content does not match its hash")
+
+// Store holds payloads that are too large to keep inline in the database.
+// Blobs are immutable once written and addressed by a store-specific reference.
+type Store interface {
+	// Put writes the content of r under key and returns its reference
+	Put(ctx context.Context, key string, r io.Reader) (string, error)
+	// Open opens the blob with the given reference for reading
+	Open(ctx context.Context, ref string) (io.ReadCloser, error)
+	// Delete removes the blob with the given reference. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, ref string) error
+}
+
+// Open returns the Store configured by BLOB_STORE, or nil when offloading is disabled
+func Open(cfg *config.Config) (Store, error) {
+	switch cfg.BlobStore {
+	case "", KindNone:
+		return nil, nil
+	case KindFilesystem:
+		store, err := NewFilesystem(cfg.BlobDir)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", cfg.BlobStore)
+	}
+}
+
+// Hash returns the hex encoded SHA-256 of data
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ContentKey returns the key of content-addressed data with the given hash, so
+// identical payloads share a single blob
+func ContentKey(hash string) string {
+	return "sha256/" + hash[:2] + "/" + hash
+}
+
+// sealedPrefix starts the keys of encrypted blobs
+const sealedPrefix = "sealed/"
+
+// SealedContentKey returns the key of encrypted data with the given hash, which is
+// the hash of the ciphertext
+func SealedContentKey(hash string) string {
+	return sealedPrefix + ContentKey(hash)
+}
+
+// IsSealed reports whether a blob reference returned for a SealedContentKey names
+// encrypted data
+func IsSealed(ref string) bool {
+	return strings.HasPrefix(ref, sealedPrefix)
+}
+
+// VerifyingReader wraps a blob reader and fails with ErrHashMismatch at EOF when
+// the content read does not match the expected hash
+func VerifyingReader(rc io.ReadCloser, expected string) io.ReadCloser {
+	return &verifyingReader{rc: rc, hash: sha256.New(), expected: expected}
+}
+
+type verifyingReader struct {
+	rc       io.ReadCloser
+	hash     hash.Hash
+	expected string
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.rc.Read(p)
+	v.hash.Write(p[:n])
+	if err == io.EOF && hex.EncodeToString(v.hash.Sum(nil)) != v.expected {
+		return n, ErrHashMismatch
+	}
+	return n, err
+}
+
+func (v *verifyingReader) Close() error {
+	return v.rc.Close()
+}
@@ -0,0 +1,97 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Filesystem stores blobs as files under a root directory. The API and worker must
+// share the directory, e.g. through a common volume.
+type Filesystem struct {
+	root string
+}
+
+// NewFilesystem creates a Filesystem store rooted at dir, creating the directory if needed
+func NewFilesystem(dir string) (*Filesystem, error) {
+	if dir == "" {
+		return nil, errors.New("blob directory is not set")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	return &Filesystem{root: dir}, nil
+}
+
+// Put implements Store. The blob is written to a temporary file and renamed into
+// place, so readers never see a partial blob.
+func (f *Filesystem) Put(_ context.Context, key string, r io.Reader) (string, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to sync blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to store blob: %w", err)
+	}
+	return key, nil
+}
+
+// Open implements Store
+func (f *Filesystem) Open(_ context.Context, ref string) (io.ReadCloser, error) {
+	path, err := f.path(ref)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// Delete implements Store
+func (f *Filesystem) Delete(_ context.Context, ref string) error {
+	path, err := f.path(ref)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps a reference to a file under the root, rejecting references that
+// would escape it
+func (f *Filesystem) path(ref string) (string, error) {
+	if ref == "" || !filepath.IsLocal(ref) || strings.Contains(ref, `\`) {
+		return "", fmt.Errorf("invalid blob reference %q", ref)
+	}
+	return filepath.Join(f.root, filepath.FromSlash(ref)), nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -12,6 +13,7 @@ import (
 // Config holds all configuration for the application
 type Config struct {
 	Port              string
+	GRPCPort          string
 	Environment       string
 	PostgresHost      string
 	PostgresPort      string
@@ -26,6 +28,63 @@ type Config struct {
 	RetryLimit        int
 	LogRetentionHours int
 	RetryDelays       []time.Duration
+	TracingExporter   string
+	KafkaBrokers      string
+
+	// Kafka ingestion
+	KafkaIngestTopics       []string
+	KafkaConsumerGroup      string
+	KafkaEventTypeHeader    string
+	KafkaEventTypeField     string
+	KafkaSubscriptionHeader string
+
+	// Payload storage
+	MaxIngestBytes      int
+	PayloadOffloadBytes int
+	BlobStore           string
+	BlobDir             string
+
+	// How long responses are kept for Idempotency-Key replays
+	IdempotencyKeyTTL time.Duration
+
+	// How far ahead deliveries can be scheduled
+	MaxScheduleAhead time.Duration
+
+	// Delivery retention by terminal status, zero keeps deliveries forever
+	DeliveryRetentionDelivered time.Duration
+	DeliveryRetentionFailed    time.Duration
+	DeliveryRetentionCancelled time.Duration
+	DeliveryRetentionExpired   time.Duration
+	DeliveryRetentionMode      string
+	RetentionBatchSize         int
+
+	// Days covered by each partition of webhook_deliveries and delivery_attempts, and
+	// how far ahead partitions are created
+	PartitionDays        int
+	PartitionPremakeDays int
+
+	// Where deliveries are archived before retention removes them
+	ArchiveSink string
+	ArchiveDir  string
+
+	// Encryption at rest
+	EncryptionKeys    []string
+	EncryptionKeyFile string
+
+	// Secrets
+	SecretRevealToken     string
+	SecretRotationOverlap time.Duration
+
+	// Request header the authenticating proxy passes the caller's identity in, recorded
+	// as the actor of audit events
+	AuditActorHeader string
+	// Addresses or CIDRs of the proxies whose X-Forwarded-For is trusted for the client
+	// address, none by default
+	TrustedProxies []string
+
+	// Ed25519 signing
+	SigningKeys    []string
+	SigningKeyFile string
 }
 
 // Load loads the configuration from environment variables
@@ -34,6 +93,7 @@ func Load() (*Config, error) {
 
 	cfg := &Config{
 		Port:              getEnv("PORT", "8080"),
+		GRPCPort:          getEnv("GRPC_PORT", "9000"),
 		Environment:       getEnv("ENV", "development"),
 		PostgresHost:      getEnv("POSTGRES_HOST", "localhost"),
 		PostgresPort:      getEnv("POSTGRES_PORT", "5432"),
@@ -46,6 +106,49 @@ func Load() (*Config, error) {
 		WorkerConcurrency: getEnvAsInt("WORKER_CONCURRENCY", 10),
 		RetryLimit:        getEnvAsInt("RETRY_LIMIT", 5),
 		LogRetentionHours: getEnvAsInt("LOG_RETENTION_HOURS", 72),
+		TracingExporter:   getEnv("TRACING_EXPORTER", "none"),
+		KafkaBrokers:      getEnv("KAFKA_BROKERS", ""),
+
+		KafkaIngestTopics:       getEnvAsSlice("KAFKA_INGEST_TOPICS", nil),
+		KafkaConsumerGroup:      getEnv("KAFKA_CONSUMER_GROUP", "webhook-ingestor"),
+		KafkaEventTypeHeader:    getEnv("KAFKA_EVENT_TYPE_HEADER", "event_type"),
+		KafkaEventTypeField:     getEnv("KAFKA_EVENT_TYPE_FIELD", "type"),
+		KafkaSubscriptionHeader: getEnv("KAFKA_SUBSCRIPTION_HEADER", "subscription_id"),
+
+		MaxIngestBytes:      getEnvAsInt("MAX_INGEST_BYTES", 10<<20),
+		PayloadOffloadBytes: getEnvAsInt("PAYLOAD_OFFLOAD_BYTES", 256<<10),
+		BlobStore:           getEnv("BLOB_STORE", "none"),
+		BlobDir:             getEnv("BLOB_DIR", "data/blobs"),
+
+		IdempotencyKeyTTL: getEnvAsDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+
+		MaxScheduleAhead: getEnvAsDuration("MAX_SCHEDULE_AHEAD", 30*24*time.Hour),
+
+		DeliveryRetentionDelivered: getEnvAsDuration("DELIVERY_RETENTION_DELIVERED", 0),
+		DeliveryRetentionFailed:    getEnvAsDuration("DELIVERY_RETENTION_FAILED", 0),
+		DeliveryRetentionCancelled: getEnvAsDuration("DELIVERY_RETENTION_CANCELLED", 0),
+		DeliveryRetentionExpired:   getEnvAsDuration("DELIVERY_RETENTION_EXPIRED", 0),
+		DeliveryRetentionMode:      getEnv("DELIVERY_RETENTION_MODE", "delete"),
+		RetentionBatchSize:         getEnvAsInt("RETENTION_BATCH_SIZE", 1000),
+
+		PartitionDays:        getEnvAsInt("PARTITION_DAYS", 1),
+		PartitionPremakeDays: getEnvAsInt("PARTITION_PREMAKE_DAYS", 7),
+
+		ArchiveSink: getEnv("ARCHIVE_SINK", "none"),
+		ArchiveDir:  getEnv("ARCHIVE_DIR", "data/archive"),
+
+		EncryptionKeys:    getEnvAsSlice("ENCRYPTION_KEYS", nil),
+		EncryptionKeyFile: getEnv("ENCRYPTION_KEY_FILE", ""),
+
+		SecretRevealToken:     getEnv("SECRET_REVEAL_TOKEN", ""),
+		SecretRotationOverlap: getEnvAsDuration("SECRET_ROTATION_OVERLAP", 24*time.Hour),
+
+		AuditActorHeader: getEnv("AUDIT_ACTOR_HEADER", "X-Forwarded-User"),
+		TrustedProxies:   getEnvAsSlice("TRUSTED_PROXIES", nil),
+
+		SigningKeys:    getEnvAsSlice("SIGNING_KEYS", nil),
+		SigningKeyFile: getEnv("SIGNING_KEY_FILE", ""),
+
 		// Default retry delays with exponential backoff: 10s, 30s, 1m, 5m, 15m
 		RetryDelays: []time.Duration{
 			10 * time.Second,
@@ -81,3 +184,32 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return value
 }
+
+// Helper function to get an environment variable as a duration, e.g. "24h"
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// Helper function to get an environment variable as a comma separated list
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, v := range strings.Split(valueStr, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
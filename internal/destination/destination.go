@@ -0,0 +1,100 @@
+package destination
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// Result is the outcome of sending a delivery to its destination
+type Result struct {
+	// StatusCode is the response status code, for destinations that have one
+	StatusCode *int
+	// Err is set when the delivery failed and is recorded on the attempt
+	Err error
+}
+
+// Destination sends a delivery's payload to the place a subscription points at.
+// Attempt recording, retries and dead-lettering are shared by the caller, so a
+// transport only implements the send step.
+type Destination interface {
+	Deliver(ctx context.Context, delivery *models.WebhookDelivery, subscription *models.Subscription, payload Payload) Result
+}
+
+// Payload is the body of a delivery. Offloaded payloads are streamed from the blob
+// store on every Open, so a destination may read the body more than once.
+type Payload struct {
+	size int64
+	open func(ctx context.Context) (io.ReadCloser, error)
+}
+
+// InlinePayload returns a Payload held in memory
+func InlinePayload(data []byte) Payload {
+	return Payload{
+		size: int64(len(data)),
+		open: func(context.Context) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		},
+	}
+}
+
+// StreamedPayload returns a Payload of the given size that is read through open
+func StreamedPayload(size int64, open func(ctx context.Context) (io.ReadCloser, error)) Payload {
+	return Payload{size: size, open: open}
+}
+
+// Size returns the payload size in bytes
+func (p Payload) Size() int64 {
+	return p.size
+}
+
+// Open opens the payload for reading
+func (p Payload) Open(ctx context.Context) (io.ReadCloser, error) {
+	return p.open(ctx)
+}
+
+// Bytes reads the whole payload, for transports that send a message in one piece
+func (p Payload) Bytes(ctx context.Context) ([]byte, error) {
+	rc, err := p.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// Registry maps destination types to their Destination
+type Registry struct {
+	destinations map[string]Destination
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{destinations: make(map[string]Destination)}
+}
+
+// Register registers the Destination for a destination type, replacing any existing one
+func (r *Registry) Register(destinationType string, d Destination) {
+	r.destinations[destinationType] = d
+}
+
+// Get returns the Destination registered for a destination type
+func (r *Registry) Get(destinationType string) (Destination, bool) {
+	d, ok := r.destinations[destinationType]
+	return d, ok
+}
+
+// metadata returns the delivery metadata sent alongside the payload by
+// destinations that carry key/value headers
+func metadata(delivery *models.WebhookDelivery, subscription *models.Subscription) map[string]string {
+	fields := map[string]string{
+		"delivery_id":     delivery.ID.String(),
+		"subscription_id": subscription.ID.String(),
+	}
+	if delivery.EventType != nil {
+		fields["event_type"] = *delivery.EventType
+	}
+	return fields
+}
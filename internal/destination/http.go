@@ -0,0 +1,163 @@
+package destination
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/pkg/signature"
+)
+
+// maxErrorBodySize caps how much of a failed response is recorded on the attempt
+const maxErrorBodySize = 4096
+
+// errNoSigningKeys is returned when delivering to an ed25519 subscription without
+// signing keys
+var errNoSigningKeys = errors.New("no Ed25519 signing keys are configured")
+
+// HTTPDestination POSTs the payload to the subscription's target URL
+type HTTPDestination struct {
+	client      *http.Client
+	signingKeys []ed25519.PrivateKey
+}
+
+// NewHTTP creates a new HTTPDestination with the given request timeout. Deliveries of
+// the ed25519 signature scheme are signed with each of the signing keys.
+func NewHTTP(timeout time.Duration, signingKeys ...ed25519.PrivateKey) *HTTPDestination {
+	return &HTTPDestination{
+		client:      &http.Client{Timeout: timeout},
+		signingKeys: signingKeys,
+	}
+}
+
+// Deliver implements Destination. The body is streamed, so offloaded payloads are
+// never held in memory, except for Ed25519 signing which needs the whole body.
+func (d *HTTPDestination) Deliver(ctx context.Context, delivery *models.WebhookDelivery, subscription *models.Subscription, payload Payload) Result {
+	body, err := payload.Open(ctx)
+	if err != nil {
+		return Result{Err: fmt.Errorf("failed to read payload: %w", err)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.TargetURL, body)
+	if err != nil {
+		body.Close()
+		return Result{Err: fmt.Errorf("failed to create HTTP request: %w", err)}
+	}
+	req.ContentLength = payload.Size()
+	req.GetBody = func() (io.ReadCloser, error) {
+		return payload.Open(ctx)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// Add event type header if present
+	if delivery.EventType != nil {
+		req.Header.Set("X-Webhook-Event", *delivery.EventType)
+	}
+
+	// Add delivery ID header
+	req.Header.Set("X-Webhook-ID", delivery.ID.String())
+
+	// Add signatures from the signing keys for the ed25519 scheme, or if a secret key
+	// is present, one per secret during a rotation overlap
+	if subscription.SignatureScheme == models.SignatureSchemeEd25519 {
+		err = d.signEd25519(ctx, req, delivery, payload)
+	} else if secrets := subscription.SigningSecrets(time.Now()); len(secrets) > 0 {
+		err = signRequest(ctx, req, delivery, subscription.SignatureScheme, payload, secrets)
+	}
+	if err != nil {
+		body.Close()
+		return Result{Err: fmt.Errorf("failed to sign payload: %w", err)}
+	}
+
+	// Pass the trace context to the consumer as traceparent
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return Result{Err: err}
+	}
+	defer resp.Body.Close()
+
+	statusCode := resp.StatusCode
+	if statusCode >= 200 && statusCode < 300 {
+		return Result{StatusCode: &statusCode}
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return Result{
+		StatusCode: &statusCode,
+		Err:        fmt.Errorf("HTTP %d: %s", statusCode, string(respBody)),
+	}
+}
+
+// signRequest sets the signature headers of the subscription's scheme. The payload
+// is read in a separate pass, so a corrupt offloaded payload fails before anything is
+// sent.
+func signRequest(ctx context.Context, req *http.Request, delivery *models.WebhookDelivery, scheme string, payload Payload, secrets []string) error {
+	id := delivery.ID.String()
+	timestamp := time.Now()
+
+	signers := make([]signature.Signer, len(secrets))
+	writers := make([]io.Writer, len(secrets))
+	for i, secret := range secrets {
+		if scheme == models.SignatureSchemeStandardWebhooks {
+			signers[i] = signature.NewStandardSigner(secret, id, timestamp)
+		} else {
+			signers[i] = signature.NewHubSigner(secret)
+		}
+		writers[i] = signers[i]
+	}
+
+	rc, err := payload.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if _, err := io.Copy(io.MultiWriter(writers...), rc); err != nil {
+		return err
+	}
+
+	if scheme == models.SignatureSchemeStandardWebhooks {
+		req.Header.Set(signature.IDHeader, id)
+		req.Header.Set(signature.TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+		req.Header.Set(signature.SignatureHeader, signature.Join(signers))
+		return nil
+	}
+	req.Header.Set(signature.HubHeader, signature.Join(signers))
+	return nil
+}
+
+// signEd25519 sets the Standard Webhooks headers with a v1a signature per signing key
+func (d *HTTPDestination) signEd25519(ctx context.Context, req *http.Request, delivery *models.WebhookDelivery, payload Payload) error {
+	if len(d.signingKeys) == 0 {
+		return errNoSigningKeys
+	}
+
+	data, err := payload.Bytes(ctx)
+	if err != nil {
+		return err
+	}
+
+	id := delivery.ID.String()
+	timestamp := time.Now()
+	signatures := make([]string, len(d.signingKeys))
+	for i, key := range d.signingKeys {
+		signatures[i] = signature.Ed25519(key, id, timestamp, data)
+	}
+
+	req.Header.Set(signature.IDHeader, id)
+	req.Header.Set(signature.TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	req.Header.Set(signature.SignatureHeader, strings.Join(signatures, " "))
+	return nil
+}
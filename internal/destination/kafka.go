@@ -0,0 +1,42 @@
+package destination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// KafkaPublisher publishes a message and waits for the broker's delivery report.
+// It is implemented by kafka.Publisher, which keeps cgo out of this package.
+type KafkaPublisher interface {
+	Publish(ctx context.Context, topic string, key string, payload []byte, headers map[string]string) error
+}
+
+// KafkaDestination publishes the payload to the subscription's Kafka topic
+type KafkaDestination struct {
+	publisher KafkaPublisher
+}
+
+// NewKafka creates a new KafkaDestination
+func NewKafka(publisher KafkaPublisher) *KafkaDestination {
+	return &KafkaDestination{publisher: publisher}
+}
+
+// Deliver implements Destination. The event type, delivery ID and subscription ID
+// are sent as message headers.
+func (d *KafkaDestination) Deliver(ctx context.Context, delivery *models.WebhookDelivery, subscription *models.Subscription, payload Payload) Result {
+	cfg := subscription.DestinationConfig
+	if cfg == nil || cfg.Topic == "" {
+		return Result{Err: errors.New("subscription has no kafka topic configured")}
+	}
+
+	body, err := payload.Bytes(ctx)
+	if err != nil {
+		return Result{Err: fmt.Errorf("failed to read payload: %w", err)}
+	}
+
+	err = d.publisher.Publish(ctx, cfg.Topic, cfg.Key, body, metadata(delivery, subscription))
+	return Result{Err: err}
+}
@@ -0,0 +1,54 @@
+package destination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// RedisStreamDestination appends the payload to the subscription's Redis stream with XADD
+type RedisStreamDestination struct {
+	redis *redis.Client
+}
+
+// NewRedisStream creates a new RedisStreamDestination
+func NewRedisStream(redisClient *redis.Client) *RedisStreamDestination {
+	return &RedisStreamDestination{redis: redisClient}
+}
+
+// Deliver implements Destination. The entry holds the payload along with the event
+// type, delivery ID and subscription ID fields.
+func (d *RedisStreamDestination) Deliver(ctx context.Context, delivery *models.WebhookDelivery, subscription *models.Subscription, payload Payload) Result {
+	cfg := subscription.DestinationConfig
+	if cfg == nil || cfg.Stream == "" {
+		return Result{Err: errors.New("subscription has no redis stream configured")}
+	}
+
+	body, err := payload.Bytes(ctx)
+	if err != nil {
+		return Result{Err: fmt.Errorf("failed to read payload: %w", err)}
+	}
+
+	values := map[string]interface{}{
+		"payload": string(body),
+	}
+	for name, value := range metadata(delivery, subscription) {
+		values[name] = value
+	}
+
+	args := &redis.XAddArgs{
+		Stream: cfg.Stream,
+		Values: values,
+	}
+	if cfg.MaxLen > 0 {
+		args.MaxLen = cfg.MaxLen
+		args.Approx = true
+	}
+
+	err = d.redis.XAdd(ctx, args).Err()
+	return Result{Err: err}
+}
@@ -15,6 +15,290 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/webhook-keys": {
+            "get": {
+                "description": "Get the Ed25519 public keys deliveries of the ed25519 signature scheme are signed with, as a JSON Web Key Set. During a rotation the previous keys are listed after the current one, and deliveries carry a signature from each key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keys"
+                ],
+                "summary": "Get the webhook signing keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_pkg_signature.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit": {
+            "get": {
+                "description": "List the management operations made through the API, newest first, with who made them and the state of the resource before and after. Signing secrets are redacted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "List audit events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only events of this action, e.g. subscription.updated",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this resource type: subscription, event_type or delivery",
+                        "name": "resource_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this resource",
+                        "name": "resource_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this actor",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this request",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events at or after this time (RFC 3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events before this time (RFC 3339)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit results (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.AuditEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/event-types": {
+            "get": {
+                "description": "List the event types subscriptions can subscribe to, with their latest schema version",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event-types"
+                ],
+                "summary": "List event types",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.EventType"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Register an event type with a description, the JSON Schema of its payload as version 1 and example payloads. Examples must match the schema.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event-types"
+                ],
+                "summary": "Register an event type",
+                "parameters": [
+                    {
+                        "description": "Event type",
+                        "name": "event_type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.EventTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.EventType"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/event-types/{name}": {
+            "get": {
+                "description": "Get an event type with all of its schema versions and examples, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event-types"
+                ],
+                "summary": "Get an event type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event type name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.EventType"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/event-types/{name}/versions": {
+            "post": {
+                "description": "Add the next schema version of an event type. Payloads are validated against the latest version.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event-types"
+                ],
+                "summary": "Add an event type schema version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event type name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schema version",
+                        "name": "version",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.EventTypeVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.EventTypeVersion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/subscriptions": {
             "get": {
                 "description": "Get a list of all webhook subscriptions",
@@ -44,7 +328,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new webhook subscription with the provided details",
+                "description": "Create a new webhook subscription with the provided details. A signing secret is generated when none is given. The secret is only returned in this response.",
                 "consumes": [
                     "application/json"
                 ],
@@ -70,7 +354,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.Subscription"
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.CreatedSubscription"
                         }
                     },
                     "400": {
@@ -284,20 +568,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/webhooks/deliveries/{id}": {
+        "/subscriptions/{id}/deliveries/stream": {
             "get": {
-                "description": "Get the status and attempt history of a webhook delivery",
+                "description": "Stream delivery state changes and attempt results for a subscription as Server-Sent Events. The SSE event name is the delivery event type.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "webhooks"
+                    "subscriptions"
                 ],
-                "summary": "Get webhook delivery status",
+                "summary": "Stream delivery events",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Delivery ID",
+                        "description": "Subscription ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -307,7 +591,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.DeliveryStatusResponse"
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.DeliveryEvent"
                         }
                     },
                     "400": {
@@ -331,9 +615,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/webhooks/ingest/{subscription_id}": {
+        "/subscriptions/{id}/secret/reveal": {
             "post": {
-                "description": "Ingest a webhook payload for a subscription",
+                "description": "Return the signing secret of a subscription. Requires the SECRET_REVEAL_TOKEN bearer token and a reason, every reveal is written to the audit log.",
                 "consumes": [
                     "application/json"
                 ],
@@ -341,32 +625,528 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "webhooks"
+                    "subscriptions"
                 ],
-                "summary": "Ingest a webhook",
+                "summary": "Reveal a subscription's signing secret",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Subscription ID",
-                        "name": "subscription_id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Event Type",
-                        "name": "X-Event-Type",
-                        "in": "header"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Webhook Signature",
-                        "name": "X-Hub-Signature-256",
-                        "in": "header"
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     },
                     {
-                        "description": "Webhook payload",
-                        "name": "payload",
+                        "description": "Reveal reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.RevealSecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.RevealSecretResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/secret/rotate": {
+            "post": {
+                "description": "Replace the signing secret of a subscription. The new secret is generated unless one is given, and is only returned in this response. The old secret stays valid for the overlap, during which deliveries carry a signature from each secret in X-Hub-Signature-256, separated by spaces.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Rotate a subscription's signing secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New secret and overlap",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.RotateSecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.RotateSecretResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/stats": {
+            "get": {
+                "description": "Get hourly delivery and attempt counts for a subscription, grouped by event type",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get delivery statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the window in RFC3339 (default 24 hours ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the window in RFC3339 (default now)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.DeliveryStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/archive/{day}": {
+            "get": {
+                "description": "Stream the deliveries archived for a day (UTC) before the retention policy removed them, with their attempts, as JSON lines. Offloaded payloads are inlined. The archive is read only, nothing is written back to the database.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Restore archived deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Day the deliveries were created, YYYY-MM-DD",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the deliveries of this subscription",
+                        "name": "subscription_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.ArchivedDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/replay": {
+            "post": {
+                "description": "Queue failed deliveries matching the filter for delivery again, oldest first. At most 'limit' deliveries (default 100) are replayed per request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Replay failed deliveries",
+                "parameters": [
+                    {
+                        "description": "Replay filter",
+                        "name": "filter",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.ReplayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.ReplayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/scheduled": {
+            "get": {
+                "description": "List the deliveries waiting for their deliver_at time, the next to fire first. At most 'limit' deliveries (default 100) are returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List scheduled deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only list deliveries of this subscription",
+                        "name": "subscription_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit results (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}": {
+            "get": {
+                "description": "Get the status and attempt history of a webhook delivery",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook delivery status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.DeliveryStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}/cancel": {
+            "post": {
+                "description": "Cancel a delivery in the SCHEDULED state before its deliver_at time",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Cancel a scheduled delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}/replay": {
+            "post": {
+                "description": "Queue a delivery in the FAILED state for delivery again with a fresh retry budget",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Replay a failed delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.ReplayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/ingest/{subscription_id}": {
+            "post": {
+                "description": "Ingest a webhook payload for a subscription. Set deliver_at or delay to deliver it later, and expires_at to drop it if it cannot be delivered in time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Ingest a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event Type",
+                        "name": "X-Event-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook Signature",
+                        "name": "X-Hub-Signature-256",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the key is repeated",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Webhook payload",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
@@ -393,6 +1173,101 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_api.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.SchemaErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/publish": {
+            "post": {
+                "description": "Queue an event for delivery to every subscription that accepts its event type. Set deliver_at or delay to deliver it later, and expires_at to drop it if it cannot be delivered in time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Publish an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event Type",
+                        "name": "X-Event-Type",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the key is repeated",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Event payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.PublishResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.SchemaErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -401,46 +1276,500 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_Unic-X_webhook-delivery_internal_models.DeliveryAttempt": {
+        }
+    },
+    "definitions": {
+        "github_com_Unic-X_webhook-delivery_internal_models.ArchivedDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.DeliveryAttempt"
+                    }
+                },
+                "delivery": {
+                    "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.WebhookDelivery"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                },
+                "source_ip": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.CreatedSubscription": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "destination_config": {
+                    "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.DestinationConfig"
+                },
+                "destination_type": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_event_age_seconds": {
+                    "type": "integer"
+                },
+                "previous_secret_expires_at": {
+                    "type": "string"
+                },
+                "retention_policy": {
+                    "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.RetentionPolicy"
+                },
+                "secret_key": {
+                    "type": "string"
+                },
+                "secret_key_hint": {
+                    "type": "string"
+                },
+                "signature_scheme": {
+                    "type": "string"
+                },
+                "target_url": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.DeliveryAttempt": {
+            "type": "object",
+            "properties": {
+                "attempt_number": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "error_details": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.DeliveryEvent": {
+            "type": "object",
+            "properties": {
+                "attempt_number": {
+                    "type": "integer"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "error_details": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "next_retry_at": {
+                    "type": "string"
+                },
+                "retry_count": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.DeliveryStats": {
+            "type": "object",
+            "properties": {
+                "attempts_failed": {
+                    "type": "integer"
+                },
+                "attempts_succeeded": {
+                    "type": "integer"
+                },
+                "attempts_total": {
+                    "type": "integer"
+                },
+                "bucket_start": {
+                    "type": "string"
+                },
+                "deliveries_delivered": {
+                    "type": "integer"
+                },
+                "deliveries_failed": {
+                    "type": "integer"
+                },
+                "deliveries_total": {
+                    "type": "integer"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.DeliveryStatsResponse": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.DeliveryStats"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.DeliveryStatusResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.DeliveryAttempt"
+                    }
+                },
+                "delivery": {
+                    "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.WebhookDelivery"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.DestinationConfig": {
             "type": "object",
             "properties": {
-                "attempt_number": {
+                "key": {
+                    "description": "Key is the Kafka message key, messages are unkeyed when empty",
+                    "type": "string"
+                },
+                "max_len": {
+                    "description": "MaxLen approximately caps the Redis stream length, unbounded when zero",
                     "type": "integer"
                 },
+                "stream": {
+                    "description": "Stream is the Redis stream deliveries are appended to",
+                    "type": "string"
+                },
+                "topic": {
+                    "description": "Topic is the Kafka topic deliveries are published to",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.EventType": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "delivery_id": {
+                "description": {
                     "type": "string"
                 },
-                "error_details": {
+                "latest_version": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
                 },
-                "id": {
+                "updated_at": {
                     "type": "string"
                 },
-                "status": {
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.EventTypeVersion"
+                    }
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.EventTypeRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "schema"
+            ],
+            "properties": {
+                "description": {
                     "type": "string"
                 },
-                "status_code": {
+                "examples": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "schema": {
+                    "type": "object"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.EventTypeVersion": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "examples": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "schema": {
+                    "type": "object"
+                },
+                "version": {
                     "type": "integer"
                 }
             }
         },
-        "github_com_Unic-X_webhook-delivery_internal_models.DeliveryStatusResponse": {
+        "github_com_Unic-X_webhook-delivery_internal_models.EventTypeVersionRequest": {
             "type": "object",
+            "required": [
+                "schema"
+            ],
             "properties": {
-                "attempts": {
+                "examples": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.DeliveryAttempt"
+                        "type": "object"
                     }
                 },
-                "delivery": {
-                    "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.WebhookDelivery"
+                "schema": {
+                    "type": "object"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.PublishResponse": {
+            "type": "object",
+            "properties": {
+                "delivery_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.ReplayRequest": {
+            "type": "object",
+            "properties": {
+                "event_type": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
+                },
+                "since": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "until": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.ReplayResponse": {
+            "type": "object",
+            "properties": {
+                "delivery_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.RetentionPolicy": {
+            "type": "object",
+            "properties": {
+                "cancelled_seconds": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "delivered_seconds": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "expired_seconds": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "failed_seconds": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "delete",
+                        "strip_payload"
+                    ]
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.RevealSecretRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.RevealSecretResponse": {
+            "type": "object",
+            "properties": {
+                "secret_key": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.RotateSecretRequest": {
+            "type": "object",
+            "properties": {
+                "overlap_seconds": {
+                    "type": "integer",
+                    "maximum": 2592000,
+                    "minimum": 0
+                },
+                "secret_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.RotateSecretResponse": {
+            "type": "object",
+            "properties": {
+                "previous_secret_expires_at": {
+                    "type": "string"
+                },
+                "secret_key": {
+                    "type": "string"
+                },
+                "secret_key_hint": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_internal_models.SchemaViolation": {
+            "type": "object",
+            "properties": {
+                "keyword": {
+                    "description": "Keyword is the schema location of the failing keyword",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path is the JSON pointer of the offending value",
+                    "type": "string"
                 }
             }
         },
@@ -450,6 +1779,12 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "destination_config": {
+                    "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.DestinationConfig"
+                },
+                "destination_type": {
+                    "type": "string"
+                },
                 "event_types": {
                     "type": "array",
                     "items": {
@@ -459,7 +1794,19 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
-                "secret_key": {
+                "max_event_age_seconds": {
+                    "type": "integer"
+                },
+                "previous_secret_expires_at": {
+                    "type": "string"
+                },
+                "retention_policy": {
+                    "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.RetentionPolicy"
+                },
+                "secret_key_hint": {
+                    "type": "string"
+                },
+                "signature_scheme": {
                     "type": "string"
                 },
                 "target_url": {
@@ -472,19 +1819,48 @@ const docTemplate = `{
         },
         "github_com_Unic-X_webhook-delivery_internal_models.SubscriptionRequest": {
             "type": "object",
-            "required": [
-                "target_url"
-            ],
             "properties": {
+                "destination_config": {
+                    "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.DestinationConfig"
+                },
+                "destination_type": {
+                    "type": "string",
+                    "enum": [
+                        "http",
+                        "kafka",
+                        "redis_stream"
+                    ]
+                },
                 "event_types": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "max_event_age_seconds": {
+                    "description": "MaxEventAgeSeconds expires deliveries that could not be sent within this many\nseconds of being created. Zero removes the limit.",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "retention_policy": {
+                    "description": "RetentionPolicy overrides the global retention of the subscription's\ndeliveries. An empty policy removes the override.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.RetentionPolicy"
+                        }
+                    ]
+                },
                 "secret_key": {
                     "type": "string"
                 },
+                "signature_scheme": {
+                    "type": "string",
+                    "enum": [
+                        "github",
+                        "standard_webhooks",
+                        "ed25519"
+                    ]
+                },
                 "target_url": {
                     "type": "string"
                 }
@@ -499,6 +1875,9 @@ const docTemplate = `{
                 "event_type": {
                     "type": "string"
                 },
+                "expires_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -514,6 +1893,18 @@ const docTemplate = `{
                         "type": "integer"
                     }
                 },
+                "payload_purged_at": {
+                    "type": "string"
+                },
+                "payload_ref": {
+                    "type": "string"
+                },
+                "payload_sha256": {
+                    "type": "string"
+                },
+                "payload_size": {
+                    "type": "integer"
+                },
                 "retry_count": {
                     "type": "integer"
                 },
@@ -531,6 +1922,16 @@ const docTemplate = `{
                 "payload"
             ],
             "properties": {
+                "delay": {
+                    "type": "string",
+                    "example": "24h"
+                },
+                "deliver_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
                 "payload": {
                     "type": "array",
                     "items": {
@@ -539,6 +1940,43 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_Unic-X_webhook-delivery_pkg_signature.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Unic-X_webhook-delivery_pkg_signature.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_pkg_signature.JWK"
+                    }
+                }
+            }
+        },
         "internal_api.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -547,6 +1985,26 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_api.SchemaErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Unic-X_webhook-delivery_internal_models.SchemaViolation"
+                    }
+                }
+            }
+        },
         "internal_api.SuccessResponse": {
             "type": "object",
             "properties": {
@@ -0,0 +1,116 @@
+// Package envelope implements envelope encryption. Data is encrypted with AES-256-GCM
+// under a data key, and data keys are stored wrapped by a master key from the keyring.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// KeySize is the size of master and data keys in bytes
+const KeySize = 32
+
+// version is the first byte of every ciphertext
+const version byte = 1
+
+// SealedPrefix marks a string field that holds a value produced by SealString
+const SealedPrefix = "enc:v1:"
+
+// ErrMalformed is returned when a ciphertext cannot be parsed
+var ErrMalformed = errors.New("malformed ciphertext")
+
+// GenerateKey returns a new random data key
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Seal encrypts plaintext with a data key. The data key ID is stored in the
+// ciphertext, and aad binds it to the row it belongs to.
+func Seal(dataKeyID uuid.UUID, key []byte, plaintext []byte, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 1+len(dataKeyID)+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	out = append(out, version)
+	out = append(out, dataKeyID[:]...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, aad), nil
+}
+
+// KeyID returns the ID of the data key a ciphertext was sealed with
+func KeyID(ciphertext []byte) (uuid.UUID, error) {
+	if len(ciphertext) < 1+len(uuid.UUID{}) || ciphertext[0] != version {
+		return uuid.Nil, ErrMalformed
+	}
+	return uuid.FromBytes(ciphertext[1 : 1+len(uuid.UUID{})])
+}
+
+// Open decrypts a ciphertext produced by Seal with the same data key and aad
+func Open(key []byte, ciphertext []byte, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := 1 + len(uuid.UUID{})
+	if len(ciphertext) < header+aead.NonceSize()+aead.Overhead() || ciphertext[0] != version {
+		return nil, ErrMalformed
+	}
+	nonce := ciphertext[header : header+aead.NonceSize()]
+	return aead.Open(nil, nonce, ciphertext[header+aead.NonceSize():], aad)
+}
+
+// SealString seals a string field, returning a text encoding suitable for TEXT columns
+func SealString(dataKeyID uuid.UUID, key []byte, plaintext string, aad []byte) (string, error) {
+	ciphertext, err := Seal(dataKeyID, key, []byte(plaintext), aad)
+	if err != nil {
+		return "", err
+	}
+	return SealedPrefix + base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// IsSealedString reports whether a string field holds a value produced by SealString
+func IsSealedString(s string) bool {
+	return strings.HasPrefix(s, SealedPrefix)
+}
+
+// DecodeString returns the ciphertext of a string field produced by SealString
+func DecodeString(s string) ([]byte, error) {
+	if !IsSealedString(s) {
+		return nil, ErrMalformed
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(s, SealedPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return ciphertext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,161 @@
+package envelope
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func mustGenerateKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestSealOpen(t *testing.T) {
+	keyID := uuid.New()
+	key := mustGenerateKey(t)
+	otherKey := mustGenerateKey(t)
+	aad := []byte("webhook_deliveries.payload:" + uuid.New().String())
+
+	tests := []struct {
+		name      string
+		plaintext []byte
+		openKey   []byte
+		openAAD   []byte
+		tamper    func([]byte) []byte
+		wantErr   error
+	}{
+		{name: "round trip", plaintext: []byte(`{"order":1}`), openKey: key, openAAD: aad},
+		{name: "empty plaintext", plaintext: []byte{}, openKey: key, openAAD: aad},
+		{name: "wrong data key", plaintext: []byte("secret"), openKey: otherKey, openAAD: aad},
+		{name: "aad of another row", plaintext: []byte("secret"), openKey: key, openAAD: []byte("webhook_deliveries.payload:other")},
+		{
+			name: "flipped ciphertext bit", plaintext: []byte("secret"), openKey: key, openAAD: aad,
+			tamper: func(ct []byte) []byte { ct[len(ct)-1] ^= 1; return ct },
+		},
+		{
+			name: "truncated", plaintext: []byte("secret"), openKey: key, openAAD: aad,
+			tamper: func(ct []byte) []byte { return ct[:20] }, wantErr: ErrMalformed,
+		},
+		{
+			name: "unknown version", plaintext: []byte("secret"), openKey: key, openAAD: aad,
+			tamper: func(ct []byte) []byte { ct[0] = version + 1; return ct }, wantErr: ErrMalformed,
+		},
+		{name: "short key", plaintext: []byte("secret"), openKey: key[:16], openAAD: aad},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ciphertext, err := Seal(keyID, key, tt.plaintext, aad)
+			if err != nil {
+				t.Fatalf("Seal() error = %v", err)
+			}
+			if bytes.Contains(ciphertext, tt.plaintext) && len(tt.plaintext) > 0 {
+				t.Fatal("ciphertext contains the plaintext")
+			}
+			if tt.tamper != nil {
+				ciphertext = tt.tamper(ciphertext)
+			}
+
+			got, err := Open(tt.openKey, ciphertext, tt.openAAD)
+			wantOK := bytes.Equal(tt.openKey, key) && bytes.Equal(tt.openAAD, aad) && tt.tamper == nil
+			switch {
+			case wantOK && err != nil:
+				t.Fatalf("Open() error = %v", err)
+			case wantOK && !bytes.Equal(got, tt.plaintext):
+				t.Fatalf("Open() = %q, want %q", got, tt.plaintext)
+			case !wantOK && err == nil:
+				t.Fatal("Open() succeeded, want an error")
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("Open() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSealUsesFreshNonces(t *testing.T) {
+	keyID := uuid.New()
+	key := mustGenerateKey(t)
+
+	a, err := Seal(keyID, key, []byte("secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Seal(keyID, key, []byte("secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(a, b) {
+		t.Fatal("sealing the same plaintext twice gave the same ciphertext")
+	}
+}
+
+func TestKeyID(t *testing.T) {
+	keyID := uuid.New()
+	ciphertext, err := Seal(keyID, mustGenerateKey(t), []byte("secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		ciphertext []byte
+		want       uuid.UUID
+		wantErr    bool
+	}{
+		{name: "sealed", ciphertext: ciphertext, want: keyID},
+		{name: "empty", ciphertext: nil, wantErr: true},
+		{name: "too short", ciphertext: ciphertext[:8], wantErr: true},
+		{name: "plaintext json", ciphertext: []byte(`{"order":1,"customer":"a"}`), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := KeyID(tt.ciphertext)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("KeyID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("KeyID() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSealString(t *testing.T) {
+	keyID := uuid.New()
+	key := mustGenerateKey(t)
+	aad := []byte("subscriptions.secret_key:" + uuid.New().String())
+
+	sealed, err := SealString(keyID, key, "whsec_abc", aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealedString(sealed) {
+		t.Fatalf("IsSealedString(%q) = false", sealed)
+	}
+
+	ciphertext, err := DecodeString(sealed)
+	if err != nil {
+		t.Fatalf("DecodeString() error = %v", err)
+	}
+	plaintext, err := Open(key, ciphertext, aad)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if string(plaintext) != "whsec_abc" {
+		t.Fatalf("Open() = %q, want %q", plaintext, "whsec_abc")
+	}
+
+	for _, s := range []string{"whsec_abc", SealedPrefix + "not base64!"} {
+		if _, err := DecodeString(s); !errors.Is(err, ErrMalformed) {
+			t.Errorf("DecodeString(%q) error = %v, want %v", s, err, ErrMalformed)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package envelope
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/config"
+)
+
+// Keyring holds the master keys data keys are wrapped with. New data keys are
+// wrapped with the active key, the others are kept to unwrap data keys until
+// they have been re-wrapped.
+type Keyring struct {
+	active string
+	keys   map[string][]byte
+}
+
+// LoadKeyring loads the master keys from ENCRYPTION_KEYS or ENCRYPTION_KEY_FILE.
+// It returns nil when neither is set, which leaves encryption disabled.
+func LoadKeyring(cfg *config.Config) (*Keyring, error) {
+	switch {
+	case len(cfg.EncryptionKeys) > 0 && cfg.EncryptionKeyFile != "":
+		return nil, errors.New("set only one of ENCRYPTION_KEYS and ENCRYPTION_KEY_FILE")
+	case len(cfg.EncryptionKeys) > 0:
+		return ParseKeyring(cfg.EncryptionKeys)
+	case cfg.EncryptionKeyFile != "":
+		return readKeyFile(cfg.EncryptionKeyFile)
+	default:
+		return nil, nil
+	}
+}
+
+// ParseKeyring parses master keys in the form "<id>:<base64 key>". The first
+// entry is the active key.
+func ParseKeyring(entries []string) (*Keyring, error) {
+	if len(entries) == 0 {
+		return nil, errors.New("no master keys")
+	}
+
+	k := &Keyring{keys: make(map[string][]byte, len(entries))}
+	for i, entry := range entries {
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("master key %d: expected <id>:<base64 key>", i+1)
+		}
+		if _, exists := k.keys[id]; exists {
+			return nil, fmt.Errorf("master key %q is listed twice", id)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("master key %q: %w", id, err)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("master key %q must be %d bytes, got %d", id, KeySize, len(key))
+		}
+
+		k.keys[id] = key
+		if i == 0 {
+			k.active = id
+		}
+	}
+	return k, nil
+}
+
+// readKeyFile reads a key file with one "<id>:<base64 key>" entry per line. Blank
+// lines and lines starting with # are ignored.
+func readKeyFile(path string) (*Keyring, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	return ParseKeyring(entries)
+}
+
+// ActiveID returns the ID of the master key new data keys are wrapped with
+func (k *Keyring) ActiveID() string {
+	return k.active
+}
+
+// Wrap encrypts a data key with the active master key and returns the master key ID
+// along with the wrapped key
+func (k *Keyring) Wrap(dataKeyID uuid.UUID, dataKey []byte) (string, []byte, error) {
+	aead, err := newAEAD(k.keys[k.active])
+	if err != nil {
+		return "", nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return k.active, aead.Seal(nonce, nonce, dataKey, dataKeyID[:]), nil
+}
+
+// Unwrap decrypts a data key with the master key it was wrapped with
+func (k *Keyring) Unwrap(dataKeyID uuid.UUID, masterKeyID string, wrapped []byte) ([]byte, error) {
+	masterKey, ok := k.keys[masterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("master key %q is not in the keyring", masterKeyID)
+	}
+
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	return aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], dataKeyID[:])
+}
@@ -0,0 +1,166 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/config"
+)
+
+// masterKey returns a keyring entry for a new random master key
+func masterKey(t *testing.T, id string) string {
+	t.Helper()
+	return id + ":" + base64.StdEncoding.EncodeToString(mustGenerateKey(t))
+}
+
+func mustParseKeyring(t *testing.T, entries ...string) *Keyring {
+	t.Helper()
+	k, err := ParseKeyring(entries)
+	if err != nil {
+		t.Fatalf("ParseKeyring() error = %v", err)
+	}
+	return k
+}
+
+func TestParseKeyring(t *testing.T) {
+	primary := masterKey(t, "primary")
+	short := "short:" + base64.StdEncoding.EncodeToString(make([]byte, 16))
+
+	tests := []struct {
+		name       string
+		entries    []string
+		wantActive string
+		wantErr    bool
+	}{
+		{name: "single key", entries: []string{primary}, wantActive: "primary"},
+		{name: "first key is active", entries: []string{masterKey(t, "next"), primary}, wantActive: "next"},
+		{name: "no keys", entries: nil, wantErr: true},
+		{name: "missing separator", entries: []string{"primary"}, wantErr: true},
+		{name: "empty id", entries: []string{":" + base64.StdEncoding.EncodeToString(mustGenerateKey(t))}, wantErr: true},
+		{name: "duplicate id", entries: []string{primary, masterKey(t, "primary")}, wantErr: true},
+		{name: "invalid base64", entries: []string{"primary:not base64!"}, wantErr: true},
+		{name: "short key", entries: []string{short}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := ParseKeyring(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKeyring() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && k.ActiveID() != tt.wantActive {
+				t.Fatalf("ActiveID() = %q, want %q", k.ActiveID(), tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestLoadKeyringFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys")
+	contents := "# rotated in on 2026-10-01\n" + masterKey(t, "k2") + "\n\n" + masterKey(t, "k1") + "\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := LoadKeyring(&config.Config{EncryptionKeyFile: path})
+	if err != nil {
+		t.Fatalf("LoadKeyring() error = %v", err)
+	}
+	if k.ActiveID() != "k2" {
+		t.Fatalf("ActiveID() = %q, want %q", k.ActiveID(), "k2")
+	}
+
+	if k, err := LoadKeyring(&config.Config{}); k != nil || err != nil {
+		t.Fatalf("LoadKeyring() without keys = %v, %v, want nil, nil", k, err)
+	}
+	if _, err := LoadKeyring(&config.Config{EncryptionKeys: []string{masterKey(t, "k1")}, EncryptionKeyFile: path}); err == nil {
+		t.Fatal("LoadKeyring() with keys and a key file succeeded, want an error")
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	old := masterKey(t, "old")
+	next := masterKey(t, "next")
+
+	dataKeyID := uuid.New()
+	dataKey := mustGenerateKey(t)
+	masterKeyID, wrapped, err := mustParseKeyring(t, old).Wrap(dataKeyID, dataKey)
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+	if masterKeyID != "old" {
+		t.Fatalf("Wrap() master key = %q, want %q", masterKeyID, "old")
+	}
+
+	// After the rotation the old key is still listed to unwrap existing data keys,
+	// which are re-wrapped with the new active key
+	rotated := mustParseKeyring(t, next, old)
+	unwrapped, err := rotated.Unwrap(dataKeyID, masterKeyID, wrapped)
+	if err != nil {
+		t.Fatalf("Unwrap() with the old key error = %v", err)
+	}
+	rewrappedID, rewrapped, err := rotated.Wrap(dataKeyID, unwrapped)
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+	if rewrappedID != "next" {
+		t.Fatalf("Wrap() master key = %q, want %q", rewrappedID, "next")
+	}
+
+	// Once everything is re-wrapped the old key can be removed
+	retired := mustParseKeyring(t, next)
+	got, err := retired.Unwrap(dataKeyID, rewrappedID, rewrapped)
+	if err != nil {
+		t.Fatalf("Unwrap() after retiring the old key error = %v", err)
+	}
+	if !bytes.Equal(got, dataKey) {
+		t.Fatal("re-wrapped data key differs from the original")
+	}
+	if _, err := retired.Unwrap(dataKeyID, masterKeyID, wrapped); err == nil {
+		t.Fatal("Unwrap() with a retired master key succeeded, want an error")
+	}
+}
+
+func TestUnwrapErrors(t *testing.T) {
+	k := mustParseKeyring(t, masterKey(t, "primary"))
+	dataKeyID := uuid.New()
+	_, wrapped, err := k.Wrap(dataKeyID, mustGenerateKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Same master key ID, different key material, e.g. a mistyped key
+	replaced := mustParseKeyring(t, masterKey(t, "primary"))
+
+	tests := []struct {
+		name        string
+		keyring     *Keyring
+		dataKeyID   uuid.UUID
+		masterKeyID string
+		wrapped     []byte
+	}{
+		{name: "unknown master key id", keyring: k, dataKeyID: dataKeyID, masterKeyID: "missing", wrapped: wrapped},
+		{name: "empty master key id", keyring: k, dataKeyID: dataKeyID, masterKeyID: "", wrapped: wrapped},
+		{name: "wrong master key", keyring: replaced, dataKeyID: dataKeyID, masterKeyID: "primary", wrapped: wrapped},
+		{name: "wrapped for another data key", keyring: k, dataKeyID: uuid.New(), masterKeyID: "primary", wrapped: wrapped},
+		{name: "truncated", keyring: k, dataKeyID: dataKeyID, masterKeyID: "primary", wrapped: wrapped[:4]},
+		{name: "empty", keyring: k, dataKeyID: dataKeyID, masterKeyID: "primary", wrapped: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := tt.keyring.Unwrap(tt.dataKeyID, tt.masterKeyID, tt.wrapped)
+			if err == nil {
+				t.Fatal("Unwrap() succeeded, want an error")
+			}
+			if key != nil {
+				t.Fatalf("Unwrap() returned a key with error %v", err)
+			}
+		})
+	}
+}
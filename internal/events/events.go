@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// Broker relays delivery events between processes over Redis pub/sub, so that the
+// API can stream what the worker does
+type Broker struct {
+	redis  *redis.Client
+	logger *logrus.Logger
+}
+
+// NewBroker creates a new Broker
+func NewBroker(redisClient *redis.Client, logger *logrus.Logger) *Broker {
+	return &Broker{
+		redis:  redisClient,
+		logger: logger,
+	}
+}
+
+// channel returns the pub/sub channel for a subscription's delivery events
+func channel(subscriptionID uuid.UUID) string {
+	return fmt.Sprintf("delivery_events:%s", subscriptionID.String())
+}
+
+// Publish publishes a delivery event to the subscription's channel
+func (b *Broker) Publish(ctx context.Context, event models.DeliveryEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.redis.Publish(ctx, channel(event.SubscriptionID), data).Err()
+}
+
+// Subscribe returns a channel of delivery events for a subscription. The channel is
+// closed once ctx is done.
+func (b *Broker) Subscribe(ctx context.Context, subscriptionID uuid.UUID) (<-chan models.DeliveryEvent, error) {
+	pubsub := b.redis.Subscribe(ctx, channel(subscriptionID))
+
+	// Wait for the subscription to be confirmed so no events are missed after returning
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	out := make(chan models.DeliveryEvent)
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event models.DeliveryEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					b.logger.WithError(err).WithField("channel", msg.Channel).Warn("Discarding malformed delivery event")
+					continue
+				}
+
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
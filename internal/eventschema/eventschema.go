@@ -0,0 +1,83 @@
+package eventschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// schemaURL is the URL schemas are compiled under. Relative references resolve
+// against it, and loading anything else is refused.
+const schemaURL = "https://schemas.webhook-delivery.local/event-type.json"
+
+// errExternalRef is returned when a schema references another document
+var errExternalRef = errors.New("references to external schemas are not supported")
+
+// Compile compiles a JSON Schema. Schemas without $schema are read as draft 2020-12.
+func Compile(schema json.RawMessage) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.LoadURL = func(string) (io.ReadCloser, error) {
+		return nil, errExternalRef
+	}
+
+	if err := compiler.AddResource(schemaURL, bytes.NewReader(schema)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(schemaURL)
+}
+
+// Validate validates a JSON document against a compiled schema and returns the
+// violations, or nil if the document is valid
+func Validate(schema *jsonschema.Schema, document json.RawMessage) ([]models.SchemaViolation, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	err := schema.Validate(value)
+	if err == nil {
+		return nil, nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	violations := leaves(validationErr, nil)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+	return violations, nil
+}
+
+// leaves collects the innermost causes of a validation error, which name the
+// failing value and keyword
+func leaves(err *jsonschema.ValidationError, out []models.SchemaViolation) []models.SchemaViolation {
+	if len(err.Causes) == 0 {
+		path := err.InstanceLocation
+		if path == "" {
+			path = "/"
+		}
+		return append(out, models.SchemaViolation{
+			Path:    path,
+			Keyword: err.KeywordLocation,
+			Message: err.Message,
+		})
+	}
+	for _, cause := range err.Causes {
+		out = leaves(cause, out)
+	}
+	return out
+}
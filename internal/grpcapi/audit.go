@@ -0,0 +1,54 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/Unic-X/webhook-delivery/internal/service"
+)
+
+// requestIDKey is the metadata key carrying the ID of a call, generated when the
+// caller sends none
+const requestIDKey = "x-request-id"
+
+// AuditUnaryInterceptor identifies the caller of a call for the audit log, the gRPC
+// counterpart of the REST API's audit context. The actor is read from the metadata
+// key matching actorHeader, and the request ID is sent back in the response header.
+func AuditUnaryInterceptor(actorHeader string) grpc.UnaryServerInterceptor {
+	actorKey := strings.ToLower(actorHeader)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		first := func(key string) string {
+			if values := md.Get(key); len(values) > 0 {
+				return values[0]
+			}
+			return ""
+		}
+
+		actor := service.AuditActor{
+			UserAgent: first("user-agent"),
+			RequestID: first(requestIDKey),
+		}
+		if actorKey != "" {
+			actor.Actor = first(actorKey)
+		}
+		if actor.RequestID == "" {
+			actor.RequestID = uuid.NewString()
+		}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			actor.RemoteAddr = p.Addr.String()
+			if host, _, err := net.SplitHostPort(actor.RemoteAddr); err == nil {
+				actor.RemoteAddr = host
+			}
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, actor.RequestID))
+
+		return handler(service.WithAuditActor(ctx, actor), req)
+	}
+}
@@ -0,0 +1,150 @@
+package grpcapi
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	webhookv1 "github.com/Unic-X/webhook-delivery/internal/pb/webhook/v1"
+)
+
+// subscriptionRequestFromProto converts a SubscriptionRequest message to the model
+// bound by the REST handlers
+func subscriptionRequestFromProto(req *webhookv1.SubscriptionRequest) models.SubscriptionRequest {
+	out := models.SubscriptionRequest{
+		TargetURL:       req.GetTargetUrl(),
+		SecretKey:       req.SecretKey,
+		EventTypes:      req.GetEventTypes(),
+		DestinationType: req.GetDestinationType(),
+		SignatureScheme: req.GetSignatureScheme(),
+	}
+	if req.MaxEventAgeSeconds != nil {
+		seconds := int(req.GetMaxEventAgeSeconds())
+		out.MaxEventAgeSeconds = &seconds
+	}
+	if policy := req.GetRetentionPolicy(); policy != nil {
+		out.RetentionPolicy = &models.RetentionPolicy{
+			DeliveredSeconds: int(policy.GetDeliveredSeconds()),
+			FailedSeconds:    int(policy.GetFailedSeconds()),
+			CancelledSeconds: int(policy.GetCancelledSeconds()),
+			ExpiredSeconds:   int(policy.GetExpiredSeconds()),
+			Mode:             policy.GetMode(),
+		}
+	}
+	if cfg := req.GetDestinationConfig(); cfg != nil {
+		out.DestinationConfig = &models.DestinationConfig{
+			Topic:  cfg.GetTopic(),
+			Key:    cfg.GetKey(),
+			Stream: cfg.GetStream(),
+			MaxLen: cfg.GetMaxLen(),
+		}
+	}
+	return out
+}
+
+// subscriptionToProto converts a subscription to its message. The signing secret is
+// left out, only the hint is set.
+func subscriptionToProto(sub models.Subscription) *webhookv1.Subscription {
+	out := &webhookv1.Subscription{
+		Id:                      sub.ID.String(),
+		TargetUrl:               sub.TargetURL,
+		SecretKeyHint:           sub.SecretKeyHint,
+		EventTypes:              sub.EventTypes,
+		DestinationType:         sub.DestinationType,
+		CreatedAt:               timestamppb.New(sub.CreatedAt),
+		UpdatedAt:               timestamppb.New(sub.UpdatedAt),
+		PreviousSecretExpiresAt: timestampToProto(sub.PreviousSecretExpiresAt),
+		SignatureScheme:         sub.SignatureScheme,
+		MaxEventAgeSeconds:      int32ToProto(sub.MaxEventAgeSeconds),
+	}
+	if cfg := sub.DestinationConfig; cfg != nil {
+		out.DestinationConfig = &webhookv1.DestinationConfig{
+			Topic:  cfg.Topic,
+			Key:    cfg.Key,
+			Stream: cfg.Stream,
+			MaxLen: cfg.MaxLen,
+		}
+	}
+	if policy := sub.RetentionPolicy; policy != nil {
+		out.RetentionPolicy = &webhookv1.RetentionPolicy{
+			DeliveredSeconds: int32(policy.DeliveredSeconds),
+			FailedSeconds:    int32(policy.FailedSeconds),
+			CancelledSeconds: int32(policy.CancelledSeconds),
+			ExpiredSeconds:   int32(policy.ExpiredSeconds),
+			Mode:             policy.Mode,
+		}
+	}
+	return out
+}
+
+func deliveryToProto(delivery models.WebhookDelivery) *webhookv1.WebhookDelivery {
+	return &webhookv1.WebhookDelivery{
+		Id:              delivery.ID.String(),
+		SubscriptionId:  delivery.SubscriptionID.String(),
+		Payload:         delivery.Payload,
+		EventType:       delivery.EventType,
+		CreatedAt:       timestamppb.New(delivery.CreatedAt),
+		Status:          delivery.Status,
+		NextRetryAt:     timestampToProto(delivery.NextRetryAt),
+		RetryCount:      int32(delivery.RetryCount),
+		MaxRetries:      int32(delivery.MaxRetries),
+		PayloadRef:      delivery.PayloadRef,
+		PayloadSha256:   delivery.PayloadSHA256,
+		PayloadSize:     delivery.PayloadSize,
+		ExpiresAt:       timestampToProto(delivery.ExpiresAt),
+		PayloadPurgedAt: timestampToProto(delivery.PayloadPurgedAt),
+	}
+}
+
+func attemptToProto(attempt models.DeliveryAttempt) *webhookv1.DeliveryAttempt {
+	return &webhookv1.DeliveryAttempt{
+		Id:            attempt.ID.String(),
+		DeliveryId:    attempt.DeliveryID.String(),
+		AttemptNumber: int32(attempt.AttemptNumber),
+		Status:        attempt.Status,
+		StatusCode:    int32ToProto(attempt.StatusCode),
+		ErrorDetails:  attempt.ErrorDetails,
+		CreatedAt:     timestamppb.New(attempt.CreatedAt),
+	}
+}
+
+func deliveryEventToProto(event models.DeliveryEvent) *webhookv1.DeliveryEvent {
+	return &webhookv1.DeliveryEvent{
+		Id:             event.ID.String(),
+		Type:           event.Type,
+		DeliveryId:     event.DeliveryID.String(),
+		SubscriptionId: event.SubscriptionID.String(),
+		EventType:      event.EventType,
+		Status:         event.Status,
+		RetryCount:     int32(event.RetryCount),
+		AttemptNumber:  int32(event.AttemptNumber),
+		StatusCode:     int32ToProto(event.StatusCode),
+		ErrorDetails:   event.ErrorDetails,
+		NextRetryAt:    timestampToProto(event.NextRetryAt),
+		Timestamp:      timestamppb.New(event.Timestamp),
+	}
+}
+
+func deliveryIDsToProto(deliveries []models.WebhookDelivery) []string {
+	out := make([]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		out = append(out, delivery.ID.String())
+	}
+	return out
+}
+
+func timestampToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func int32ToProto(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	out := int32(*v)
+	return &out
+}
@@ -0,0 +1,409 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	webhookv1 "github.com/Unic-X/webhook-delivery/internal/pb/webhook/v1"
+	"github.com/Unic-X/webhook-delivery/internal/service"
+)
+
+// defaultDeliveryLimit matches the default limit of the REST deliveries endpoint
+const defaultDeliveryLimit = 20
+
+// Server implements the WebhookService gRPC service on top of service.Service.
+// Requests are validated with the same binding rules as the REST handlers.
+type Server struct {
+	webhookv1.UnimplementedWebhookServiceServer
+
+	service service.Service
+	logger  *logrus.Logger
+}
+
+// NewServer creates a new Server
+func NewServer(service service.Service, logger *logrus.Logger) *Server {
+	return &Server{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateSubscription creates a new webhook subscription
+func (s *Server) CreateSubscription(ctx context.Context, req *webhookv1.CreateSubscriptionRequest) (*webhookv1.CreateSubscriptionResponse, error) {
+	subReq, err := s.bindSubscriptionRequest(req.GetSubscription())
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.service.CreateSubscription(ctx, subReq)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create subscription")
+		if st := validationStatus(err); st != nil {
+			return nil, st
+		}
+		return nil, status.Error(codes.Internal, "Failed to create subscription")
+	}
+
+	// The create response is the only one that carries the secret
+	out := subscriptionToProto(subscription)
+	out.SecretKey = subscription.SecretKey
+	return &webhookv1.CreateSubscriptionResponse{Subscription: out}, nil
+}
+
+// GetSubscription gets a webhook subscription by ID
+func (s *Server) GetSubscription(ctx context.Context, req *webhookv1.GetSubscriptionRequest) (*webhookv1.GetSubscriptionResponse, error) {
+	id, err := parseID(req.GetId(), "Invalid subscription ID")
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.service.GetSubscription(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get subscription")
+		return nil, notFoundOrInternal(err, "Subscription not found", "Failed to get subscription")
+	}
+
+	return &webhookv1.GetSubscriptionResponse{Subscription: subscriptionToProto(subscription)}, nil
+}
+
+// UpdateSubscription updates an existing webhook subscription
+func (s *Server) UpdateSubscription(ctx context.Context, req *webhookv1.UpdateSubscriptionRequest) (*webhookv1.UpdateSubscriptionResponse, error) {
+	id, err := parseID(req.GetId(), "Invalid subscription ID")
+	if err != nil {
+		return nil, err
+	}
+
+	subReq, err := s.bindSubscriptionRequest(req.GetSubscription())
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.service.UpdateSubscription(ctx, id, subReq)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to update subscription")
+		if st := validationStatus(err); st != nil {
+			return nil, st
+		}
+		return nil, notFoundOrInternal(err, "Subscription not found", "Failed to update subscription")
+	}
+
+	return &webhookv1.UpdateSubscriptionResponse{Subscription: subscriptionToProto(subscription)}, nil
+}
+
+// RotateSubscriptionSecret replaces a subscription's signing secret
+func (s *Server) RotateSubscriptionSecret(ctx context.Context, req *webhookv1.RotateSubscriptionSecretRequest) (*webhookv1.RotateSubscriptionSecretResponse, error) {
+	id, err := parseID(req.GetId(), "Invalid subscription ID")
+	if err != nil {
+		return nil, err
+	}
+
+	rotateReq := models.RotateSecretRequest{SecretKey: req.SecretKey}
+	if req.OverlapSeconds != nil {
+		overlap := int(req.GetOverlapSeconds())
+		rotateReq.OverlapSeconds = &overlap
+	}
+	if err := binding.Validator.ValidateStruct(&rotateReq); err != nil {
+		s.logger.WithError(err).Warn("Invalid secret rotation request")
+		return nil, status.Error(codes.InvalidArgument, "Invalid request: "+err.Error())
+	}
+
+	subscription, err := s.service.RotateSubscriptionSecret(ctx, id, rotateReq)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to rotate subscription secret")
+		if st := validationStatus(err); st != nil {
+			return nil, st
+		}
+		return nil, notFoundOrInternal(err, "Subscription not found", "Failed to rotate subscription secret")
+	}
+
+	// Like the create response, the rotation response carries the new secret
+	out := subscriptionToProto(subscription)
+	out.SecretKey = subscription.SecretKey
+	return &webhookv1.RotateSubscriptionSecretResponse{Subscription: out}, nil
+}
+
+// DeleteSubscription deletes a webhook subscription
+func (s *Server) DeleteSubscription(ctx context.Context, req *webhookv1.DeleteSubscriptionRequest) (*webhookv1.DeleteSubscriptionResponse, error) {
+	id, err := parseID(req.GetId(), "Invalid subscription ID")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteSubscription(ctx, id); err != nil {
+		s.logger.WithError(err).Error("Failed to delete subscription")
+		return nil, status.Error(codes.Internal, "Failed to delete subscription")
+	}
+
+	return &webhookv1.DeleteSubscriptionResponse{}, nil
+}
+
+// ListSubscriptions lists all webhook subscriptions
+func (s *Server) ListSubscriptions(ctx context.Context, _ *webhookv1.ListSubscriptionsRequest) (*webhookv1.ListSubscriptionsResponse, error) {
+	subscriptions, err := s.service.ListSubscriptions(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list subscriptions")
+		return nil, status.Error(codes.Internal, "Failed to list subscriptions")
+	}
+
+	resp := &webhookv1.ListSubscriptionsResponse{
+		Subscriptions: make([]*webhookv1.Subscription, 0, len(subscriptions)),
+	}
+	for _, subscription := range subscriptions {
+		resp.Subscriptions = append(resp.Subscriptions, subscriptionToProto(subscription))
+	}
+	return resp, nil
+}
+
+// IngestWebhook queues a webhook for delivery to a single subscription. The
+// signature is verified by the service exactly as for the REST API.
+func (s *Server) IngestWebhook(ctx context.Context, req *webhookv1.IngestWebhookRequest) (*webhookv1.IngestWebhookResponse, error) {
+	id, err := parseID(req.GetSubscriptionId(), "Invalid subscription ID")
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := bindPayload(req.GetPayload(), "Invalid webhook payload")
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := scheduleFromProto(req.GetDeliverAt(), req.GetDelay(), req.GetExpiresAt())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.IngestWebhook(ctx, id, req.GetEventType(), payload, req.GetSignature(), schedule); err != nil {
+		s.logger.WithError(err).Error("Failed to ingest webhook")
+		if err.Error() == "invalid signature" {
+			return nil, status.Error(codes.InvalidArgument, "Invalid signature")
+		}
+		if st := payloadStatus(err); st != nil {
+			return nil, st
+		}
+		return nil, status.Error(codes.Internal, "Failed to process webhook")
+	}
+
+	return &webhookv1.IngestWebhookResponse{Message: "Webhook accepted for processing"}, nil
+}
+
+// PublishEvent fans an event out to all matching subscriptions
+func (s *Server) PublishEvent(ctx context.Context, req *webhookv1.PublishEventRequest) (*webhookv1.PublishEventResponse, error) {
+	if req.GetEventType() == "" {
+		return nil, status.Error(codes.InvalidArgument, "event_type is required")
+	}
+
+	payload, err := bindPayload(req.GetPayload(), "Invalid event payload")
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := scheduleFromProto(req.GetDeliverAt(), req.GetDelay(), req.GetExpiresAt())
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.service.PublishEvent(ctx, req.GetEventType(), payload, schedule)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to publish event")
+		if st := payloadStatus(err); st != nil {
+			return nil, st
+		}
+		return nil, status.Error(codes.Internal, "Failed to publish event")
+	}
+
+	return &webhookv1.PublishEventResponse{
+		Message:     "Event accepted for processing",
+		DeliveryIds: deliveryIDsToProto(deliveries),
+	}, nil
+}
+
+// GetDeliveryStatus gets the status and attempt history of a webhook delivery
+func (s *Server) GetDeliveryStatus(ctx context.Context, req *webhookv1.GetDeliveryStatusRequest) (*webhookv1.GetDeliveryStatusResponse, error) {
+	id, err := parseID(req.GetId(), "Invalid delivery ID")
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryStatus, err := s.service.GetDeliveryStatus(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get delivery status")
+		return nil, notFoundOrInternal(err, "Delivery not found", "Failed to get delivery status")
+	}
+
+	resp := &webhookv1.GetDeliveryStatusResponse{
+		Delivery: deliveryToProto(deliveryStatus.Delivery),
+		Attempts: make([]*webhookv1.DeliveryAttempt, 0, len(deliveryStatus.Attempts)),
+	}
+	for _, attempt := range deliveryStatus.Attempts {
+		resp.Attempts = append(resp.Attempts, attemptToProto(attempt))
+	}
+	return resp, nil
+}
+
+// ListRecentDeliveries gets recent webhook deliveries for a subscription
+func (s *Server) ListRecentDeliveries(ctx context.Context, req *webhookv1.ListRecentDeliveriesRequest) (*webhookv1.ListRecentDeliveriesResponse, error) {
+	id, err := parseID(req.GetSubscriptionId(), "Invalid subscription ID")
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+
+	deliveries, err := s.service.GetRecentDeliveries(ctx, id, limit)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get recent deliveries")
+		return nil, status.Error(codes.Internal, "Failed to get recent deliveries")
+	}
+
+	resp := &webhookv1.ListRecentDeliveriesResponse{
+		Deliveries: make([]*webhookv1.WebhookDelivery, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, deliveryToProto(delivery))
+	}
+	return resp, nil
+}
+
+// WatchDeliveries streams delivery events for a subscription until the client cancels
+func (s *Server) WatchDeliveries(req *webhookv1.WatchDeliveriesRequest, stream webhookv1.WebhookService_WatchDeliveriesServer) error {
+	id, err := parseID(req.GetSubscriptionId(), "Invalid subscription ID")
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+
+	if _, err := s.service.GetSubscription(ctx, id); err != nil {
+		s.logger.WithError(err).Error("Failed to get subscription")
+		return notFoundOrInternal(err, "Subscription not found", "Failed to get subscription")
+	}
+
+	events, err := s.service.StreamDeliveryEvents(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to stream delivery events")
+		return status.Error(codes.Internal, "Failed to stream delivery events")
+	}
+
+	for event := range events {
+		if err := stream.Send(&webhookv1.WatchDeliveriesResponse{Event: deliveryEventToProto(event)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindSubscriptionRequest converts and validates a subscription request with the
+// binding rules used by the REST handlers
+func (s *Server) bindSubscriptionRequest(req *webhookv1.SubscriptionRequest) (models.SubscriptionRequest, error) {
+	if req == nil {
+		return models.SubscriptionRequest{}, status.Error(codes.InvalidArgument, "Invalid request: subscription is required")
+	}
+
+	subReq := subscriptionRequestFromProto(req)
+	if err := binding.Validator.ValidateStruct(&subReq); err != nil {
+		s.logger.WithError(err).Warn("Invalid subscription request")
+		return models.SubscriptionRequest{}, status.Error(codes.InvalidArgument, "Invalid request: "+err.Error())
+	}
+	return subReq, nil
+}
+
+// bindPayload validates a JSON payload with the binding rules used by the REST handlers
+func bindPayload(payload []byte, message string) (json.RawMessage, error) {
+	webhookReq := models.WebhookRequest{Payload: payload}
+	if !json.Valid(payload) || binding.Validator.ValidateStruct(&webhookReq) != nil {
+		return nil, status.Error(codes.InvalidArgument, message)
+	}
+	return webhookReq.Payload, nil
+}
+
+// scheduleFromProto resolves deliver_at, delay and expires_at with the rules of the
+// REST API
+func scheduleFromProto(deliverAt *timestamppb.Timestamp, delay *durationpb.Duration, expiresAt *timestamppb.Timestamp) (models.DeliverySchedule, error) {
+	var webhookReq models.WebhookRequest
+	if deliverAt != nil {
+		t := deliverAt.AsTime()
+		webhookReq.DeliverAt = &t
+	}
+	if delay != nil {
+		webhookReq.Delay = delay.AsDuration().String()
+	}
+	if expiresAt != nil {
+		t := expiresAt.AsTime()
+		webhookReq.ExpiresAt = &t
+	}
+
+	schedule, err := webhookReq.Schedule(time.Now())
+	if err != nil {
+		return schedule, status.Error(codes.InvalidArgument, "Invalid request: "+err.Error())
+	}
+	return schedule, nil
+}
+
+// parseID parses a UUID request field
+func parseID(raw string, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, message)
+	}
+	return id, nil
+}
+
+// validationStatus maps a service.ValidationError to an InvalidArgument status
+func validationStatus(err error) error {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		return status.Error(codes.InvalidArgument, "Invalid request: "+validationErr.Message)
+	}
+	return nil
+}
+
+// payloadStatus maps a payload rejected by the service to an InvalidArgument status,
+// or ResourceExhausted when it is too large. Schema violations are attached as
+// BadRequest field violations keyed by JSON pointer.
+func payloadStatus(err error) error {
+	if errors.Is(err, service.ErrPayloadTooLarge) {
+		return status.Error(codes.ResourceExhausted, "Payload exceeds the maximum ingest size")
+	}
+
+	var schemaErr *service.SchemaValidationError
+	if !errors.As(err, &schemaErr) {
+		return validationStatus(err)
+	}
+
+	st := status.New(codes.InvalidArgument, "Payload does not match the event type schema")
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range schemaErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Path,
+			Description: violation.Message,
+		})
+	}
+	if detailed, detailErr := st.WithDetails(badRequest); detailErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// notFoundOrInternal maps a missing row to NotFound and anything else to Internal
+func notFoundOrInternal(err error, notFound string, internal string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Error(codes.NotFound, notFound)
+	}
+	return status.Error(codes.Internal, internal)
+}
@@ -0,0 +1,234 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Unic-X/webhook-delivery/internal/config"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/internal/service"
+	"github.com/Unic-X/webhook-delivery/internal/tracing"
+)
+
+const (
+	pollTimeout    = 500 * time.Millisecond
+	initialBackoff = 1 * time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// MessageConsumer is the subset of *kafka.Consumer used by the Ingestor, so that a
+// fake consumer can stand in for the broker in tests
+type MessageConsumer interface {
+	SubscribeTopics(topics []string, rebalanceCb ckafka.RebalanceCb) error
+	ReadMessage(timeout time.Duration) (*ckafka.Message, error)
+	CommitMessage(msg *ckafka.Message) ([]ckafka.TopicPartition, error)
+}
+
+// Ingestor reads domain events from Kafka topics and queues them for delivery.
+// A message's offset is committed only once its delivery rows are persisted, so
+// messages are processed at least once.
+type Ingestor struct {
+	consumer MessageConsumer
+	service  service.Service
+	config   *config.Config
+	logger   *logrus.Logger
+}
+
+// NewIngestor creates a new Ingestor
+func NewIngestor(consumer MessageConsumer, svc service.Service, cfg *config.Config, logger *logrus.Logger) *Ingestor {
+	return &Ingestor{
+		consumer: consumer,
+		service:  svc,
+		config:   cfg,
+		logger:   logger,
+	}
+}
+
+// permanentError marks a message that can never be ingested and should be skipped
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Run consumes the configured topics until ctx is done
+func (i *Ingestor) Run(ctx context.Context) error {
+	if err := i.consumer.SubscribeTopics(i.config.KafkaIngestTopics, nil); err != nil {
+		return fmt.Errorf("failed to subscribe to topics: %w", err)
+	}
+
+	i.logger.WithField("topics", i.config.KafkaIngestTopics).Info("Consuming events from Kafka")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		msg, err := i.consumer.ReadMessage(pollTimeout)
+		if err != nil {
+			var kafkaErr ckafka.Error
+			if errors.As(err, &kafkaErr) && kafkaErr.Code() == ckafka.ErrTimedOut {
+				continue
+			}
+			i.logger.WithError(err).Warn("Failed to read message from Kafka")
+			continue
+		}
+
+		if err := i.process(ctx, msg); err != nil {
+			// Only returned when ctx is done, the offset stays uncommitted
+			return nil
+		}
+
+		if _, err := i.consumer.CommitMessage(msg); err != nil {
+			i.logger.WithError(err).WithFields(messageFields(msg)).Error("Failed to commit Kafka offset")
+		}
+	}
+}
+
+// process handles a message, retrying transient failures with backoff until it
+// succeeds, fails permanently or ctx is done
+func (i *Ingestor) process(ctx context.Context, msg *ckafka.Message) error {
+	backoff := initialBackoff
+	for {
+		err := i.handle(ctx, msg)
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			i.logger.WithError(err).WithFields(messageFields(msg)).Warn("Skipping Kafka message that cannot be ingested")
+			return nil
+		}
+
+		i.logger.WithError(err).WithFields(messageFields(msg)).WithField("backoff", backoff).Error("Failed to ingest Kafka message, retrying")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// handle ingests a single message. Messages with a subscription header go to that
+// subscription, all others are fanned out by event type.
+func (i *Ingestor) handle(ctx context.Context, msg *ckafka.Message) error {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+
+	topic := ""
+	if msg.TopicPartition.Topic != nil {
+		topic = *msg.TopicPartition.Topic
+	}
+
+	ctx, span := tracing.Tracer().Start(tracing.Extract(ctx, headers), "kafka.consume "+topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", topic),
+			attribute.Int64("messaging.kafka.offset", int64(msg.TopicPartition.Offset)),
+		),
+	)
+	defer span.End()
+
+	if !json.Valid(msg.Value) {
+		return &permanentError{err: errors.New("message value is not valid JSON")}
+	}
+
+	eventType := i.eventType(msg.Value, headers)
+
+	if rawID, ok := headers[i.config.KafkaSubscriptionHeader]; ok {
+		subscriptionID, err := uuid.Parse(rawID)
+		if err != nil {
+			return &permanentError{err: fmt.Errorf("invalid subscription ID %q: %w", rawID, err)}
+		}
+
+		if err := i.service.IngestWebhook(ctx, subscriptionID, eventType, msg.Value, "", models.DeliverySchedule{}); err != nil {
+			tracing.RecordError(span, err)
+			return classify(err)
+		}
+		return nil
+	}
+
+	if eventType == "" {
+		return &permanentError{err: errors.New("message has neither a subscription nor an event type")}
+	}
+
+	if _, err := i.service.PublishEvent(ctx, eventType, msg.Value, models.DeliverySchedule{}); err != nil {
+		tracing.RecordError(span, err)
+		return classify(err)
+	}
+	return nil
+}
+
+// eventType reads the event type from the configured header, falling back to a
+// top-level string field of the JSON value
+func (i *Ingestor) eventType(value []byte, headers map[string]string) string {
+	if eventType := headers[i.config.KafkaEventTypeHeader]; eventType != "" {
+		return eventType
+	}
+
+	if i.config.KafkaEventTypeField == "" {
+		return ""
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(value, &fields); err != nil {
+		return ""
+	}
+
+	var eventType string
+	if err := json.Unmarshal(fields[i.config.KafkaEventTypeField], &eventType); err != nil {
+		return ""
+	}
+	return eventType
+}
+
+// classify marks service errors that retrying cannot fix as permanent
+func classify(err error) error {
+	var validationErr *service.ValidationError
+	var schemaErr *service.SchemaValidationError
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, service.ErrPayloadTooLarge) ||
+		errors.As(err, &validationErr) || errors.As(err, &schemaErr) {
+		return &permanentError{err: err}
+	}
+	return err
+}
+
+// messageFields returns log fields identifying a message
+func messageFields(msg *ckafka.Message) logrus.Fields {
+	fields := logrus.Fields{
+		"partition": msg.TopicPartition.Partition,
+		"offset":    msg.TopicPartition.Offset,
+	}
+	if msg.TopicPartition.Topic != nil {
+		fields["topic"] = *msg.TopicPartition.Topic
+	}
+	return fields
+}
@@ -0,0 +1,75 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/sirupsen/logrus"
+)
+
+// Producer is the subset of *kafka.Producer used for delivery, so that a fake
+// producer can stand in for the broker in tests
+type Producer interface {
+	Produce(msg *ckafka.Message, deliveryChan chan ckafka.Event) error
+}
+
+// Publisher publishes delivery payloads to Kafka and waits for their delivery reports
+type Publisher struct {
+	producer Producer
+}
+
+// NewPublisher creates a new Publisher
+func NewPublisher(producer Producer) *Publisher {
+	return &Publisher{producer: producer}
+}
+
+// Publish produces a message and blocks until the broker acknowledges or rejects it,
+// or ctx is done
+func (p *Publisher) Publish(ctx context.Context, topic string, key string, payload []byte, headers map[string]string) error {
+	msg := &ckafka.Message{
+		TopicPartition: ckafka.TopicPartition{Topic: &topic, Partition: ckafka.PartitionAny},
+		Value:          payload,
+	}
+	if key != "" {
+		msg.Key = []byte(key)
+	}
+	for name, value := range headers {
+		msg.Headers = append(msg.Headers, ckafka.Header{Key: name, Value: []byte(value)})
+	}
+
+	// Buffered so the producer never blocks on a report nobody waits for any more
+	reports := make(chan ckafka.Event, 1)
+	if err := p.producer.Produce(msg, reports); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case event := <-reports:
+		switch ev := event.(type) {
+		case *ckafka.Message:
+			return ev.TopicPartition.Error
+		case ckafka.Error:
+			return ev
+		default:
+			return fmt.Errorf("unexpected delivery report: %v", ev)
+		}
+	}
+}
+
+// LogEvents logs producer level events such as broker connection errors until the
+// producer's event channel is closed
+func LogEvents(producer *ckafka.Producer, logger *logrus.Logger) {
+	for event := range producer.Events() {
+		switch ev := event.(type) {
+		case ckafka.Error:
+			logger.WithError(ev).Warn("Kafka producer error")
+		case *ckafka.Message:
+			if ev.TopicPartition.Error != nil {
+				logger.WithError(ev.TopicPartition.Error).Warn("Kafka delivery failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+const namespace = "webhook"
+
+// Ingest outcomes
+const (
+	OutcomeAccepted            = "accepted"
+	OutcomeFiltered            = "filtered"
+	OutcomeInvalidSignature    = "invalid_signature"
+	OutcomeSchemaInvalid       = "schema_invalid"
+	OutcomePayloadTooLarge     = "payload_too_large"
+	OutcomeUnknownSubscription = "unknown_subscription"
+	OutcomeEnqueueFailed       = "enqueue_failed"
+	OutcomeError               = "error"
+)
+
+var (
+	// WebhooksIngested counts ingested webhooks by outcome
+	WebhooksIngested = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ingest_total",
+		Help:      "Number of ingested webhooks by outcome.",
+	}, []string{"outcome"})
+
+	// EnqueueFailures counts failures to enqueue delivery tasks
+	EnqueueFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "enqueue_failures_total",
+		Help:      "Number of delivery tasks that could not be enqueued, by source.",
+	}, []string{"source"})
+
+	// DeliveryAttempts counts delivery attempts by status class and subscription
+	DeliveryAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "delivery_attempts_total",
+		Help:      "Number of delivery attempts by response status class (ok or error for destinations without status codes) and subscription.",
+	}, []string{"status_class", "subscription_id"})
+
+	// DeliveryDuration observes the duration of outbound delivery requests
+	DeliveryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "delivery_duration_seconds",
+		Help:      "Duration of outbound delivery requests by response status class (ok or error for destinations without status codes).",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"status_class"})
+
+	// RetriesScheduled counts deliveries scheduled for another attempt
+	RetriesScheduled = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "retries_scheduled_total",
+		Help:      "Number of deliveries scheduled for retry.",
+	})
+
+	// DeadLettered counts deliveries that exhausted their retries
+	DeadLettered = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dead_lettered_total",
+		Help:      "Number of deliveries marked as failed after exhausting their retries.",
+	})
+
+	// DeliveriesExpired counts deliveries that expired before they could be sent, by
+	// the limit that expired them
+	DeliveriesExpired = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "deliveries_expired_total",
+		Help:      "Number of deliveries expired before they could be sent, by reason (expires_at or max_event_age).",
+	}, []string{"reason"})
+
+	// DeliveriesPurged counts deliveries removed by the retention policy, by status
+	// and mode
+	DeliveriesPurged = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "deliveries_purged_total",
+		Help:      "Number of deliveries deleted or stripped of their payload by the retention policy, by status and mode (delete or strip_payload).",
+	}, []string{"status", "mode"})
+
+	// DeliveriesArchived counts deliveries written to the archive sink before retention
+	// removed them
+	DeliveriesArchived = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "deliveries_archived_total",
+		Help:      "Number of deliveries written to the archive sink before the retention policy removed them.",
+	})
+
+	// CacheRequests counts subscription cache lookups by result. The hit ratio is
+	// hit / (hit + miss).
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Number of subscription cache lookups by result (hit or miss).",
+	}, []string{"result"})
+
+	// SecretReveals counts subscription signing secrets revealed through the API
+	SecretReveals = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "secret_reveals_total",
+		Help:      "Number of subscription signing secrets revealed through the API.",
+	})
+
+	// AuditWriteFailures counts audit events that could not be written to the audit
+	// log. The event is logged instead.
+	AuditWriteFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audit_write_failures_total",
+		Help:      "Number of audit events that could not be written to the audit log.",
+	})
+)
+
+// StatusClass maps an HTTP status code to its class label, e.g. 503 to "5xx".
+// A nil status code means no response was received.
+func StatusClass(statusCode *int) string {
+	if statusCode == nil {
+		return "error"
+	}
+	switch {
+	case *statusCode >= 500:
+		return "5xx"
+	case *statusCode >= 400:
+		return "4xx"
+	case *statusCode >= 300:
+		return "3xx"
+	case *statusCode >= 200:
+		return "2xx"
+	default:
+		return "1xx"
+	}
+}
+
+// ResultClass returns the status class label for a delivery result. Destinations
+// without status codes are labelled "ok" or "error".
+func ResultClass(statusCode *int, err error) string {
+	if statusCode != nil {
+		return StatusClass(statusCode)
+	}
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// scrapeTimeout bounds the time collectors spend querying PostgreSQL
+const scrapeTimeout = 5 * time.Second
+
+// QueueCollector exposes asynq queue depth by queue and task state
+type QueueCollector struct {
+	inspector *asynq.Inspector
+	logger    *logrus.Logger
+	depth     *prometheus.Desc
+}
+
+// NewQueueCollector creates a new QueueCollector
+func NewQueueCollector(inspector *asynq.Inspector, logger *logrus.Logger) *QueueCollector {
+	return &QueueCollector{
+		inspector: inspector,
+		logger:    logger,
+		depth: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "depth"),
+			"Number of tasks in the asynq queue by state.",
+			[]string{"queue", "state"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.depth
+}
+
+// Collect implements prometheus.Collector
+func (c *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	queues, err := c.inspector.Queues()
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to list queues for metrics")
+		return
+	}
+
+	for _, queue := range queues {
+		info, err := c.inspector.GetQueueInfo(queue)
+		if err != nil {
+			c.logger.WithError(err).WithField("queue", queue).Warn("Failed to get queue info for metrics")
+			continue
+		}
+
+		states := map[string]int{
+			"pending":   info.Pending,
+			"active":    info.Active,
+			"scheduled": info.Scheduled,
+			"retry":     info.Retry,
+			"archived":  info.Archived,
+		}
+		for state, count := range states {
+			ch <- prometheus.MustNewConstMetric(c.depth, prometheus.GaugeValue, float64(count), queue, state)
+		}
+	}
+}
+
+// DeliveryCounter counts deliveries in a given status
+type DeliveryCounter interface {
+	CountDeliveriesByStatus(ctx context.Context, status string) (int64, error)
+}
+
+// DeadLetterCollector exposes the number of deliveries that exhausted their retries
+type DeadLetterCollector struct {
+	counter DeliveryCounter
+	logger  *logrus.Logger
+	size    *prometheus.Desc
+}
+
+// NewDeadLetterCollector creates a new DeadLetterCollector
+func NewDeadLetterCollector(counter DeliveryCounter, logger *logrus.Logger) *DeadLetterCollector {
+	return &DeadLetterCollector{
+		counter: counter,
+		logger:  logger,
+		size: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "dlq", "size"),
+			"Number of deliveries in the FAILED state.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *DeadLetterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.size
+}
+
+// Collect implements prometheus.Collector
+func (c *DeadLetterCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	count, err := c.counter.CountDeliveriesByStatus(ctx, models.StatusFailed)
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to count failed deliveries for metrics")
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(count))
+}
@@ -12,12 +12,202 @@ import (
 
 // Subscription represents a webhook subscription
 type Subscription struct {
-	ID         uuid.UUID   `json:"id" db:"id"`
-	TargetURL  string      `json:"target_url" db:"target_url"`
-	SecretKey  *string     `json:"secret_key,omitempty" db:"secret_key"`
-	EventTypes StringArray `json:"event_types,omitempty" db:"event_types"`
-	CreatedAt  time.Time   `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time   `json:"updated_at" db:"updated_at"`
+	ID                      uuid.UUID          `json:"id" db:"id"`
+	TargetURL               string             `json:"target_url" db:"target_url"`
+	SecretKey               *string            `json:"-" db:"secret_key"`
+	SecretKeyHint           string             `json:"secret_key_hint,omitempty" db:"-"`
+	PreviousSecretKey       *string            `json:"-" db:"previous_secret_key"`
+	PreviousSecretExpiresAt *time.Time         `json:"previous_secret_expires_at,omitempty" db:"previous_secret_expires_at"`
+	SignatureScheme         string             `json:"signature_scheme" db:"signature_scheme"`
+	EventTypes              StringArray        `json:"event_types,omitempty" db:"event_types"`
+	DestinationType         string             `json:"destination_type" db:"destination_type"`
+	DestinationConfig       *DestinationConfig `json:"destination_config,omitempty" db:"destination_config"`
+	MaxEventAgeSeconds      *int               `json:"max_event_age_seconds,omitempty" db:"max_event_age_seconds"`
+	RetentionPolicy         *RetentionPolicy   `json:"retention_policy,omitempty" db:"retention_policy"`
+	DataKeyID               *uuid.UUID         `json:"-" db:"data_key_id"`
+	CreatedAt               time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt               time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// secretHintLength is the number of trailing secret characters shown in a hint
+const secretHintLength = 4
+
+// SetSecretKeyHint fills SecretKeyHint from the secret key. The hint keeps the
+// secret's prefix and last characters so a secret can be recognised without
+// being returned.
+func (s *Subscription) SetSecretKeyHint() {
+	s.SecretKeyHint = ""
+	if s.SecretKey == nil || *s.SecretKey == "" {
+		return
+	}
+	secret := *s.SecretKey
+	if len(secret) < 4*secretHintLength {
+		s.SecretKeyHint = "****"
+		return
+	}
+	prefix := ""
+	if i := strings.Index(secret, "_"); i > 0 && i < len(secret)-secretHintLength {
+		prefix = secret[:i+1]
+	}
+	s.SecretKeyHint = prefix + "..." + secret[len(secret)-secretHintLength:]
+}
+
+// SigningSecrets returns the secrets deliveries are signed with at now, the current
+// secret first followed by the previous one while it has not expired
+func (s *Subscription) SigningSecrets(now time.Time) []string {
+	var secrets []string
+	if s.SecretKey != nil && *s.SecretKey != "" {
+		secrets = append(secrets, *s.SecretKey)
+	}
+	if s.PreviousSecretKey != nil && *s.PreviousSecretKey != "" &&
+		s.PreviousSecretExpiresAt != nil && now.Before(*s.PreviousSecretExpiresAt) {
+		secrets = append(secrets, *s.PreviousSecretKey)
+	}
+	return secrets
+}
+
+// MaxEventAge returns how long after creation the subscription's deliveries expire,
+// zero when they do not
+func (s *Subscription) MaxEventAge() time.Duration {
+	if s.MaxEventAgeSeconds == nil {
+		return 0
+	}
+	return time.Duration(*s.MaxEventAgeSeconds) * time.Second
+}
+
+// CreatedSubscription is returned when a subscription is created. It is the only
+// response that includes the signing secret.
+type CreatedSubscription struct {
+	Subscription
+	SecretKey *string `json:"secret_key,omitempty"`
+}
+
+// Constants for destination types
+const (
+	DestinationHTTP        = "http"
+	DestinationKafka       = "kafka"
+	DestinationRedisStream = "redis_stream"
+)
+
+// Constants for signature schemes of HTTP deliveries
+const (
+	// SignatureSchemeGitHub signs the body into X-Hub-Signature-256
+	SignatureSchemeGitHub = "github"
+	// SignatureSchemeStandardWebhooks signs the delivery ID, timestamp and body into
+	// the webhook-id, webhook-timestamp and webhook-signature headers
+	SignatureSchemeStandardWebhooks = "standard_webhooks"
+	// SignatureSchemeEd25519 uses the Standard Webhooks headers with Ed25519
+	// signatures from the service's signing keys instead of the shared secret
+	SignatureSchemeEd25519 = "ed25519"
+)
+
+// DestinationConfig holds the settings for non-HTTP destinations
+type DestinationConfig struct {
+	// Topic is the Kafka topic deliveries are published to
+	Topic string `json:"topic,omitempty"`
+	// Key is the Kafka message key, messages are unkeyed when empty
+	Key string `json:"key,omitempty"`
+	// Stream is the Redis stream deliveries are appended to
+	Stream string `json:"stream,omitempty"`
+	// MaxLen approximately caps the Redis stream length, unbounded when zero
+	MaxLen int64 `json:"max_len,omitempty"`
+}
+
+// Value converts the DestinationConfig to JSONB
+func (d DestinationConfig) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan scans JSONB into the DestinationConfig
+func (d *DestinationConfig) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, d)
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	default:
+		return errors.New("unsupported type for DestinationConfig")
+	}
+}
+
+// Constants for what a retention policy does with deliveries past their retention
+const (
+	// RetentionModeDelete deletes the delivery and its attempts
+	RetentionModeDelete = "delete"
+	// RetentionModeStripPayload removes the payload but keeps the delivery and its
+	// attempts
+	RetentionModeStripPayload = "strip_payload"
+)
+
+// RetentionPolicy is how long deliveries in each terminal state are kept, in seconds,
+// and what happens to them after that. Zero values are taken from the global policy,
+// a global zero keeps the deliveries forever.
+type RetentionPolicy struct {
+	DeliveredSeconds int    `json:"delivered_seconds,omitempty" binding:"omitempty,min=0"`
+	FailedSeconds    int    `json:"failed_seconds,omitempty" binding:"omitempty,min=0"`
+	CancelledSeconds int    `json:"cancelled_seconds,omitempty" binding:"omitempty,min=0"`
+	ExpiredSeconds   int    `json:"expired_seconds,omitempty" binding:"omitempty,min=0"`
+	Mode             string `json:"mode,omitempty" binding:"omitempty,oneof=delete strip_payload"`
+}
+
+// RetentionStatuses are the terminal delivery states retention applies to
+var RetentionStatuses = []string{StatusDelivered, StatusFailed, StatusCancelled, StatusExpired}
+
+// ActiveStatuses are the delivery states that are not final
+var ActiveStatuses = []string{StatusPending, StatusProcessing, StatusScheduled}
+
+// Retention returns how long deliveries in a terminal state are kept, zero when they
+// are kept forever
+func (p RetentionPolicy) Retention(status string) time.Duration {
+	var seconds int
+	switch status {
+	case StatusDelivered:
+		seconds = p.DeliveredSeconds
+	case StatusFailed:
+		seconds = p.FailedSeconds
+	case StatusCancelled:
+		seconds = p.CancelledSeconds
+	case StatusExpired:
+		seconds = p.ExpiredSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Override returns the policy with the non-zero fields of override applied
+func (p RetentionPolicy) Override(override RetentionPolicy) RetentionPolicy {
+	if override.DeliveredSeconds > 0 {
+		p.DeliveredSeconds = override.DeliveredSeconds
+	}
+	if override.FailedSeconds > 0 {
+		p.FailedSeconds = override.FailedSeconds
+	}
+	if override.CancelledSeconds > 0 {
+		p.CancelledSeconds = override.CancelledSeconds
+	}
+	if override.ExpiredSeconds > 0 {
+		p.ExpiredSeconds = override.ExpiredSeconds
+	}
+	if override.Mode != "" {
+		p.Mode = override.Mode
+	}
+	return p
+}
+
+// Value converts the RetentionPolicy to JSONB
+func (p RetentionPolicy) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan scans JSONB into the RetentionPolicy
+func (p *RetentionPolicy) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return errors.New("unsupported type for RetentionPolicy")
+	}
 }
 
 // StringArray is a type for handling string arrays in PostgreSQL
@@ -69,17 +259,57 @@ func (s *StringArray) Scan(src interface{}) error {
 	}
 }
 
-// WebhookDelivery represents a webhook payload to be delivered
+// WebhookDelivery represents a webhook payload to be delivered. Payloads above the
+// offload threshold are kept in the blob store and Payload is empty. When encryption
+// at rest is enabled, the stored payload is PayloadCiphertext and the repository
+// decrypts it into Payload.
 type WebhookDelivery struct {
-	ID             uuid.UUID       `json:"id" db:"id"`
-	SubscriptionID uuid.UUID       `json:"subscription_id" db:"subscription_id"`
-	Payload        json.RawMessage `json:"payload" db:"payload"`
-	EventType      *string         `json:"event_type,omitempty" db:"event_type"`
-	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
-	Status         string          `json:"status" db:"status"`
-	NextRetryAt    *time.Time      `json:"next_retry_at,omitempty" db:"next_retry_at"`
-	RetryCount     int             `json:"retry_count" db:"retry_count"`
-	MaxRetries     int             `json:"max_retries" db:"max_retries"`
+	ID                uuid.UUID       `json:"id" db:"id"`
+	SubscriptionID    uuid.UUID       `json:"subscription_id" db:"subscription_id"`
+	Payload           json.RawMessage `json:"payload" db:"payload"`
+	PayloadCiphertext []byte          `json:"-" db:"payload_ciphertext"`
+	PayloadRef        *string         `json:"payload_ref,omitempty" db:"payload_ref"`
+	PayloadSHA256     *string         `json:"payload_sha256,omitempty" db:"payload_sha256"`
+	PayloadSize       *int64          `json:"payload_size,omitempty" db:"payload_size"`
+	PayloadPurgedAt   *time.Time      `json:"payload_purged_at,omitempty" db:"payload_purged_at"`
+	EventType         *string         `json:"event_type,omitempty" db:"event_type"`
+	CreatedAt         time.Time       `json:"created_at" db:"created_at"`
+	ExpiresAt         *time.Time      `json:"expires_at,omitempty" db:"expires_at"`
+	Status            string          `json:"status" db:"status"`
+	NextRetryAt       *time.Time      `json:"next_retry_at,omitempty" db:"next_retry_at"`
+	RetryCount        int             `json:"retry_count" db:"retry_count"`
+	MaxRetries        int             `json:"max_retries" db:"max_retries"`
+}
+
+// IsOffloaded reports whether the delivery's payload is kept in the blob store
+func (d *WebhookDelivery) IsOffloaded() bool {
+	return d.PayloadRef != nil
+}
+
+// Expiry returns when the delivery expires, the earlier of its expires_at and its
+// creation plus maxAge, and which of them it is. The time is zero when it does not
+// expire.
+func (d *WebhookDelivery) Expiry(maxAge time.Duration) (time.Time, string) {
+	var expiry time.Time
+	var reason string
+	if d.ExpiresAt != nil {
+		expiry, reason = *d.ExpiresAt, "expires_at"
+	}
+	if maxAge > 0 {
+		if byAge := d.CreatedAt.Add(maxAge); expiry.IsZero() || byAge.Before(expiry) {
+			expiry, reason = byAge, "max_event_age"
+		}
+	}
+	return expiry, reason
+}
+
+// DataKey is a subscription's data encryption key, stored wrapped by a master key
+type DataKey struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	MasterKeyID string     `json:"master_key_id" db:"master_key_id"`
+	WrappedKey  []byte     `json:"-" db:"wrapped_key"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	RotatedAt   *time.Time `json:"rotated_at,omitempty" db:"rotated_at"`
 }
 
 // DeliveryAttempt represents an attempt to deliver a webhook
@@ -93,6 +323,16 @@ type DeliveryAttempt struct {
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 }
 
+// ArchivedDelivery is a delivery as written to the archive before retention removed
+// it, with the attempts still recorded at the time. An offloaded payload is inlined in
+// Delivery.Payload. With encryption at rest, the archive file holds the payload in
+// PayloadCiphertext instead, and it is decrypted again when the archive is read back.
+type ArchivedDelivery struct {
+	Delivery          WebhookDelivery   `json:"delivery"`
+	Attempts          []DeliveryAttempt `json:"attempts"`
+	PayloadCiphertext []byte            `json:"payload_ciphertext,omitempty" swaggerignore:"true"`
+}
+
 // Constants for status values
 const (
 	StatusPending    = "PENDING"
@@ -100,18 +340,157 @@ const (
 	StatusDelivered  = "DELIVERED"
 	StatusFailed     = "FAILED"
 	StatusSuccess    = "SUCCESS"
+	// StatusScheduled is a delivery waiting for its deliver_at time
+	StatusScheduled = "SCHEDULED"
+	// StatusCancelled is a scheduled delivery cancelled before it fired
+	StatusCancelled = "CANCELLED"
+	// StatusExpired is a delivery, and the attempt that found it, that expired before
+	// it could be sent
+	StatusExpired = "EXPIRED"
 )
 
+// Constants for delivery event types published on state changes
+const (
+	EventDeliveryCreated        = "delivery.created"
+	EventDeliveryProcessing     = "delivery.processing"
+	EventAttemptSucceeded       = "attempt.succeeded"
+	EventAttemptFailed          = "attempt.failed"
+	EventDeliveryRetryScheduled = "delivery.retry_scheduled"
+	EventDeliveryDeadLettered   = "delivery.dead_lettered"
+	EventDeliveryReplayed       = "delivery.replayed"
+	EventDeliveryCancelled      = "delivery.cancelled"
+	EventDeliveryExpired        = "delivery.expired"
+)
+
+// DeliveryEvent describes a state change of a webhook delivery or the result of an attempt
+type DeliveryEvent struct {
+	ID             uuid.UUID  `json:"id"`
+	Type           string     `json:"type"`
+	DeliveryID     uuid.UUID  `json:"delivery_id"`
+	SubscriptionID uuid.UUID  `json:"subscription_id"`
+	EventType      *string    `json:"event_type,omitempty"`
+	Status         string     `json:"status"`
+	RetryCount     int        `json:"retry_count"`
+	AttemptNumber  int        `json:"attempt_number,omitempty"`
+	StatusCode     *int       `json:"status_code,omitempty"`
+	ErrorDetails   *string    `json:"error_details,omitempty"`
+	NextRetryAt    *time.Time `json:"next_retry_at,omitempty"`
+	Timestamp      time.Time  `json:"timestamp"`
+}
+
 // SubscriptionRequest is used for creating/updating a subscription
 type SubscriptionRequest struct {
-	TargetURL  string   `json:"target_url" binding:"required,url"`
-	SecretKey  *string  `json:"secret_key,omitempty"`
-	EventTypes []string `json:"event_types,omitempty"`
+	TargetURL         string             `json:"target_url,omitempty" binding:"omitempty,url"`
+	SecretKey         *string            `json:"secret_key,omitempty"`
+	EventTypes        []string           `json:"event_types,omitempty"`
+	DestinationType   string             `json:"destination_type,omitempty" binding:"omitempty,oneof=http kafka redis_stream"`
+	DestinationConfig *DestinationConfig `json:"destination_config,omitempty"`
+	SignatureScheme   string             `json:"signature_scheme,omitempty" binding:"omitempty,oneof=github standard_webhooks ed25519"`
+	// MaxEventAgeSeconds expires deliveries that could not be sent within this many
+	// seconds of being created. Zero removes the limit.
+	MaxEventAgeSeconds *int `json:"max_event_age_seconds,omitempty" binding:"omitempty,min=0"`
+	// RetentionPolicy overrides the global retention of the subscription's
+	// deliveries. An empty policy removes the override.
+	RetentionPolicy *RetentionPolicy `json:"retention_policy,omitempty"`
+}
+
+// RevealSecretRequest records why a subscription's signing secret is revealed
+type RevealSecretRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
 }
 
-// WebhookRequest is used for incoming webhook payloads
+// RevealSecretResponse contains a subscription's signing secret
+type RevealSecretResponse struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	SecretKey      string    `json:"secret_key"`
+}
+
+// RotateSecretRequest replaces a subscription's signing secret. The new secret is
+// generated when SecretKey is empty, and the old one stays valid for
+// OverlapSeconds, or SECRET_ROTATION_OVERLAP when not set.
+type RotateSecretRequest struct {
+	SecretKey      *string `json:"secret_key,omitempty"`
+	OverlapSeconds *int    `json:"overlap_seconds,omitempty" binding:"omitempty,min=0,max=2592000"`
+}
+
+// RotateSecretResponse contains the new signing secret and when the previous one
+// expires. It is the only response that includes the new secret.
+type RotateSecretResponse struct {
+	SubscriptionID          uuid.UUID  `json:"subscription_id"`
+	SecretKey               string     `json:"secret_key"`
+	SecretKeyHint           string     `json:"secret_key_hint"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"`
+}
+
+// WebhookRequest is used for incoming webhook payloads. The payload is delivered at
+// DeliverAt or after Delay, a duration such as "90m", and right away when neither is
+// set. It is not delivered after ExpiresAt.
 type WebhookRequest struct {
-	Payload json.RawMessage `json:"payload" binding:"required"`
+	Payload   json.RawMessage `json:"payload" binding:"required"`
+	DeliverAt *time.Time      `json:"deliver_at,omitempty"`
+	Delay     string          `json:"delay,omitempty" example:"24h"`
+	ExpiresAt *time.Time      `json:"expires_at,omitempty"`
+}
+
+// DeliverySchedule is when an ingested or published payload is delivered. A zero
+// DeliverAt delivers right away, a zero ExpiresAt never expires.
+type DeliverySchedule struct {
+	DeliverAt time.Time
+	ExpiresAt time.Time
+}
+
+// ErrInvalidSchedule is returned when a webhook request has both deliver_at and
+// delay, or a delay that is not a positive duration
+var ErrInvalidSchedule = errors.New("set either deliver_at or delay, delay must be a positive duration such as 90m")
+
+// Schedule returns the delivery schedule of the request
+func (r WebhookRequest) Schedule(now time.Time) (DeliverySchedule, error) {
+	var schedule DeliverySchedule
+	switch {
+	case r.DeliverAt != nil && r.Delay != "":
+		return schedule, ErrInvalidSchedule
+	case r.DeliverAt != nil:
+		schedule.DeliverAt = *r.DeliverAt
+	case r.Delay != "":
+		delay, err := time.ParseDuration(r.Delay)
+		if err != nil || delay <= 0 {
+			return schedule, ErrInvalidSchedule
+		}
+		schedule.DeliverAt = now.Add(delay)
+	}
+	if r.ExpiresAt != nil {
+		schedule.ExpiresAt = *r.ExpiresAt
+	}
+	return schedule, nil
+}
+
+// ScheduledDeliveriesRequest filters the scheduled deliveries listed, which are
+// ordered by when they fire
+type ScheduledDeliveriesRequest struct {
+	SubscriptionID *uuid.UUID `form:"subscription_id"`
+	Limit          int        `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// PublishResponse lists the deliveries queued for a published event
+type PublishResponse struct {
+	Message     string      `json:"message"`
+	DeliveryIDs []uuid.UUID `json:"delivery_ids"`
+}
+
+// ReplayRequest selects failed deliveries to replay. Deliveries are matched on their
+// creation time, and at most Limit are replayed per request.
+type ReplayRequest struct {
+	SubscriptionID *uuid.UUID `json:"subscription_id,omitempty"`
+	EventType      string     `json:"event_type,omitempty"`
+	Since          *time.Time `json:"since,omitempty"`
+	Until          *time.Time `json:"until,omitempty"`
+	Limit          int        `json:"limit,omitempty" binding:"omitempty,min=1,max=1000"`
+}
+
+// ReplayResponse lists the deliveries queued again by a replay
+type ReplayResponse struct {
+	Message     string      `json:"message"`
+	DeliveryIDs []uuid.UUID `json:"delivery_ids"`
 }
 
 // DeliveryStatusResponse contains the delivery status and attempts
@@ -124,3 +503,116 @@ type DeliveryStatusResponse struct {
 type DeliveryListResponse struct {
 	Deliveries []WebhookDelivery `json:"deliveries"`
 }
+
+// EventType is an entry in the event type catalog
+type EventType struct {
+	Name          string             `json:"name" db:"name"`
+	Description   string             `json:"description" db:"description"`
+	LatestVersion int                `json:"latest_version" db:"latest_version"`
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" db:"updated_at"`
+	Versions      []EventTypeVersion `json:"versions,omitempty" db:"-"`
+}
+
+// EventTypeVersion is a version of an event type's payload schema
+type EventTypeVersion struct {
+	EventType string          `json:"event_type" db:"event_type"`
+	Version   int             `json:"version" db:"version"`
+	Schema    json.RawMessage `json:"schema" db:"schema" swaggertype:"object"`
+	Examples  json.RawMessage `json:"examples" db:"examples" swaggertype:"array,object"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// EventTypeRequest is used for registering an event type with its first schema version
+type EventTypeRequest struct {
+	Name        string            `json:"name" binding:"required,max=200"`
+	Description string            `json:"description,omitempty"`
+	Schema      json.RawMessage   `json:"schema" binding:"required" swaggertype:"object"`
+	Examples    []json.RawMessage `json:"examples,omitempty" swaggertype:"array,object"`
+}
+
+// EventTypeVersionRequest is used for adding a schema version to an event type
+type EventTypeVersionRequest struct {
+	Schema   json.RawMessage   `json:"schema" binding:"required" swaggertype:"object"`
+	Examples []json.RawMessage `json:"examples,omitempty" swaggertype:"array,object"`
+}
+
+// SchemaViolation describes a value of a payload that does not match its event type's schema
+type SchemaViolation struct {
+	// Path is the JSON pointer of the offending value
+	Path string `json:"path"`
+	// Keyword is the schema location of the failing keyword
+	Keyword string `json:"keyword"`
+	Message string `json:"message"`
+}
+
+// DeliveryStats is an hourly rollup of deliveries and attempts for a subscription and event type
+type DeliveryStats struct {
+	SubscriptionID      uuid.UUID `json:"subscription_id" db:"subscription_id"`
+	EventType           string    `json:"event_type" db:"event_type"`
+	BucketStart         time.Time `json:"bucket_start" db:"bucket_start"`
+	DeliveriesTotal     int       `json:"deliveries_total" db:"deliveries_total"`
+	DeliveriesDelivered int       `json:"deliveries_delivered" db:"deliveries_delivered"`
+	DeliveriesFailed    int       `json:"deliveries_failed" db:"deliveries_failed"`
+	AttemptsTotal       int       `json:"attempts_total" db:"attempts_total"`
+	AttemptsSucceeded   int       `json:"attempts_succeeded" db:"attempts_succeeded"`
+	AttemptsFailed      int       `json:"attempts_failed" db:"attempts_failed"`
+}
+
+// DeliveryStatsResponse contains hourly delivery statistics for a subscription
+type DeliveryStatsResponse struct {
+	SubscriptionID uuid.UUID       `json:"subscription_id"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	Buckets        []DeliveryStats `json:"buckets"`
+}
+
+// Constants for audited actions
+const (
+	AuditSubscriptionCreated        = "subscription.created"
+	AuditSubscriptionUpdated        = "subscription.updated"
+	AuditSubscriptionDeleted        = "subscription.deleted"
+	AuditSubscriptionSecretRevealed = "subscription.secret_revealed"
+	AuditSubscriptionSecretRotated  = "subscription.secret_rotated"
+	AuditEventTypeCreated           = "event_type.created"
+	AuditEventTypeVersionAdded      = "event_type.version_added"
+	AuditDeliveryReplayed           = "delivery.replayed"
+	AuditDeliveriesReplayed         = "deliveries.replayed"
+	AuditDeliveryCancelled          = "delivery.cancelled"
+)
+
+// Constants for the types of audited resources
+const (
+	AuditResourceSubscription = "subscription"
+	AuditResourceEventType    = "event_type"
+	AuditResourceDelivery     = "delivery"
+)
+
+// AuditEvent records a management operation, who made it and what it changed.
+// Before and After never contain signing secrets.
+type AuditEvent struct {
+	ID           uuid.UUID       `json:"id" db:"id"`
+	Action       string          `json:"action" db:"action"`
+	ResourceType string          `json:"resource_type" db:"resource_type"`
+	ResourceID   string          `json:"resource_id,omitempty" db:"resource_id"`
+	Actor        string          `json:"actor,omitempty" db:"actor"`
+	SourceIP     string          `json:"source_ip,omitempty" db:"source_ip"`
+	UserAgent    string          `json:"user_agent,omitempty" db:"user_agent"`
+	RequestID    string          `json:"request_id,omitempty" db:"request_id"`
+	Before       json.RawMessage `json:"before,omitempty" db:"before" swaggertype:"object"`
+	After        json.RawMessage `json:"after,omitempty" db:"after" swaggertype:"object"`
+	Metadata     json.RawMessage `json:"metadata,omitempty" db:"metadata" swaggertype:"object"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditEventsRequest filters the audit events listed, which are ordered newest first
+type AuditEventsRequest struct {
+	Action       string     `form:"action"`
+	ResourceType string     `form:"resource_type"`
+	ResourceID   string     `form:"resource_id"`
+	Actor        string     `form:"actor"`
+	RequestID    string     `form:"request_id"`
+	Since        *time.Time `form:"since"`
+	Until        *time.Time `form:"until"`
+	Limit        int        `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
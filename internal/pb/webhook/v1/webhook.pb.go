@@ -0,0 +1,2230 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: webhook/v1/webhook.proto
+
+package webhookv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DestinationConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Kafka topic and optional message key
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Key   string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// Redis stream and optional approximate maximum length
+	Stream        string `protobuf:"bytes,3,opt,name=stream,proto3" json:"stream,omitempty"`
+	MaxLen        int64  `protobuf:"varint,4,opt,name=max_len,json=maxLen,proto3" json:"max_len,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DestinationConfig) Reset() {
+	*x = DestinationConfig{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestinationConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestinationConfig) ProtoMessage() {}
+
+func (x *DestinationConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestinationConfig.ProtoReflect.Descriptor instead.
+func (*DestinationConfig) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DestinationConfig) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *DestinationConfig) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *DestinationConfig) GetStream() string {
+	if x != nil {
+		return x.Stream
+	}
+	return ""
+}
+
+func (x *DestinationConfig) GetMaxLen() int64 {
+	if x != nil {
+		return x.MaxLen
+	}
+	return 0
+}
+
+// How long deliveries in each terminal state are kept, in seconds, and whether they
+// are then deleted or stripped of their payload. Unset fields take the global value.
+type RetentionPolicy struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	DeliveredSeconds int32                  `protobuf:"varint,1,opt,name=delivered_seconds,json=deliveredSeconds,proto3" json:"delivered_seconds,omitempty"`
+	FailedSeconds    int32                  `protobuf:"varint,2,opt,name=failed_seconds,json=failedSeconds,proto3" json:"failed_seconds,omitempty"`
+	CancelledSeconds int32                  `protobuf:"varint,3,opt,name=cancelled_seconds,json=cancelledSeconds,proto3" json:"cancelled_seconds,omitempty"`
+	ExpiredSeconds   int32                  `protobuf:"varint,4,opt,name=expired_seconds,json=expiredSeconds,proto3" json:"expired_seconds,omitempty"`
+	// delete or strip_payload
+	Mode          string `protobuf:"bytes,5,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetentionPolicy) Reset() {
+	*x = RetentionPolicy{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetentionPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionPolicy) ProtoMessage() {}
+
+func (x *RetentionPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetentionPolicy.ProtoReflect.Descriptor instead.
+func (*RetentionPolicy) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RetentionPolicy) GetDeliveredSeconds() int32 {
+	if x != nil {
+		return x.DeliveredSeconds
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetFailedSeconds() int32 {
+	if x != nil {
+		return x.FailedSeconds
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetCancelledSeconds() int32 {
+	if x != nil {
+		return x.CancelledSeconds
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetExpiredSeconds() int32 {
+	if x != nil {
+		return x.ExpiredSeconds
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+type Subscription struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TargetUrl string                 `protobuf:"bytes,2,opt,name=target_url,json=targetUrl,proto3" json:"target_url,omitempty"`
+	// Signing secret, only set in the CreateSubscription and RotateSubscriptionSecret
+	// responses
+	SecretKey         *string                `protobuf:"bytes,3,opt,name=secret_key,json=secretKey,proto3,oneof" json:"secret_key,omitempty"`
+	EventTypes        []string               `protobuf:"bytes,4,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	DestinationType   string                 `protobuf:"bytes,5,opt,name=destination_type,json=destinationType,proto3" json:"destination_type,omitempty"`
+	DestinationConfig *DestinationConfig     `protobuf:"bytes,6,opt,name=destination_config,json=destinationConfig,proto3" json:"destination_config,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Prefix and last characters of the signing secret, e.g. whsec_...abcd
+	SecretKeyHint string `protobuf:"bytes,9,opt,name=secret_key_hint,json=secretKeyHint,proto3" json:"secret_key_hint,omitempty"`
+	// When the secret replaced by the last rotation stops being valid
+	PreviousSecretExpiresAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=previous_secret_expires_at,json=previousSecretExpiresAt,proto3" json:"previous_secret_expires_at,omitempty"`
+	// github, standard_webhooks or ed25519
+	SignatureScheme string `protobuf:"bytes,11,opt,name=signature_scheme,json=signatureScheme,proto3" json:"signature_scheme,omitempty"`
+	// Deliveries not sent within this many seconds of being created expire
+	MaxEventAgeSeconds *int32 `protobuf:"varint,12,opt,name=max_event_age_seconds,json=maxEventAgeSeconds,proto3,oneof" json:"max_event_age_seconds,omitempty"`
+	// Overrides of the global delivery retention policy
+	RetentionPolicy *RetentionPolicy `protobuf:"bytes,13,opt,name=retention_policy,json=retentionPolicy,proto3" json:"retention_policy,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Subscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Subscription) GetTargetUrl() string {
+	if x != nil {
+		return x.TargetUrl
+	}
+	return ""
+}
+
+func (x *Subscription) GetSecretKey() string {
+	if x != nil && x.SecretKey != nil {
+		return *x.SecretKey
+	}
+	return ""
+}
+
+func (x *Subscription) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *Subscription) GetDestinationType() string {
+	if x != nil {
+		return x.DestinationType
+	}
+	return ""
+}
+
+func (x *Subscription) GetDestinationConfig() *DestinationConfig {
+	if x != nil {
+		return x.DestinationConfig
+	}
+	return nil
+}
+
+func (x *Subscription) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Subscription) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Subscription) GetSecretKeyHint() string {
+	if x != nil {
+		return x.SecretKeyHint
+	}
+	return ""
+}
+
+func (x *Subscription) GetPreviousSecretExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PreviousSecretExpiresAt
+	}
+	return nil
+}
+
+func (x *Subscription) GetSignatureScheme() string {
+	if x != nil {
+		return x.SignatureScheme
+	}
+	return ""
+}
+
+func (x *Subscription) GetMaxEventAgeSeconds() int32 {
+	if x != nil && x.MaxEventAgeSeconds != nil {
+		return *x.MaxEventAgeSeconds
+	}
+	return 0
+}
+
+func (x *Subscription) GetRetentionPolicy() *RetentionPolicy {
+	if x != nil {
+		return x.RetentionPolicy
+	}
+	return nil
+}
+
+type SubscriptionRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	TargetUrl string                 `protobuf:"bytes,1,opt,name=target_url,json=targetUrl,proto3" json:"target_url,omitempty"`
+	// Generated on create when empty, left unchanged on update when empty
+	SecretKey         *string            `protobuf:"bytes,2,opt,name=secret_key,json=secretKey,proto3,oneof" json:"secret_key,omitempty"`
+	EventTypes        []string           `protobuf:"bytes,3,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	DestinationType   string             `protobuf:"bytes,4,opt,name=destination_type,json=destinationType,proto3" json:"destination_type,omitempty"`
+	DestinationConfig *DestinationConfig `protobuf:"bytes,5,opt,name=destination_config,json=destinationConfig,proto3" json:"destination_config,omitempty"`
+	// github, standard_webhooks or ed25519, defaults to github on create and is
+	// left unchanged on update when empty
+	SignatureScheme string `protobuf:"bytes,6,opt,name=signature_scheme,json=signatureScheme,proto3" json:"signature_scheme,omitempty"`
+	// Expires deliveries not sent within this many seconds of being created. 0
+	// removes the limit, it is left unchanged on update when unset.
+	MaxEventAgeSeconds *int32 `protobuf:"varint,7,opt,name=max_event_age_seconds,json=maxEventAgeSeconds,proto3,oneof" json:"max_event_age_seconds,omitempty"`
+	// Overrides the global delivery retention policy. An empty policy removes the
+	// override, it is left unchanged on update when unset.
+	RetentionPolicy *RetentionPolicy `protobuf:"bytes,8,opt,name=retention_policy,json=retentionPolicy,proto3" json:"retention_policy,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SubscriptionRequest) Reset() {
+	*x = SubscriptionRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionRequest) ProtoMessage() {}
+
+func (x *SubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*SubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SubscriptionRequest) GetTargetUrl() string {
+	if x != nil {
+		return x.TargetUrl
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetSecretKey() string {
+	if x != nil && x.SecretKey != nil {
+		return *x.SecretKey
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *SubscriptionRequest) GetDestinationType() string {
+	if x != nil {
+		return x.DestinationType
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetDestinationConfig() *DestinationConfig {
+	if x != nil {
+		return x.DestinationConfig
+	}
+	return nil
+}
+
+func (x *SubscriptionRequest) GetSignatureScheme() string {
+	if x != nil {
+		return x.SignatureScheme
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetMaxEventAgeSeconds() int32 {
+	if x != nil && x.MaxEventAgeSeconds != nil {
+		return *x.MaxEventAgeSeconds
+	}
+	return 0
+}
+
+func (x *SubscriptionRequest) GetRetentionPolicy() *RetentionPolicy {
+	if x != nil {
+		return x.RetentionPolicy
+	}
+	return nil
+}
+
+type CreateSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *SubscriptionRequest   `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionRequest) Reset() {
+	*x = CreateSubscriptionRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionRequest) ProtoMessage() {}
+
+func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateSubscriptionRequest) GetSubscription() *SubscriptionRequest {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type CreateSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionResponse) Reset() {
+	*x = CreateSubscriptionResponse{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionResponse) ProtoMessage() {}
+
+func (x *CreateSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateSubscriptionResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type GetSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionRequest) Reset() {
+	*x = GetSubscriptionRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionRequest) ProtoMessage() {}
+
+func (x *GetSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionResponse) Reset() {
+	*x = GetSubscriptionResponse{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionResponse) ProtoMessage() {}
+
+func (x *GetSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetSubscriptionResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type UpdateSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Subscription  *SubscriptionRequest   `protobuf:"bytes,2,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionRequest) Reset() {
+	*x = UpdateSubscriptionRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionRequest) ProtoMessage() {}
+
+func (x *UpdateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetSubscription() *SubscriptionRequest {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type UpdateSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionResponse) Reset() {
+	*x = UpdateSubscriptionResponse{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionResponse) ProtoMessage() {}
+
+func (x *UpdateSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateSubscriptionResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type DeleteSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSubscriptionRequest) Reset() {
+	*x = DeleteSubscriptionRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSubscriptionRequest) ProtoMessage() {}
+
+func (x *DeleteSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSubscriptionResponse) Reset() {
+	*x = DeleteSubscriptionResponse{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSubscriptionResponse) ProtoMessage() {}
+
+func (x *DeleteSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{11}
+}
+
+type ListSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsRequest) Reset() {
+	*x = ListSubscriptionsRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{12}
+}
+
+type ListSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*Subscription        `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsResponse) Reset() {
+	*x = ListSubscriptionsResponse{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListSubscriptionsResponse) GetSubscriptions() []*Subscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+type RotateSubscriptionSecretRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Generated when empty
+	SecretKey *string `protobuf:"bytes,2,opt,name=secret_key,json=secretKey,proto3,oneof" json:"secret_key,omitempty"`
+	// Defaults to SECRET_ROTATION_OVERLAP, 0 drops the old secret immediately
+	OverlapSeconds *int32 `protobuf:"varint,3,opt,name=overlap_seconds,json=overlapSeconds,proto3,oneof" json:"overlap_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RotateSubscriptionSecretRequest) Reset() {
+	*x = RotateSubscriptionSecretRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateSubscriptionSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSubscriptionSecretRequest) ProtoMessage() {}
+
+func (x *RotateSubscriptionSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSubscriptionSecretRequest.ProtoReflect.Descriptor instead.
+func (*RotateSubscriptionSecretRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RotateSubscriptionSecretRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RotateSubscriptionSecretRequest) GetSecretKey() string {
+	if x != nil && x.SecretKey != nil {
+		return *x.SecretKey
+	}
+	return ""
+}
+
+func (x *RotateSubscriptionSecretRequest) GetOverlapSeconds() int32 {
+	if x != nil && x.OverlapSeconds != nil {
+		return *x.OverlapSeconds
+	}
+	return 0
+}
+
+type RotateSubscriptionSecretResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateSubscriptionSecretResponse) Reset() {
+	*x = RotateSubscriptionSecretResponse{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateSubscriptionSecretResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSubscriptionSecretResponse) ProtoMessage() {}
+
+func (x *RotateSubscriptionSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSubscriptionSecretResponse.ProtoReflect.Descriptor instead.
+func (*RotateSubscriptionSecretResponse) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RotateSubscriptionSecretResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type IngestWebhookRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	EventType      string                 `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// JSON encoded payload
+	Payload []byte `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	// Same value as the X-Hub-Signature-256 header of the REST API
+	Signature string `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	// Deliver at this time or after this delay instead of right away, at most one
+	// may be set
+	DeliverAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=deliver_at,json=deliverAt,proto3" json:"deliver_at,omitempty"`
+	Delay     *durationpb.Duration   `protobuf:"bytes,6,opt,name=delay,proto3" json:"delay,omitempty"`
+	// The webhook is not delivered after this time
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestWebhookRequest) Reset() {
+	*x = IngestWebhookRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestWebhookRequest) ProtoMessage() {}
+
+func (x *IngestWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestWebhookRequest.ProtoReflect.Descriptor instead.
+func (*IngestWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *IngestWebhookRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *IngestWebhookRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *IngestWebhookRequest) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *IngestWebhookRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *IngestWebhookRequest) GetDeliverAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliverAt
+	}
+	return nil
+}
+
+func (x *IngestWebhookRequest) GetDelay() *durationpb.Duration {
+	if x != nil {
+		return x.Delay
+	}
+	return nil
+}
+
+func (x *IngestWebhookRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type IngestWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestWebhookResponse) Reset() {
+	*x = IngestWebhookResponse{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestWebhookResponse) ProtoMessage() {}
+
+func (x *IngestWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestWebhookResponse.ProtoReflect.Descriptor instead.
+func (*IngestWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *IngestWebhookResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type PublishEventRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	EventType string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// JSON encoded payload
+	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	// Deliver at this time or after this delay instead of right away, at most one
+	// may be set
+	DeliverAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=deliver_at,json=deliverAt,proto3" json:"deliver_at,omitempty"`
+	Delay     *durationpb.Duration   `protobuf:"bytes,4,opt,name=delay,proto3" json:"delay,omitempty"`
+	// The event is not delivered after this time
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishEventRequest) Reset() {
+	*x = PublishEventRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishEventRequest) ProtoMessage() {}
+
+func (x *PublishEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishEventRequest.ProtoReflect.Descriptor instead.
+func (*PublishEventRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PublishEventRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *PublishEventRequest) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *PublishEventRequest) GetDeliverAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliverAt
+	}
+	return nil
+}
+
+func (x *PublishEventRequest) GetDelay() *durationpb.Duration {
+	if x != nil {
+		return x.Delay
+	}
+	return nil
+}
+
+func (x *PublishEventRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type PublishEventResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	DeliveryIds   []string               `protobuf:"bytes,2,rep,name=delivery_ids,json=deliveryIds,proto3" json:"delivery_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishEventResponse) Reset() {
+	*x = PublishEventResponse{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishEventResponse) ProtoMessage() {}
+
+func (x *PublishEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishEventResponse.ProtoReflect.Descriptor instead.
+func (*PublishEventResponse) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PublishEventResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PublishEventResponse) GetDeliveryIds() []string {
+	if x != nil {
+		return x.DeliveryIds
+	}
+	return nil
+}
+
+type WebhookDelivery struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,2,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	// JSON encoded payload, empty when the payload is offloaded to the blob store
+	Payload     []byte                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	EventType   *string                `protobuf:"bytes,4,opt,name=event_type,json=eventType,proto3,oneof" json:"event_type,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Status      string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	NextRetryAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=next_retry_at,json=nextRetryAt,proto3" json:"next_retry_at,omitempty"`
+	RetryCount  int32                  `protobuf:"varint,8,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
+	MaxRetries  int32                  `protobuf:"varint,9,opt,name=max_retries,json=maxRetries,proto3" json:"max_retries,omitempty"`
+	// Blob store reference, hex encoded SHA-256 and size of an offloaded payload
+	PayloadRef    *string `protobuf:"bytes,10,opt,name=payload_ref,json=payloadRef,proto3,oneof" json:"payload_ref,omitempty"`
+	PayloadSha256 *string `protobuf:"bytes,11,opt,name=payload_sha256,json=payloadSha256,proto3,oneof" json:"payload_sha256,omitempty"`
+	PayloadSize   *int64  `protobuf:"varint,12,opt,name=payload_size,json=payloadSize,proto3,oneof" json:"payload_size,omitempty"`
+	// The delivery is not sent after this time
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// When the retention policy removed the payload
+	PayloadPurgedAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=payload_purged_at,json=payloadPurgedAt,proto3" json:"payload_purged_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WebhookDelivery) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetEventType() string {
+	if x != nil && x.EventType != nil {
+		return *x.EventType
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetNextRetryAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRetryAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetMaxRetries() int32 {
+	if x != nil {
+		return x.MaxRetries
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetPayloadRef() string {
+	if x != nil && x.PayloadRef != nil {
+		return *x.PayloadRef
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetPayloadSha256() string {
+	if x != nil && x.PayloadSha256 != nil {
+		return *x.PayloadSha256
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetPayloadSize() int64 {
+	if x != nil && x.PayloadSize != nil {
+		return *x.PayloadSize
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetPayloadPurgedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PayloadPurgedAt
+	}
+	return nil
+}
+
+type DeliveryAttempt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DeliveryId    string                 `protobuf:"bytes,2,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	AttemptNumber int32                  `protobuf:"varint,3,opt,name=attempt_number,json=attemptNumber,proto3" json:"attempt_number,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	StatusCode    *int32                 `protobuf:"varint,5,opt,name=status_code,json=statusCode,proto3,oneof" json:"status_code,omitempty"`
+	ErrorDetails  *string                `protobuf:"bytes,6,opt,name=error_details,json=errorDetails,proto3,oneof" json:"error_details,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryAttempt) Reset() {
+	*x = DeliveryAttempt{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryAttempt) ProtoMessage() {}
+
+func (x *DeliveryAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryAttempt.ProtoReflect.Descriptor instead.
+func (*DeliveryAttempt) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeliveryAttempt) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeliveryAttempt) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *DeliveryAttempt) GetAttemptNumber() int32 {
+	if x != nil {
+		return x.AttemptNumber
+	}
+	return 0
+}
+
+func (x *DeliveryAttempt) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DeliveryAttempt) GetStatusCode() int32 {
+	if x != nil && x.StatusCode != nil {
+		return *x.StatusCode
+	}
+	return 0
+}
+
+func (x *DeliveryAttempt) GetErrorDetails() string {
+	if x != nil && x.ErrorDetails != nil {
+		return *x.ErrorDetails
+	}
+	return ""
+}
+
+func (x *DeliveryAttempt) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type GetDeliveryStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeliveryStatusRequest) Reset() {
+	*x = GetDeliveryStatusRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryStatusRequest) ProtoMessage() {}
+
+func (x *GetDeliveryStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatusRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetDeliveryStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetDeliveryStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Delivery      *WebhookDelivery       `protobuf:"bytes,1,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	Attempts      []*DeliveryAttempt     `protobuf:"bytes,2,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeliveryStatusResponse) Reset() {
+	*x = GetDeliveryStatusResponse{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryStatusResponse) ProtoMessage() {}
+
+func (x *GetDeliveryStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatusResponse) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetDeliveryStatusResponse) GetDelivery() *WebhookDelivery {
+	if x != nil {
+		return x.Delivery
+	}
+	return nil
+}
+
+func (x *GetDeliveryStatusResponse) GetAttempts() []*DeliveryAttempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+type ListRecentDeliveriesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	// Defaults to 20
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecentDeliveriesRequest) Reset() {
+	*x = ListRecentDeliveriesRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecentDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecentDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListRecentDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecentDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListRecentDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListRecentDeliveriesRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *ListRecentDeliveriesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListRecentDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecentDeliveriesResponse) Reset() {
+	*x = ListRecentDeliveriesResponse{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecentDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecentDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListRecentDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecentDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListRecentDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListRecentDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+type WatchDeliveriesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WatchDeliveriesRequest) Reset() {
+	*x = WatchDeliveriesRequest{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDeliveriesRequest) ProtoMessage() {}
+
+func (x *WatchDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*WatchDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *WatchDeliveriesRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+type WatchDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *DeliveryEvent         `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDeliveriesResponse) Reset() {
+	*x = WatchDeliveriesResponse{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDeliveriesResponse) ProtoMessage() {}
+
+func (x *WatchDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*WatchDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *WatchDeliveriesResponse) GetEvent() *DeliveryEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type DeliveryEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// One of delivery.created, delivery.processing, attempt.succeeded,
+	// attempt.failed, delivery.retry_scheduled, delivery.dead_lettered or
+	// delivery.replayed
+	Type           string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	DeliveryId     string                 `protobuf:"bytes,3,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,4,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	EventType      *string                `protobuf:"bytes,5,opt,name=event_type,json=eventType,proto3,oneof" json:"event_type,omitempty"`
+	Status         string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	RetryCount     int32                  `protobuf:"varint,7,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
+	AttemptNumber  int32                  `protobuf:"varint,8,opt,name=attempt_number,json=attemptNumber,proto3" json:"attempt_number,omitempty"`
+	StatusCode     *int32                 `protobuf:"varint,9,opt,name=status_code,json=statusCode,proto3,oneof" json:"status_code,omitempty"`
+	ErrorDetails   *string                `protobuf:"bytes,10,opt,name=error_details,json=errorDetails,proto3,oneof" json:"error_details,omitempty"`
+	NextRetryAt    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=next_retry_at,json=nextRetryAt,proto3" json:"next_retry_at,omitempty"`
+	Timestamp      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeliveryEvent) Reset() {
+	*x = DeliveryEvent{}
+	mi := &file_webhook_v1_webhook_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryEvent) ProtoMessage() {}
+
+func (x *DeliveryEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_webhook_v1_webhook_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryEvent.ProtoReflect.Descriptor instead.
+func (*DeliveryEvent) Descriptor() ([]byte, []int) {
+	return file_webhook_v1_webhook_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DeliveryEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeliveryEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *DeliveryEvent) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *DeliveryEvent) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *DeliveryEvent) GetEventType() string {
+	if x != nil && x.EventType != nil {
+		return *x.EventType
+	}
+	return ""
+}
+
+func (x *DeliveryEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DeliveryEvent) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+func (x *DeliveryEvent) GetAttemptNumber() int32 {
+	if x != nil {
+		return x.AttemptNumber
+	}
+	return 0
+}
+
+func (x *DeliveryEvent) GetStatusCode() int32 {
+	if x != nil && x.StatusCode != nil {
+		return *x.StatusCode
+	}
+	return 0
+}
+
+func (x *DeliveryEvent) GetErrorDetails() string {
+	if x != nil && x.ErrorDetails != nil {
+		return *x.ErrorDetails
+	}
+	return ""
+}
+
+func (x *DeliveryEvent) GetNextRetryAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRetryAt
+	}
+	return nil
+}
+
+func (x *DeliveryEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+var File_webhook_v1_webhook_proto protoreflect.FileDescriptor
+
+const file_webhook_v1_webhook_proto_rawDesc = "" +
+	"\n" +
+	"\x18webhook/v1/webhook.proto\x12\n" +
+	"webhook.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"l\n" +
+	"\x11DestinationConfig\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x16\n" +
+	"\x06stream\x18\x03 \x01(\tR\x06stream\x12\x17\n" +
+	"\amax_len\x18\x04 \x01(\x03R\x06maxLen\"\xcf\x01\n" +
+	"\x0fRetentionPolicy\x12+\n" +
+	"\x11delivered_seconds\x18\x01 \x01(\x05R\x10deliveredSeconds\x12%\n" +
+	"\x0efailed_seconds\x18\x02 \x01(\x05R\rfailedSeconds\x12+\n" +
+	"\x11cancelled_seconds\x18\x03 \x01(\x05R\x10cancelledSeconds\x12'\n" +
+	"\x0fexpired_seconds\x18\x04 \x01(\x05R\x0eexpiredSeconds\x12\x12\n" +
+	"\x04mode\x18\x05 \x01(\tR\x04mode\"\xc6\x05\n" +
+	"\fSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"target_url\x18\x02 \x01(\tR\ttargetUrl\x12\"\n" +
+	"\n" +
+	"secret_key\x18\x03 \x01(\tH\x00R\tsecretKey\x88\x01\x01\x12\x1f\n" +
+	"\vevent_types\x18\x04 \x03(\tR\n" +
+	"eventTypes\x12)\n" +
+	"\x10destination_type\x18\x05 \x01(\tR\x0fdestinationType\x12L\n" +
+	"\x12destination_config\x18\x06 \x01(\v2\x1d.webhook.v1.DestinationConfigR\x11destinationConfig\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12&\n" +
+	"\x0fsecret_key_hint\x18\t \x01(\tR\rsecretKeyHint\x12W\n" +
+	"\x1aprevious_secret_expires_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x17previousSecretExpiresAt\x12)\n" +
+	"\x10signature_scheme\x18\v \x01(\tR\x0fsignatureScheme\x126\n" +
+	"\x15max_event_age_seconds\x18\f \x01(\x05H\x01R\x12maxEventAgeSeconds\x88\x01\x01\x12F\n" +
+	"\x10retention_policy\x18\r \x01(\v2\x1b.webhook.v1.RetentionPolicyR\x0fretentionPolicyB\r\n" +
+	"\v_secret_keyB\x18\n" +
+	"\x16_max_event_age_seconds\"\xc6\x03\n" +
+	"\x13SubscriptionRequest\x12\x1d\n" +
+	"\n" +
+	"target_url\x18\x01 \x01(\tR\ttargetUrl\x12\"\n" +
+	"\n" +
+	"secret_key\x18\x02 \x01(\tH\x00R\tsecretKey\x88\x01\x01\x12\x1f\n" +
+	"\vevent_types\x18\x03 \x03(\tR\n" +
+	"eventTypes\x12)\n" +
+	"\x10destination_type\x18\x04 \x01(\tR\x0fdestinationType\x12L\n" +
+	"\x12destination_config\x18\x05 \x01(\v2\x1d.webhook.v1.DestinationConfigR\x11destinationConfig\x12)\n" +
+	"\x10signature_scheme\x18\x06 \x01(\tR\x0fsignatureScheme\x126\n" +
+	"\x15max_event_age_seconds\x18\a \x01(\x05H\x01R\x12maxEventAgeSeconds\x88\x01\x01\x12F\n" +
+	"\x10retention_policy\x18\b \x01(\v2\x1b.webhook.v1.RetentionPolicyR\x0fretentionPolicyB\r\n" +
+	"\v_secret_keyB\x18\n" +
+	"\x16_max_event_age_seconds\"`\n" +
+	"\x19CreateSubscriptionRequest\x12C\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x1f.webhook.v1.SubscriptionRequestR\fsubscription\"Z\n" +
+	"\x1aCreateSubscriptionResponse\x12<\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x18.webhook.v1.SubscriptionR\fsubscription\"(\n" +
+	"\x16GetSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"W\n" +
+	"\x17GetSubscriptionResponse\x12<\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x18.webhook.v1.SubscriptionR\fsubscription\"p\n" +
+	"\x19UpdateSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12C\n" +
+	"\fsubscription\x18\x02 \x01(\v2\x1f.webhook.v1.SubscriptionRequestR\fsubscription\"Z\n" +
+	"\x1aUpdateSubscriptionResponse\x12<\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x18.webhook.v1.SubscriptionR\fsubscription\"+\n" +
+	"\x19DeleteSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1c\n" +
+	"\x1aDeleteSubscriptionResponse\"\x1a\n" +
+	"\x18ListSubscriptionsRequest\"[\n" +
+	"\x19ListSubscriptionsResponse\x12>\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x18.webhook.v1.SubscriptionR\rsubscriptions\"\xa6\x01\n" +
+	"\x1fRotateSubscriptionSecretRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
+	"\n" +
+	"secret_key\x18\x02 \x01(\tH\x00R\tsecretKey\x88\x01\x01\x12,\n" +
+	"\x0foverlap_seconds\x18\x03 \x01(\x05H\x01R\x0eoverlapSeconds\x88\x01\x01B\r\n" +
+	"\v_secret_keyB\x12\n" +
+	"\x10_overlap_seconds\"`\n" +
+	" RotateSubscriptionSecretResponse\x12<\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x18.webhook.v1.SubscriptionR\fsubscription\"\xbd\x02\n" +
+	"\x14IngestWebhookRequest\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\fR\apayload\x12\x1c\n" +
+	"\tsignature\x18\x04 \x01(\tR\tsignature\x129\n" +
+	"\n" +
+	"deliver_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tdeliverAt\x12/\n" +
+	"\x05delay\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x05delay\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"1\n" +
+	"\x15IngestWebhookResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xf5\x01\n" +
+	"\x13PublishEventRequest\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12\x18\n" +
+	"\apayload\x18\x02 \x01(\fR\apayload\x129\n" +
+	"\n" +
+	"deliver_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tdeliverAt\x12/\n" +
+	"\x05delay\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x05delay\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"S\n" +
+	"\x14PublishEventResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12!\n" +
+	"\fdelivery_ids\x18\x02 \x03(\tR\vdeliveryIds\"\x9d\x05\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0fsubscription_id\x18\x02 \x01(\tR\x0esubscriptionId\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\fR\apayload\x12\"\n" +
+	"\n" +
+	"event_type\x18\x04 \x01(\tH\x00R\teventType\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12>\n" +
+	"\rnext_retry_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vnextRetryAt\x12\x1f\n" +
+	"\vretry_count\x18\b \x01(\x05R\n" +
+	"retryCount\x12\x1f\n" +
+	"\vmax_retries\x18\t \x01(\x05R\n" +
+	"maxRetries\x12$\n" +
+	"\vpayload_ref\x18\n" +
+	" \x01(\tH\x01R\n" +
+	"payloadRef\x88\x01\x01\x12*\n" +
+	"\x0epayload_sha256\x18\v \x01(\tH\x02R\rpayloadSha256\x88\x01\x01\x12&\n" +
+	"\fpayload_size\x18\f \x01(\x03H\x03R\vpayloadSize\x88\x01\x01\x129\n" +
+	"\n" +
+	"expires_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12F\n" +
+	"\x11payload_purged_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\x0fpayloadPurgedAtB\r\n" +
+	"\v_event_typeB\x0e\n" +
+	"\f_payload_refB\x11\n" +
+	"\x0f_payload_sha256B\x0f\n" +
+	"\r_payload_size\"\xae\x02\n" +
+	"\x0fDeliveryAttempt\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vdelivery_id\x18\x02 \x01(\tR\n" +
+	"deliveryId\x12%\n" +
+	"\x0eattempt_number\x18\x03 \x01(\x05R\rattemptNumber\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12$\n" +
+	"\vstatus_code\x18\x05 \x01(\x05H\x00R\n" +
+	"statusCode\x88\x01\x01\x12(\n" +
+	"\rerror_details\x18\x06 \x01(\tH\x01R\ferrorDetails\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB\x0e\n" +
+	"\f_status_codeB\x10\n" +
+	"\x0e_error_details\"*\n" +
+	"\x18GetDeliveryStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8d\x01\n" +
+	"\x19GetDeliveryStatusResponse\x127\n" +
+	"\bdelivery\x18\x01 \x01(\v2\x1b.webhook.v1.WebhookDeliveryR\bdelivery\x127\n" +
+	"\battempts\x18\x02 \x03(\v2\x1b.webhook.v1.DeliveryAttemptR\battempts\"\\\n" +
+	"\x1bListRecentDeliveriesRequest\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"[\n" +
+	"\x1cListRecentDeliveriesResponse\x12;\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x1b.webhook.v1.WebhookDeliveryR\n" +
+	"deliveries\"A\n" +
+	"\x16WatchDeliveriesRequest\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\"J\n" +
+	"\x17WatchDeliveriesResponse\x12/\n" +
+	"\x05event\x18\x01 \x01(\v2\x19.webhook.v1.DeliveryEventR\x05event\"\xfc\x03\n" +
+	"\rDeliveryEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1f\n" +
+	"\vdelivery_id\x18\x03 \x01(\tR\n" +
+	"deliveryId\x12'\n" +
+	"\x0fsubscription_id\x18\x04 \x01(\tR\x0esubscriptionId\x12\"\n" +
+	"\n" +
+	"event_type\x18\x05 \x01(\tH\x00R\teventType\x88\x01\x01\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1f\n" +
+	"\vretry_count\x18\a \x01(\x05R\n" +
+	"retryCount\x12%\n" +
+	"\x0eattempt_number\x18\b \x01(\x05R\rattemptNumber\x12$\n" +
+	"\vstatus_code\x18\t \x01(\x05H\x01R\n" +
+	"statusCode\x88\x01\x01\x12(\n" +
+	"\rerror_details\x18\n" +
+	" \x01(\tH\x02R\ferrorDetails\x88\x01\x01\x12>\n" +
+	"\rnext_retry_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vnextRetryAt\x128\n" +
+	"\ttimestamp\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\ttimestampB\r\n" +
+	"\v_event_typeB\x0e\n" +
+	"\f_status_codeB\x10\n" +
+	"\x0e_error_details2\xc8\b\n" +
+	"\x0eWebhookService\x12c\n" +
+	"\x12CreateSubscription\x12%.webhook.v1.CreateSubscriptionRequest\x1a&.webhook.v1.CreateSubscriptionResponse\x12Z\n" +
+	"\x0fGetSubscription\x12\".webhook.v1.GetSubscriptionRequest\x1a#.webhook.v1.GetSubscriptionResponse\x12c\n" +
+	"\x12UpdateSubscription\x12%.webhook.v1.UpdateSubscriptionRequest\x1a&.webhook.v1.UpdateSubscriptionResponse\x12c\n" +
+	"\x12DeleteSubscription\x12%.webhook.v1.DeleteSubscriptionRequest\x1a&.webhook.v1.DeleteSubscriptionResponse\x12`\n" +
+	"\x11ListSubscriptions\x12$.webhook.v1.ListSubscriptionsRequest\x1a%.webhook.v1.ListSubscriptionsResponse\x12u\n" +
+	"\x18RotateSubscriptionSecret\x12+.webhook.v1.RotateSubscriptionSecretRequest\x1a,.webhook.v1.RotateSubscriptionSecretResponse\x12T\n" +
+	"\rIngestWebhook\x12 .webhook.v1.IngestWebhookRequest\x1a!.webhook.v1.IngestWebhookResponse\x12Q\n" +
+	"\fPublishEvent\x12\x1f.webhook.v1.PublishEventRequest\x1a .webhook.v1.PublishEventResponse\x12`\n" +
+	"\x11GetDeliveryStatus\x12$.webhook.v1.GetDeliveryStatusRequest\x1a%.webhook.v1.GetDeliveryStatusResponse\x12i\n" +
+	"\x14ListRecentDeliveries\x12'.webhook.v1.ListRecentDeliveriesRequest\x1a(.webhook.v1.ListRecentDeliveriesResponse\x12\\\n" +
+	"\x0fWatchDeliveries\x12\".webhook.v1.WatchDeliveriesRequest\x1a#.webhook.v1.WatchDeliveriesResponse0\x01BEZCgithub.com/Unic-X/webhook-delivery/internal/pb/webhook/v1;webhookv1b\x06proto3"
+
+var (
+	file_webhook_v1_webhook_proto_rawDescOnce sync.Once
+	file_webhook_v1_webhook_proto_rawDescData []byte
+)
+
+func file_webhook_v1_webhook_proto_rawDescGZIP() []byte {
+	file_webhook_v1_webhook_proto_rawDescOnce.Do(func() {
+		file_webhook_v1_webhook_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_webhook_v1_webhook_proto_rawDesc), len(file_webhook_v1_webhook_proto_rawDesc)))
+	})
+	return file_webhook_v1_webhook_proto_rawDescData
+}
+
+var file_webhook_v1_webhook_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_webhook_v1_webhook_proto_goTypes = []any{
+	(*DestinationConfig)(nil),                // 0: webhook.v1.DestinationConfig
+	(*RetentionPolicy)(nil),                  // 1: webhook.v1.RetentionPolicy
+	(*Subscription)(nil),                     // 2: webhook.v1.Subscription
+	(*SubscriptionRequest)(nil),              // 3: webhook.v1.SubscriptionRequest
+	(*CreateSubscriptionRequest)(nil),        // 4: webhook.v1.CreateSubscriptionRequest
+	(*CreateSubscriptionResponse)(nil),       // 5: webhook.v1.CreateSubscriptionResponse
+	(*GetSubscriptionRequest)(nil),           // 6: webhook.v1.GetSubscriptionRequest
+	(*GetSubscriptionResponse)(nil),          // 7: webhook.v1.GetSubscriptionResponse
+	(*UpdateSubscriptionRequest)(nil),        // 8: webhook.v1.UpdateSubscriptionRequest
+	(*UpdateSubscriptionResponse)(nil),       // 9: webhook.v1.UpdateSubscriptionResponse
+	(*DeleteSubscriptionRequest)(nil),        // 10: webhook.v1.DeleteSubscriptionRequest
+	(*DeleteSubscriptionResponse)(nil),       // 11: webhook.v1.DeleteSubscriptionResponse
+	(*ListSubscriptionsRequest)(nil),         // 12: webhook.v1.ListSubscriptionsRequest
+	(*ListSubscriptionsResponse)(nil),        // 13: webhook.v1.ListSubscriptionsResponse
+	(*RotateSubscriptionSecretRequest)(nil),  // 14: webhook.v1.RotateSubscriptionSecretRequest
+	(*RotateSubscriptionSecretResponse)(nil), // 15: webhook.v1.RotateSubscriptionSecretResponse
+	(*IngestWebhookRequest)(nil),             // 16: webhook.v1.IngestWebhookRequest
+	(*IngestWebhookResponse)(nil),            // 17: webhook.v1.IngestWebhookResponse
+	(*PublishEventRequest)(nil),              // 18: webhook.v1.PublishEventRequest
+	(*PublishEventResponse)(nil),             // 19: webhook.v1.PublishEventResponse
+	(*WebhookDelivery)(nil),                  // 20: webhook.v1.WebhookDelivery
+	(*DeliveryAttempt)(nil),                  // 21: webhook.v1.DeliveryAttempt
+	(*GetDeliveryStatusRequest)(nil),         // 22: webhook.v1.GetDeliveryStatusRequest
+	(*GetDeliveryStatusResponse)(nil),        // 23: webhook.v1.GetDeliveryStatusResponse
+	(*ListRecentDeliveriesRequest)(nil),      // 24: webhook.v1.ListRecentDeliveriesRequest
+	(*ListRecentDeliveriesResponse)(nil),     // 25: webhook.v1.ListRecentDeliveriesResponse
+	(*WatchDeliveriesRequest)(nil),           // 26: webhook.v1.WatchDeliveriesRequest
+	(*WatchDeliveriesResponse)(nil),          // 27: webhook.v1.WatchDeliveriesResponse
+	(*DeliveryEvent)(nil),                    // 28: webhook.v1.DeliveryEvent
+	(*timestamppb.Timestamp)(nil),            // 29: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),              // 30: google.protobuf.Duration
+}
+var file_webhook_v1_webhook_proto_depIdxs = []int32{
+	0,  // 0: webhook.v1.Subscription.destination_config:type_name -> webhook.v1.DestinationConfig
+	29, // 1: webhook.v1.Subscription.created_at:type_name -> google.protobuf.Timestamp
+	29, // 2: webhook.v1.Subscription.updated_at:type_name -> google.protobuf.Timestamp
+	29, // 3: webhook.v1.Subscription.previous_secret_expires_at:type_name -> google.protobuf.Timestamp
+	1,  // 4: webhook.v1.Subscription.retention_policy:type_name -> webhook.v1.RetentionPolicy
+	0,  // 5: webhook.v1.SubscriptionRequest.destination_config:type_name -> webhook.v1.DestinationConfig
+	1,  // 6: webhook.v1.SubscriptionRequest.retention_policy:type_name -> webhook.v1.RetentionPolicy
+	3,  // 7: webhook.v1.CreateSubscriptionRequest.subscription:type_name -> webhook.v1.SubscriptionRequest
+	2,  // 8: webhook.v1.CreateSubscriptionResponse.subscription:type_name -> webhook.v1.Subscription
+	2,  // 9: webhook.v1.GetSubscriptionResponse.subscription:type_name -> webhook.v1.Subscription
+	3,  // 10: webhook.v1.UpdateSubscriptionRequest.subscription:type_name -> webhook.v1.SubscriptionRequest
+	2,  // 11: webhook.v1.UpdateSubscriptionResponse.subscription:type_name -> webhook.v1.Subscription
+	2,  // 12: webhook.v1.ListSubscriptionsResponse.subscriptions:type_name -> webhook.v1.Subscription
+	2,  // 13: webhook.v1.RotateSubscriptionSecretResponse.subscription:type_name -> webhook.v1.Subscription
+	29, // 14: webhook.v1.IngestWebhookRequest.deliver_at:type_name -> google.protobuf.Timestamp
+	30, // 15: webhook.v1.IngestWebhookRequest.delay:type_name -> google.protobuf.Duration
+	29, // 16: webhook.v1.IngestWebhookRequest.expires_at:type_name -> google.protobuf.Timestamp
+	29, // 17: webhook.v1.PublishEventRequest.deliver_at:type_name -> google.protobuf.Timestamp
+	30, // 18: webhook.v1.PublishEventRequest.delay:type_name -> google.protobuf.Duration
+	29, // 19: webhook.v1.PublishEventRequest.expires_at:type_name -> google.protobuf.Timestamp
+	29, // 20: webhook.v1.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	29, // 21: webhook.v1.WebhookDelivery.next_retry_at:type_name -> google.protobuf.Timestamp
+	29, // 22: webhook.v1.WebhookDelivery.expires_at:type_name -> google.protobuf.Timestamp
+	29, // 23: webhook.v1.WebhookDelivery.payload_purged_at:type_name -> google.protobuf.Timestamp
+	29, // 24: webhook.v1.DeliveryAttempt.created_at:type_name -> google.protobuf.Timestamp
+	20, // 25: webhook.v1.GetDeliveryStatusResponse.delivery:type_name -> webhook.v1.WebhookDelivery
+	21, // 26: webhook.v1.GetDeliveryStatusResponse.attempts:type_name -> webhook.v1.DeliveryAttempt
+	20, // 27: webhook.v1.ListRecentDeliveriesResponse.deliveries:type_name -> webhook.v1.WebhookDelivery
+	28, // 28: webhook.v1.WatchDeliveriesResponse.event:type_name -> webhook.v1.DeliveryEvent
+	29, // 29: webhook.v1.DeliveryEvent.next_retry_at:type_name -> google.protobuf.Timestamp
+	29, // 30: webhook.v1.DeliveryEvent.timestamp:type_name -> google.protobuf.Timestamp
+	4,  // 31: webhook.v1.WebhookService.CreateSubscription:input_type -> webhook.v1.CreateSubscriptionRequest
+	6,  // 32: webhook.v1.WebhookService.GetSubscription:input_type -> webhook.v1.GetSubscriptionRequest
+	8,  // 33: webhook.v1.WebhookService.UpdateSubscription:input_type -> webhook.v1.UpdateSubscriptionRequest
+	10, // 34: webhook.v1.WebhookService.DeleteSubscription:input_type -> webhook.v1.DeleteSubscriptionRequest
+	12, // 35: webhook.v1.WebhookService.ListSubscriptions:input_type -> webhook.v1.ListSubscriptionsRequest
+	14, // 36: webhook.v1.WebhookService.RotateSubscriptionSecret:input_type -> webhook.v1.RotateSubscriptionSecretRequest
+	16, // 37: webhook.v1.WebhookService.IngestWebhook:input_type -> webhook.v1.IngestWebhookRequest
+	18, // 38: webhook.v1.WebhookService.PublishEvent:input_type -> webhook.v1.PublishEventRequest
+	22, // 39: webhook.v1.WebhookService.GetDeliveryStatus:input_type -> webhook.v1.GetDeliveryStatusRequest
+	24, // 40: webhook.v1.WebhookService.ListRecentDeliveries:input_type -> webhook.v1.ListRecentDeliveriesRequest
+	26, // 41: webhook.v1.WebhookService.WatchDeliveries:input_type -> webhook.v1.WatchDeliveriesRequest
+	5,  // 42: webhook.v1.WebhookService.CreateSubscription:output_type -> webhook.v1.CreateSubscriptionResponse
+	7,  // 43: webhook.v1.WebhookService.GetSubscription:output_type -> webhook.v1.GetSubscriptionResponse
+	9,  // 44: webhook.v1.WebhookService.UpdateSubscription:output_type -> webhook.v1.UpdateSubscriptionResponse
+	11, // 45: webhook.v1.WebhookService.DeleteSubscription:output_type -> webhook.v1.DeleteSubscriptionResponse
+	13, // 46: webhook.v1.WebhookService.ListSubscriptions:output_type -> webhook.v1.ListSubscriptionsResponse
+	15, // 47: webhook.v1.WebhookService.RotateSubscriptionSecret:output_type -> webhook.v1.RotateSubscriptionSecretResponse
+	17, // 48: webhook.v1.WebhookService.IngestWebhook:output_type -> webhook.v1.IngestWebhookResponse
+	19, // 49: webhook.v1.WebhookService.PublishEvent:output_type -> webhook.v1.PublishEventResponse
+	23, // 50: webhook.v1.WebhookService.GetDeliveryStatus:output_type -> webhook.v1.GetDeliveryStatusResponse
+	25, // 51: webhook.v1.WebhookService.ListRecentDeliveries:output_type -> webhook.v1.ListRecentDeliveriesResponse
+	27, // 52: webhook.v1.WebhookService.WatchDeliveries:output_type -> webhook.v1.WatchDeliveriesResponse
+	42, // [42:53] is the sub-list for method output_type
+	31, // [31:42] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
+}
+
+func init() { file_webhook_v1_webhook_proto_init() }
+func file_webhook_v1_webhook_proto_init() {
+	if File_webhook_v1_webhook_proto != nil {
+		return
+	}
+	file_webhook_v1_webhook_proto_msgTypes[2].OneofWrappers = []any{}
+	file_webhook_v1_webhook_proto_msgTypes[3].OneofWrappers = []any{}
+	file_webhook_v1_webhook_proto_msgTypes[14].OneofWrappers = []any{}
+	file_webhook_v1_webhook_proto_msgTypes[20].OneofWrappers = []any{}
+	file_webhook_v1_webhook_proto_msgTypes[21].OneofWrappers = []any{}
+	file_webhook_v1_webhook_proto_msgTypes[28].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_webhook_v1_webhook_proto_rawDesc), len(file_webhook_v1_webhook_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   29,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_webhook_v1_webhook_proto_goTypes,
+		DependencyIndexes: file_webhook_v1_webhook_proto_depIdxs,
+		MessageInfos:      file_webhook_v1_webhook_proto_msgTypes,
+	}.Build()
+	File_webhook_v1_webhook_proto = out.File
+	file_webhook_v1_webhook_proto_goTypes = nil
+	file_webhook_v1_webhook_proto_depIdxs = nil
+}
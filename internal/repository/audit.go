@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// CreateAuditEvent records an audit event
+func (r *PostgresRepository) CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (id, action, resource_type, resource_id, actor, source_ip, user_agent, request_id,
+			before, after, metadata, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		event.ID, event.Action, event.ResourceType, event.ResourceID, event.Actor, event.SourceIP, event.UserAgent, event.RequestID,
+		nullBytes(event.Before), nullBytes(event.After), nullBytes(event.Metadata), event.CreatedAt)
+	return err
+}
+
+// ListAuditEvents returns the audit events matching a filter, newest first
+func (r *PostgresRepository) ListAuditEvents(ctx context.Context, filter models.AuditEventsRequest) ([]models.AuditEvent, error) {
+	query := `SELECT * FROM audit_events WHERE TRUE`
+	var args []interface{}
+
+	for _, cond := range []struct {
+		column string
+		value  string
+	}{
+		{"action", filter.Action},
+		{"resource_type", filter.ResourceType},
+		{"resource_id", filter.ResourceID},
+		{"actor", filter.Actor},
+		{"request_id", filter.RequestID},
+	} {
+		if cond.value != "" {
+			args = append(args, cond.value)
+			query += fmt.Sprintf(" AND %s = $%d", cond.column, len(args))
+		}
+	}
+	if filter.Since != nil {
+		args = append(args, *filter.Since)
+		query += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+	if filter.Until != nil {
+		args = append(args, *filter.Until)
+		query += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	events := []models.AuditEvent{}
+	err := r.db.SelectContext(ctx, &events, query, args...)
+	return events, err
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// CreateDataKey stores a wrapped data key
+func (r *PostgresRepository) CreateDataKey(ctx context.Context, key *models.DataKey) error {
+	query := `
+		INSERT INTO data_keys (id, master_key_id, wrapped_key, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := r.db.ExecContext(ctx, query, key.ID, key.MasterKeyID, key.WrappedKey, key.CreatedAt)
+	return err
+}
+
+// GetDataKey retrieves a wrapped data key by ID
+func (r *PostgresRepository) GetDataKey(ctx context.Context, id uuid.UUID) (*models.DataKey, error) {
+	query := `SELECT * FROM data_keys WHERE id = $1`
+	var key models.DataKey
+	if err := r.db.GetContext(ctx, &key, query, id); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// DeleteDataKey deletes a data key. Anything still encrypted with it becomes unreadable.
+func (r *PostgresRepository) DeleteDataKey(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM data_keys WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// ListDataKeysToRewrap returns data keys wrapped by a master key other than the active one
+func (r *PostgresRepository) ListDataKeysToRewrap(ctx context.Context, activeMasterKeyID string, limit int) ([]models.DataKey, error) {
+	query := `SELECT * FROM data_keys WHERE master_key_id <> $1 ORDER BY created_at ASC LIMIT $2`
+	var keys []models.DataKey
+	err := r.db.SelectContext(ctx, &keys, query, activeMasterKeyID, limit)
+	return keys, err
+}
+
+// RewrapDataKey replaces the wrapping of a data key. It reports false if the key was
+// re-wrapped concurrently and is no longer wrapped by previousMasterKeyID.
+func (r *PostgresRepository) RewrapDataKey(ctx context.Context, key *models.DataKey, previousMasterKeyID string) (bool, error) {
+	query := `
+		UPDATE data_keys
+		SET master_key_id = $1, wrapped_key = $2, rotated_at = $3
+		WHERE id = $4 AND master_key_id = $5
+	`
+	result, err := r.db.ExecContext(ctx, query, key.MasterKeyID, key.WrappedKey, time.Now(), key.ID, previousMasterKeyID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// GetSubscriptionDataKeyID returns the ID of a subscription's data key, or nil if it
+// has none yet
+func (r *PostgresRepository) GetSubscriptionDataKeyID(ctx context.Context, subscriptionID uuid.UUID) (*uuid.UUID, error) {
+	query := `SELECT data_key_id FROM subscriptions WHERE id = $1`
+	var id *uuid.UUID
+	err := r.db.GetContext(ctx, &id, query, subscriptionID)
+	return id, err
+}
+
+// AssignSubscriptionDataKey sets a subscription's data key unless it already has one,
+// and returns the data key the subscription ends up with
+func (r *PostgresRepository) AssignSubscriptionDataKey(ctx context.Context, subscriptionID uuid.UUID, dataKeyID uuid.UUID) (uuid.UUID, error) {
+	query := `
+		UPDATE subscriptions
+		SET data_key_id = COALESCE(data_key_id, $2)
+		WHERE id = $1
+		RETURNING data_key_id
+	`
+	var id uuid.UUID
+	err := r.db.GetContext(ctx, &id, query, subscriptionID, dataKeyID)
+	return id, err
+}
+
+// ListSubscriptionsWithPlaintextSecret returns subscriptions whose secret key does not
+// start with sealedPrefix
+func (r *PostgresRepository) ListSubscriptionsWithPlaintextSecret(ctx context.Context, sealedPrefix string, limit int) ([]models.Subscription, error) {
+	query := `
+		SELECT * FROM subscriptions
+		WHERE secret_key IS NOT NULL AND secret_key <> '' AND NOT starts_with(secret_key, $1)
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+	var subs []models.Subscription
+	err := r.db.SelectContext(ctx, &subs, query, sealedPrefix, limit)
+	return subs, err
+}
+
+// ReplaceSubscriptionSecret replaces a subscription's stored secret key. It reports
+// false if the secret was changed concurrently and no longer equals previous.
+func (r *PostgresRepository) ReplaceSubscriptionSecret(ctx context.Context, id uuid.UUID, previous string, secret string) (bool, error) {
+	query := `UPDATE subscriptions SET secret_key = $1 WHERE id = $2 AND secret_key = $3`
+	result, err := r.db.ExecContext(ctx, query, secret, id, previous)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// ListPlaintextDeliveries returns deliveries whose payload is stored unencrypted inline
+func (r *PostgresRepository) ListPlaintextDeliveries(ctx context.Context, limit int) ([]models.WebhookDelivery, error) {
+	query := `SELECT * FROM webhook_deliveries WHERE payload IS NOT NULL LIMIT $1`
+	var deliveries []models.WebhookDelivery
+	err := r.db.SelectContext(ctx, &deliveries, query, limit)
+	return deliveries, err
+}
+
+// EncryptDeliveryPayload replaces a delivery's inline payload with its ciphertext. It
+// reports false if the payload was already replaced.
+func (r *PostgresRepository) EncryptDeliveryPayload(ctx context.Context, id uuid.UUID, ciphertext []byte) (bool, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET payload = NULL, payload_ciphertext = $1
+		WHERE id = $2 AND payload IS NOT NULL
+	`
+	result, err := r.db.ExecContext(ctx, query, ciphertext, id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// DeleteSubscriptionWithDataKey deletes a subscription together with its data key, so
+// anything encrypted for the subscription becomes unreadable
+func (r *PostgresRepository) DeleteSubscriptionWithDataKey(ctx context.Context, id uuid.UUID) error {
+	query := `
+		WITH attempts AS (
+			DELETE FROM delivery_attempts
+			WHERE delivery_id IN (SELECT id FROM webhook_deliveries WHERE subscription_id = $1)
+		), deleted AS (
+			DELETE FROM subscriptions WHERE id = $1 RETURNING data_key_id
+		)
+		DELETE FROM data_keys WHERE id IN (SELECT data_key_id FROM deleted)
+	`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -0,0 +1,630 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Unic-X/webhook-delivery/internal/envelope"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// New returns the repository for db. Subscription secrets and delivery payloads are
+// encrypted at rest when a keyring is given.
+func New(db *sqlx.DB, keyring *envelope.Keyring) Repository {
+	repo := NewPostgresRepository(db)
+	if keyring == nil {
+		return repo
+	}
+	return NewEncryptedRepository(repo, keyring)
+}
+
+// EncryptedRepository encrypts subscription secret keys and delivery payloads at rest. Each subscription has its own data key, stored wrapped by a master key
+// from the keyring. Rows written before encryption was enabled are read as they are
+// until Reencrypt encrypts them.
+type EncryptedRepository struct {
+	*PostgresRepository
+	keyring *envelope.Keyring
+
+	// dataKeys caches unwrapped data keys by ID, and subscriptionKeys the data key
+	// ID of each subscription. Neither changes once assigned.
+	dataKeys         sync.Map
+	subscriptionKeys sync.Map
+}
+
+// NewEncryptedRepository creates a new EncryptedRepository
+func NewEncryptedRepository(repo *PostgresRepository, keyring *envelope.Keyring) *EncryptedRepository {
+	return &EncryptedRepository{
+		PostgresRepository: repo,
+		keyring:            keyring,
+	}
+}
+
+// secretAAD binds a sealed secret key to its subscription
+func secretAAD(subscriptionID uuid.UUID) []byte {
+	return []byte("subscriptions.secret_key:" + subscriptionID.String())
+}
+
+// previousSecretAAD binds a sealed previous secret key to its subscription
+func previousSecretAAD(subscriptionID uuid.UUID) []byte {
+	return []byte("subscriptions.previous_secret_key:" + subscriptionID.String())
+}
+
+// payloadAAD binds a sealed payload to its delivery
+func payloadAAD(deliveryID uuid.UUID) []byte {
+	return []byte("webhook_deliveries.payload:" + deliveryID.String())
+}
+
+// archivedPayloadAAD binds a sealed payload in the archive to its delivery
+func archivedPayloadAAD(deliveryID uuid.UUID) []byte {
+	return []byte("archive.payload:" + deliveryID.String())
+}
+
+// blobAAD binds a sealed payload blob to its subscription. Blobs are shared by the
+// deliveries of a subscription, so they are not bound to one of them.
+func blobAAD(subscriptionID uuid.UUID) []byte {
+	return []byte("blobs.payload:" + subscriptionID.String())
+}
+
+// newDataKey generates a data key and stores it wrapped by the active master key
+func (r *EncryptedRepository) newDataKey(ctx context.Context) (uuid.UUID, []byte, error) {
+	key, err := envelope.GenerateKey()
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	id := uuid.New()
+	masterKeyID, wrapped, err := r.keyring.Wrap(id, key)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	dataKey := models.DataKey{
+		ID:          id,
+		MasterKeyID: masterKeyID,
+		WrappedKey:  wrapped,
+		CreatedAt:   time.Now(),
+	}
+	if err := r.CreateDataKey(ctx, &dataKey); err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	r.dataKeys.Store(id, key)
+	return id, key, nil
+}
+
+// dataKey returns an unwrapped data key
+func (r *EncryptedRepository) dataKey(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	if key, ok := r.dataKeys.Load(id); ok {
+		return key.([]byte), nil
+	}
+
+	dataKey, err := r.GetDataKey(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data key %s: %w", id, err)
+	}
+	key, err := r.keyring.Unwrap(id, dataKey.MasterKeyID, dataKey.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key %s: %w", id, err)
+	}
+
+	r.dataKeys.Store(id, key)
+	return key, nil
+}
+
+// subscriptionKey returns the data key of a subscription, creating one for
+// subscriptions written before encryption was enabled
+func (r *EncryptedRepository) subscriptionKey(ctx context.Context, subscriptionID uuid.UUID) (uuid.UUID, []byte, error) {
+	if id, ok := r.subscriptionKeys.Load(subscriptionID); ok {
+		key, err := r.dataKey(ctx, id.(uuid.UUID))
+		return id.(uuid.UUID), key, err
+	}
+
+	id, err := r.GetSubscriptionDataKeyID(ctx, subscriptionID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	if id == nil {
+		newID, _, err := r.newDataKey(ctx)
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+		assigned, err := r.AssignSubscriptionDataKey(ctx, subscriptionID, newID)
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+		// Another writer assigned a key first, drop ours
+		if assigned != newID {
+			r.dataKeys.Delete(newID)
+			_ = r.DeleteDataKey(ctx, newID)
+		}
+		id = &assigned
+	}
+
+	r.subscriptionKeys.Store(subscriptionID, *id)
+	key, err := r.dataKey(ctx, *id)
+	return *id, key, err
+}
+
+// sealSubscription returns a copy of sub with its secret keys sealed
+func sealSubscription(sub *models.Subscription, keyID uuid.UUID, key []byte) (*models.Subscription, error) {
+	stored := *sub
+	var err error
+	if stored.SecretKey, err = sealSecret(sub.SecretKey, keyID, key, secretAAD(sub.ID)); err != nil {
+		return nil, err
+	}
+	if stored.PreviousSecretKey, err = sealSecret(sub.PreviousSecretKey, keyID, key, previousSecretAAD(sub.ID)); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// sealSecret seals a secret key, nil and empty secrets are returned as they are
+func sealSecret(secret *string, keyID uuid.UUID, key []byte, aad []byte) (*string, error) {
+	if secret == nil || *secret == "" {
+		return secret, nil
+	}
+	sealed, err := envelope.SealString(keyID, key, *secret, aad)
+	if err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// openSubscription decrypts a subscription's secret keys in place
+func (r *EncryptedRepository) openSubscription(ctx context.Context, sub *models.Subscription) error {
+	var err error
+	if sub.SecretKey, err = r.openSecret(ctx, sub.SecretKey, secretAAD(sub.ID)); err != nil {
+		return fmt.Errorf("failed to decrypt secret key of subscription %s: %w", sub.ID, err)
+	}
+	if sub.PreviousSecretKey, err = r.openSecret(ctx, sub.PreviousSecretKey, previousSecretAAD(sub.ID)); err != nil {
+		return fmt.Errorf("failed to decrypt previous secret key of subscription %s: %w", sub.ID, err)
+	}
+	return nil
+}
+
+// openSecret decrypts a sealed secret key, other values are returned as they are
+func (r *EncryptedRepository) openSecret(ctx context.Context, secret *string, aad []byte) (*string, error) {
+	if secret == nil || !envelope.IsSealedString(*secret) {
+		return secret, nil
+	}
+
+	ciphertext, err := envelope.DecodeString(*secret)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := r.open(ctx, ciphertext, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	opened := string(plaintext)
+	return &opened, nil
+}
+
+// openDelivery decrypts a delivery's payload in place
+func (r *EncryptedRepository) openDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.PayloadCiphertext == nil {
+		return nil
+	}
+
+	plaintext, err := r.open(ctx, delivery.PayloadCiphertext, payloadAAD(delivery.ID))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt payload of delivery %s: %w", delivery.ID, err)
+	}
+
+	delivery.Payload = plaintext
+	delivery.PayloadCiphertext = nil
+	return nil
+}
+
+// open decrypts a ciphertext with the data key it names
+func (r *EncryptedRepository) open(ctx context.Context, ciphertext []byte, aad []byte) ([]byte, error) {
+	keyID, err := envelope.KeyID(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	key, err := r.dataKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.Open(key, ciphertext, aad)
+}
+
+// openSubscriptions decrypts the secret keys of a list of subscriptions
+func (r *EncryptedRepository) openSubscriptions(ctx context.Context, subs []models.Subscription, err error) ([]models.Subscription, error) {
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		if err := r.openSubscription(ctx, &subs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return subs, nil
+}
+
+// openDeliveries decrypts the payloads of a list of deliveries
+func (r *EncryptedRepository) openDeliveries(ctx context.Context, deliveries []models.WebhookDelivery, err error) ([]models.WebhookDelivery, error) {
+	if err != nil {
+		return nil, err
+	}
+	for i := range deliveries {
+		if err := r.openDelivery(ctx, &deliveries[i]); err != nil {
+			return nil, err
+		}
+	}
+	return deliveries, nil
+}
+
+// CreateSubscription creates a subscription with a new data key and its secret key sealed
+func (r *EncryptedRepository) CreateSubscription(ctx context.Context, sub *models.Subscription) error {
+	keyID, key, err := r.newDataKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	sub.DataKeyID = &keyID
+	stored, err := sealSubscription(sub, keyID, key)
+	if err != nil {
+		return err
+	}
+	if err := r.PostgresRepository.CreateSubscription(ctx, stored); err != nil {
+		r.dataKeys.Delete(keyID)
+		_ = r.DeleteDataKey(ctx, keyID)
+		return err
+	}
+
+	r.subscriptionKeys.Store(sub.ID, keyID)
+	return nil
+}
+
+// GetSubscription retrieves a subscription by ID with its secret key decrypted
+func (r *EncryptedRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*models.Subscription, error) {
+	sub, err := r.PostgresRepository.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.openSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// UpdateSubscription updates a subscription with its secret key sealed
+func (r *EncryptedRepository) UpdateSubscription(ctx context.Context, sub *models.Subscription) error {
+	keyID, key, err := r.subscriptionKey(ctx, sub.ID)
+	if err != nil {
+		return err
+	}
+
+	stored, err := sealSubscription(sub, keyID, key)
+	if err != nil {
+		return err
+	}
+	return r.PostgresRepository.UpdateSubscription(ctx, stored)
+}
+
+// RotateSubscriptionSecret stores a subscription's current and previous signing
+// secrets sealed
+func (r *EncryptedRepository) RotateSubscriptionSecret(ctx context.Context, sub *models.Subscription) error {
+	keyID, key, err := r.subscriptionKey(ctx, sub.ID)
+	if err != nil {
+		return err
+	}
+
+	stored, err := sealSubscription(sub, keyID, key)
+	if err != nil {
+		return err
+	}
+	return r.PostgresRepository.RotateSubscriptionSecret(ctx, stored)
+}
+
+// DeleteSubscription deletes a subscription along with its data key
+func (r *EncryptedRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	if err := r.DeleteSubscriptionWithDataKey(ctx, id); err != nil {
+		return err
+	}
+
+	if keyID, ok := r.subscriptionKeys.LoadAndDelete(id); ok {
+		r.dataKeys.Delete(keyID)
+	}
+	return nil
+}
+
+// ListSubscriptions returns all subscriptions with their secret keys decrypted
+func (r *EncryptedRepository) ListSubscriptions(ctx context.Context) ([]models.Subscription, error) {
+	subs, err := r.PostgresRepository.ListSubscriptions(ctx)
+	return r.openSubscriptions(ctx, subs, err)
+}
+
+// ListSubscriptionsForEventType returns the subscriptions that accept an event type
+// with their secret keys decrypted
+func (r *EncryptedRepository) ListSubscriptionsForEventType(ctx context.Context, eventType string) ([]models.Subscription, error) {
+	subs, err := r.PostgresRepository.ListSubscriptionsForEventType(ctx, eventType)
+	return r.openSubscriptions(ctx, subs, err)
+}
+
+// CreateWebhookDelivery creates a delivery with its inline payload sealed under the
+// subscription's data key. Offloaded payloads are sealed by SealBlob instead.
+func (r *EncryptedRepository) CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	stored, err := r.sealDelivery(ctx, *delivery)
+	if err != nil {
+		return err
+	}
+	return r.PostgresRepository.CreateWebhookDelivery(ctx, &stored)
+}
+
+// CreateWebhookDeliveries creates the deliveries of a published event in a single
+// transaction, each inline payload sealed under its subscription's data key
+func (r *EncryptedRepository) CreateWebhookDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	stored := make([]models.WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		var err error
+		if stored[i], err = r.sealDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return r.PostgresRepository.CreateWebhookDeliveries(ctx, stored)
+}
+
+// sealDelivery returns a delivery as it is stored, with its inline payload replaced
+// by the ciphertext
+func (r *EncryptedRepository) sealDelivery(ctx context.Context, delivery models.WebhookDelivery) (models.WebhookDelivery, error) {
+	if delivery.Payload == nil {
+		return delivery, nil
+	}
+
+	keyID, key, err := r.subscriptionKey(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	ciphertext, err := envelope.Seal(keyID, key, delivery.Payload, payloadAAD(delivery.ID))
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	delivery.Payload = nil
+	delivery.PayloadCiphertext = ciphertext
+	return delivery, nil
+}
+
+// SealBlob encrypts a payload offloaded to the blob store under the data key of the
+// subscription it is delivered to
+func (r *EncryptedRepository) SealBlob(ctx context.Context, subscriptionID uuid.UUID, data []byte) ([]byte, error) {
+	keyID, key, err := r.subscriptionKey(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.Seal(keyID, key, data, blobAAD(subscriptionID))
+}
+
+// OpenBlob decrypts a payload blob sealed by SealBlob
+func (r *EncryptedRepository) OpenBlob(ctx context.Context, subscriptionID uuid.UUID, sealed []byte) ([]byte, error) {
+	data, err := r.open(ctx, sealed, blobAAD(subscriptionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt payload blob of subscription %s: %w", subscriptionID, err)
+	}
+	return data, nil
+}
+
+// SealArchivedPayload encrypts the payload of a delivery written to the archive under
+// its subscription's data key
+func (r *EncryptedRepository) SealArchivedPayload(ctx context.Context, delivery *models.WebhookDelivery) ([]byte, error) {
+	keyID, key, err := r.subscriptionKey(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.Seal(keyID, key, delivery.Payload, archivedPayloadAAD(delivery.ID))
+}
+
+// OpenArchivedPayload decrypts the payload of an archived delivery sealed by
+// SealArchivedPayload
+func (r *EncryptedRepository) OpenArchivedPayload(ctx context.Context, deliveryID uuid.UUID, sealed []byte) ([]byte, error) {
+	payload, err := r.open(ctx, sealed, archivedPayloadAAD(deliveryID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt archived payload of delivery %s: %w", deliveryID, err)
+	}
+	return payload, nil
+}
+
+// GetWebhookDelivery retrieves a webhook delivery by ID with its payload decrypted
+func (r *EncryptedRepository) GetWebhookDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	delivery, err := r.PostgresRepository.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.openDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// GetWebhookDeliveryAt retrieves a webhook delivery by ID and creation time with its
+// payload decrypted
+func (r *EncryptedRepository) GetWebhookDeliveryAt(ctx context.Context, id uuid.UUID, createdAt time.Time) (*models.WebhookDelivery, error) {
+	delivery, err := r.PostgresRepository.GetWebhookDeliveryAt(ctx, id, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.openDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// GetPendingDeliveries retrieves pending deliveries with their payloads decrypted
+func (r *EncryptedRepository) GetPendingDeliveries(ctx context.Context, limit int) ([]models.WebhookDelivery, error) {
+	deliveries, err := r.PostgresRepository.GetPendingDeliveries(ctx, limit)
+	return r.openDeliveries(ctx, deliveries, err)
+}
+
+// ListFailedDeliveries retrieves failed deliveries matching a replay filter with their
+// payloads decrypted
+func (r *EncryptedRepository) ListFailedDeliveries(ctx context.Context, filter models.ReplayRequest) ([]models.WebhookDelivery, error) {
+	deliveries, err := r.PostgresRepository.ListFailedDeliveries(ctx, filter)
+	return r.openDeliveries(ctx, deliveries, err)
+}
+
+// ListScheduledDeliveries retrieves scheduled deliveries with their payloads decrypted
+func (r *EncryptedRepository) ListScheduledDeliveries(ctx context.Context, filter models.ScheduledDeliveriesRequest) ([]models.WebhookDelivery, error) {
+	deliveries, err := r.PostgresRepository.ListScheduledDeliveries(ctx, filter)
+	return r.openDeliveries(ctx, deliveries, err)
+}
+
+// ListRetentionDeliveries retrieves deliveries past their retention with their
+// payloads decrypted
+func (r *EncryptedRepository) ListRetentionDeliveries(ctx context.Context, filter RetentionFilter) ([]models.WebhookDelivery, error) {
+	deliveries, err := r.PostgresRepository.ListRetentionDeliveries(ctx, filter)
+	return r.openDeliveries(ctx, deliveries, err)
+}
+
+// ListPartitionDeliveries retrieves the deliveries of a partition with their payloads
+// decrypted
+func (r *EncryptedRepository) ListPartitionDeliveries(ctx context.Context, name string, after PartitionCursor, limit int) ([]models.WebhookDelivery, error) {
+	deliveries, err := r.PostgresRepository.ListPartitionDeliveries(ctx, name, after, limit)
+	return r.openDeliveries(ctx, deliveries, err)
+}
+
+// GetRecentDeliveries retrieves recent deliveries for a subscription with their
+// payloads decrypted
+func (r *EncryptedRepository) GetRecentDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	deliveries, err := r.PostgresRepository.GetRecentDeliveries(ctx, subscriptionID, limit)
+	return r.openDeliveries(ctx, deliveries, err)
+}
+
+// ReencryptResult counts the rows changed by a re-encryption pass
+type ReencryptResult struct {
+	DataKeysRewrapped int
+	SecretsEncrypted  int
+	PayloadsEncrypted int
+}
+
+// Reencrypt re-wraps data keys that are not wrapped by the active master key, then
+// encrypts secret keys and payloads written before encryption was enabled. Rows are
+// processed batchSize at a time until none are left. Every update is conditional,
+// so concurrent passes are safe.
+func (r *EncryptedRepository) Reencrypt(ctx context.Context, batchSize int) (ReencryptResult, error) {
+	var result ReencryptResult
+	var err error
+
+	if result.DataKeysRewrapped, err = r.rewrapDataKeys(ctx, batchSize); err != nil {
+		return result, err
+	}
+	if result.SecretsEncrypted, err = r.encryptSecrets(ctx, batchSize); err != nil {
+		return result, err
+	}
+	result.PayloadsEncrypted, err = r.encryptPayloads(ctx, batchSize)
+	return result, err
+}
+
+// rewrapDataKeys re-wraps data keys with the active master key
+func (r *EncryptedRepository) rewrapDataKeys(ctx context.Context, batchSize int) (int, error) {
+	total := 0
+	for {
+		keys, err := r.ListDataKeysToRewrap(ctx, r.keyring.ActiveID(), batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		changed := 0
+		for _, dataKey := range keys {
+			key, err := r.keyring.Unwrap(dataKey.ID, dataKey.MasterKeyID, dataKey.WrappedKey)
+			if err != nil {
+				return total, fmt.Errorf("failed to unwrap data key %s: %w", dataKey.ID, err)
+			}
+
+			previous := dataKey.MasterKeyID
+			if dataKey.MasterKeyID, dataKey.WrappedKey, err = r.keyring.Wrap(dataKey.ID, key); err != nil {
+				return total, err
+			}
+			ok, err := r.RewrapDataKey(ctx, &dataKey, previous)
+			if err != nil {
+				return total, err
+			}
+			if ok {
+				changed++
+			}
+		}
+
+		total += changed
+		if len(keys) < batchSize || changed == 0 {
+			return total, nil
+		}
+	}
+}
+
+// encryptSecrets seals secret keys that are stored in plaintext
+func (r *EncryptedRepository) encryptSecrets(ctx context.Context, batchSize int) (int, error) {
+	total := 0
+	for {
+		subs, err := r.ListSubscriptionsWithPlaintextSecret(ctx, envelope.SealedPrefix, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		changed := 0
+		for _, sub := range subs {
+			keyID, key, err := r.subscriptionKey(ctx, sub.ID)
+			if err != nil {
+				return total, err
+			}
+			sealed, err := envelope.SealString(keyID, key, *sub.SecretKey, secretAAD(sub.ID))
+			if err != nil {
+				return total, err
+			}
+			ok, err := r.ReplaceSubscriptionSecret(ctx, sub.ID, *sub.SecretKey, sealed)
+			if err != nil {
+				return total, err
+			}
+			if ok {
+				changed++
+			}
+		}
+
+		total += changed
+		if len(subs) < batchSize || changed == 0 {
+			return total, nil
+		}
+	}
+}
+
+// encryptPayloads seals inline payloads that are stored in plaintext
+func (r *EncryptedRepository) encryptPayloads(ctx context.Context, batchSize int) (int, error) {
+	total := 0
+	for {
+		deliveries, err := r.ListPlaintextDeliveries(ctx, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		changed := 0
+		for _, delivery := range deliveries {
+			keyID, key, err := r.subscriptionKey(ctx, delivery.SubscriptionID)
+			if err != nil {
+				return total, err
+			}
+			ciphertext, err := envelope.Seal(keyID, key, delivery.Payload, payloadAAD(delivery.ID))
+			if err != nil {
+				return total, err
+			}
+			ok, err := r.EncryptDeliveryPayload(ctx, delivery.ID, ciphertext)
+			if err != nil {
+				return total, err
+			}
+			if ok {
+				changed++
+			}
+		}
+
+		total += changed
+		if len(deliveries) < batchSize || changed == 0 {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/envelope"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// masterKeyEntry returns a keyring entry for a new random master key
+func masterKeyEntry(t *testing.T, id string) string {
+	t.Helper()
+	key, err := envelope.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id + ":" + base64.StdEncoding.EncodeToString(key)
+}
+
+// newTestKeyring parses keyring entries, the first one is the active key
+func newTestKeyring(t *testing.T, entries ...string) *envelope.Keyring {
+	t.Helper()
+	keyring, err := envelope.ParseKeyring(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyring
+}
+
+// newCachedRepository returns an EncryptedRepository without a database whose key
+// caches hold a data key for each subscription
+func newCachedRepository(t *testing.T, subscriptionIDs ...uuid.UUID) *EncryptedRepository {
+	t.Helper()
+	r := NewEncryptedRepository(nil, newTestKeyring(t, masterKeyEntry(t, "primary")))
+	for _, subscriptionID := range subscriptionIDs {
+		key, err := envelope.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyID := uuid.New()
+		r.dataKeys.Store(keyID, key)
+		r.subscriptionKeys.Store(subscriptionID, keyID)
+	}
+	return r
+}
+
+func TestEncryptedRepositoryRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	subscriptionID := uuid.New()
+	otherSubscriptionID := uuid.New()
+	deliveryID := uuid.New()
+	r := newCachedRepository(t, subscriptionID, otherSubscriptionID)
+	payload := []byte(`{"order":1,"card":"4242"}`)
+
+	tests := []struct {
+		name string
+		// seal encrypts payload for subscriptionID, open decrypts it for the given
+		// subscription and delivery
+		seal func() ([]byte, error)
+		open func(subscriptionID, deliveryID uuid.UUID, sealed []byte) ([]byte, error)
+	}{
+		{
+			name: "payload blob",
+			seal: func() ([]byte, error) { return r.SealBlob(ctx, subscriptionID, payload) },
+			open: func(subscriptionID, _ uuid.UUID, sealed []byte) ([]byte, error) {
+				return r.OpenBlob(ctx, subscriptionID, sealed)
+			},
+		},
+		{
+			name: "archived payload",
+			seal: func() ([]byte, error) {
+				return r.SealArchivedPayload(ctx, &models.WebhookDelivery{ID: deliveryID, SubscriptionID: subscriptionID, Payload: payload})
+			},
+			open: func(_, deliveryID uuid.UUID, sealed []byte) ([]byte, error) {
+				return r.OpenArchivedPayload(ctx, deliveryID, sealed)
+			},
+		},
+		{
+			name: "inline payload",
+			seal: func() ([]byte, error) {
+				delivery, err := r.sealDelivery(ctx, models.WebhookDelivery{ID: deliveryID, SubscriptionID: subscriptionID, Payload: payload})
+				if delivery.Payload != nil {
+					t.Fatal("sealed delivery still holds its plaintext payload")
+				}
+				return delivery.PayloadCiphertext, err
+			},
+			open: func(_, deliveryID uuid.UUID, sealed []byte) ([]byte, error) {
+				delivery := models.WebhookDelivery{ID: deliveryID, PayloadCiphertext: sealed}
+				err := r.openDelivery(ctx, &delivery)
+				return delivery.Payload, err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sealed, err := tt.seal()
+			if err != nil {
+				t.Fatalf("seal error = %v", err)
+			}
+			if bytes.Contains(sealed, payload) {
+				t.Fatal("sealed payload contains the plaintext")
+			}
+
+			got, err := tt.open(subscriptionID, deliveryID, sealed)
+			if err != nil {
+				t.Fatalf("open error = %v", err)
+			}
+			if !bytes.Equal(got, payload) {
+				t.Fatalf("open = %s, want %s", got, payload)
+			}
+
+			// The ciphertext is bound to its subscription or delivery
+			if _, err := tt.open(otherSubscriptionID, uuid.New(), sealed); err == nil {
+				t.Fatal("open for another subscription or delivery succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestEncryptedRepositoryReadsPlaintextRows(t *testing.T) {
+	ctx := context.Background()
+	subscriptionID := uuid.New()
+	r := newCachedRepository(t, subscriptionID)
+
+	// Rows written before encryption was enabled are returned as they are
+	secret := "whsec_plaintext"
+	sub := models.Subscription{ID: subscriptionID, SecretKey: &secret}
+	if err := r.openSubscription(ctx, &sub); err != nil {
+		t.Fatalf("openSubscription() error = %v", err)
+	}
+	if *sub.SecretKey != secret || sub.PreviousSecretKey != nil {
+		t.Fatalf("openSubscription() changed a plaintext secret to %v", sub.SecretKey)
+	}
+
+	delivery := models.WebhookDelivery{ID: uuid.New(), SubscriptionID: subscriptionID, Payload: json.RawMessage(`{"order":1}`)}
+	if err := r.openDelivery(ctx, &delivery); err != nil {
+		t.Fatalf("openDelivery() error = %v", err)
+	}
+	if string(delivery.Payload) != `{"order":1}` {
+		t.Fatalf("openDelivery() changed a plaintext payload to %s", delivery.Payload)
+	}
+
+	// Sealed secrets are opened in place
+	keyID, _ := r.subscriptionKeys.Load(subscriptionID)
+	key, _ := r.dataKeys.Load(keyID)
+	stored, err := sealSubscription(&models.Subscription{ID: subscriptionID, SecretKey: &secret}, keyID.(uuid.UUID), key.([]byte))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !envelope.IsSealedString(*stored.SecretKey) {
+		t.Fatalf("sealSubscription() stored the secret as %q", *stored.SecretKey)
+	}
+	if err := r.openSubscription(ctx, stored); err != nil {
+		t.Fatalf("openSubscription() error = %v", err)
+	}
+	if *stored.SecretKey != secret {
+		t.Fatalf("openSubscription() = %q, want %q", *stored.SecretKey, secret)
+	}
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// Tables range partitioned on created_at
+const (
+	DeliveriesTable = "webhook_deliveries"
+	AttemptsTable   = "delivery_attempts"
+)
+
+// Partition is a range partition of a partitioned table, covering rows created from
+// Start up to End. Start is nil for the partition of the rows created before the
+// tables were partitioned. The default partition is not listed.
+type Partition struct {
+	Name  string     `db:"name"`
+	Start *time.Time `db:"range_start"`
+	End   *time.Time `db:"range_end"`
+}
+
+// PartitionCursor is the position after which ListPartitionDeliveries continues. The
+// zero value starts at the beginning.
+type PartitionCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// PartitionName returns the name of a table's partition starting at start
+func PartitionName(table string, start time.Time) string {
+	return table + "_p" + start.UTC().Format("20060102")
+}
+
+// defaultPartition returns the name of a table's default partition
+func defaultPartition(table string) string {
+	return table + "_default"
+}
+
+// ListPartitions returns the range partitions of a table, ordered by their end
+func (r *PostgresRepository) ListPartitions(ctx context.Context, table string) ([]Partition, error) {
+	query := `
+		SELECT c.relname AS name,
+			(regexp_match(pg_get_expr(c.relpartbound, c.oid), 'FROM \(''([^'']*)''\)'))[1]::timestamptz AS range_start,
+			(regexp_match(pg_get_expr(c.relpartbound, c.oid), 'TO \(''([^'']*)''\)'))[1]::timestamptz AS range_end
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = $1::regclass AND pg_get_expr(c.relpartbound, c.oid) <> 'DEFAULT'
+		ORDER BY range_end ASC
+	`
+	var partitions []Partition
+	err := r.db.SelectContext(ctx, &partitions, query, table)
+	return partitions, err
+}
+
+// CreatePartition creates the partition of a table for rows created from start up to
+// end and returns its name. Rows of that range already in the default partition are
+// moved into it, otherwise the partition could not be created.
+func (r *PostgresRepository) CreatePartition(ctx context.Context, table string, start, end time.Time) (string, error) {
+	name := PartitionName(table, start)
+	bounds := fmt.Sprintf("FOR VALUES FROM (%s) TO (%s)",
+		pq.QuoteLiteral(start.UTC().Format(time.RFC3339)), pq.QuoteLiteral(end.UTC().Format(time.RFC3339)))
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var stray bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE created_at >= $1 AND created_at < $2)`,
+		pq.QuoteIdentifier(defaultPartition(table)))
+	if err := tx.GetContext(ctx, &stray, query, start, end); err != nil {
+		return "", err
+	}
+
+	if !stray {
+		query = fmt.Sprintf(`CREATE TABLE %s PARTITION OF %s %s`, pq.QuoteIdentifier(name), pq.QuoteIdentifier(table), bounds)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return "", err
+		}
+		return name, tx.Commit()
+	}
+
+	query = fmt.Sprintf(`CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`,
+		pq.QuoteIdentifier(name), pq.QuoteIdentifier(table))
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return "", err
+	}
+	query = fmt.Sprintf(`
+		WITH moved AS (
+			DELETE FROM %s WHERE created_at >= $1 AND created_at < $2 RETURNING *
+		)
+		INSERT INTO %s SELECT * FROM moved
+	`, pq.QuoteIdentifier(defaultPartition(table)), pq.QuoteIdentifier(name))
+	if _, err := tx.ExecContext(ctx, query, start, end); err != nil {
+		return "", err
+	}
+	query = fmt.Sprintf(`ALTER TABLE %s ATTACH PARTITION %s %s`, pq.QuoteIdentifier(table), pq.QuoteIdentifier(name), bounds)
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return "", err
+	}
+	return name, tx.Commit()
+}
+
+// partitionLockTimeout bounds how long dropping a partition waits for the lock on its
+// table, so that writes do not queue up behind it. A partition that could not be
+// locked is dropped on a later run.
+const partitionLockTimeout = "5s"
+
+// DropPartition drops a partition with all of its rows
+func (r *PostgresRepository) DropPartition(ctx context.Context, name string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SET LOCAL lock_timeout = `+pq.QuoteLiteral(partitionLockTimeout)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, pq.QuoteIdentifier(name))); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AttemptPartitionInUse reports whether a partition of delivery_attempts holds
+// attempts of deliveries that have not been archived yet, i.e. that are neither
+// removed nor stripped of their payload
+func (r *PostgresRepository) AttemptPartitionInUse(ctx context.Context, name string) (bool, error) {
+	query := fmt.Sprintf(`
+		SELECT EXISTS (
+			SELECT 1 FROM %s da
+			JOIN webhook_deliveries wd ON wd.id = da.delivery_id AND wd.created_at <= da.created_at
+			WHERE wd.payload_purged_at IS NULL
+		)
+	`, pq.QuoteIdentifier(name))
+	var inUse bool
+	err := r.db.GetContext(ctx, &inUse, query)
+	return inUse, err
+}
+
+// DropDeliveryPartition drops a partition of webhook_deliveries, and deletes the
+// attempts of its deliveries, unless one of its deliveries is in one of the given
+// states or has attempts at or after attemptsSince, and reports whether it did. Only the partition is locked against writes
+// while checking, so no delivery can change state in between. The table itself is
+// locked just for the drop. DETACH PARTITION CONCURRENTLY would avoid that, but it is
+// not allowed while the table has a default partition.
+func (r *PostgresRepository) DropDeliveryPartition(ctx context.Context, name string, unlessStatuses []string, attemptsSince time.Time) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SET LOCAL lock_timeout = `+pq.QuoteLiteral(partitionLockTimeout)); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`LOCK TABLE %s IN SHARE MODE`, pq.QuoteIdentifier(name))); err != nil {
+		return false, err
+	}
+
+	var active bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE status = ANY($1))`, pq.QuoteIdentifier(name))
+	if err := tx.GetContext(ctx, &active, query, pq.Array(unlessStatuses)); err != nil {
+		return false, err
+	}
+	if active {
+		return false, nil
+	}
+
+	// Attempts still counted by the stats rollup would drop out of their buckets
+	var recent bool
+	query = fmt.Sprintf(`
+		SELECT EXISTS (
+			SELECT 1 FROM delivery_attempts da
+			JOIN %s wd ON da.delivery_id = wd.id
+			WHERE da.created_at >= $1 AND da.created_at >= wd.created_at
+		)
+	`, pq.QuoteIdentifier(name))
+	if err := tx.GetContext(ctx, &recent, query, attemptsSince); err != nil {
+		return false, err
+	}
+	if recent {
+		return false, nil
+	}
+
+	// Attempts of retries are created after their delivery and may be in a later
+	// partition of delivery_attempts, which would keep them after the delivery is gone
+	query = fmt.Sprintf(`
+		DELETE FROM delivery_attempts da
+		USING %s wd
+		WHERE da.delivery_id = wd.id AND da.created_at >= wd.created_at
+	`, pq.QuoteIdentifier(name))
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DROP TABLE %s`, pq.QuoteIdentifier(name))); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// CountPartitionDeliveries counts the deliveries of a webhook_deliveries partition by status
+func (r *PostgresRepository) CountPartitionDeliveries(ctx context.Context, name string) (map[string]int64, error) {
+	query := fmt.Sprintf(`SELECT status, COUNT(*) FROM %s GROUP BY status`, pq.QuoteIdentifier(name))
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
+// ListPartitionDeliveries returns up to limit deliveries of a webhook_deliveries
+// partition after a cursor, in creation order
+func (r *PostgresRepository) ListPartitionDeliveries(ctx context.Context, name string, after PartitionCursor, limit int) ([]models.WebhookDelivery, error) {
+	query := fmt.Sprintf(`
+		SELECT * FROM %s
+		WHERE (created_at, id) > ($1, $2)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3
+	`, pq.QuoteIdentifier(name))
+	var deliveries []models.WebhookDelivery
+	err := r.db.SelectContext(ctx, &deliveries, query, after.CreatedAt, after.ID, limit)
+	return deliveries, err
+}
+
+// ListPartitionPayloadRefs returns the blob references of the offloaded payloads in a
+// webhook_deliveries partition
+func (r *PostgresRepository) ListPartitionPayloadRefs(ctx context.Context, name string) ([]string, error) {
+	query := fmt.Sprintf(`SELECT DISTINCT payload_ref FROM %s WHERE payload_ref IS NOT NULL`, pq.QuoteIdentifier(name))
+	var refs []string
+	err := r.db.SelectContext(ctx, &refs, query)
+	return refs, err
+}
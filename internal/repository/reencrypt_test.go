@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"github.com/Unic-X/webhook-delivery/internal/envelope"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// testDB connects to the database in TEST_DATABASE_URL and migrates it, the test is
+// skipped when it is not set. The database should be dedicated to the tests.
+func testDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	m, err := migrate.New("file://../../migrations", url)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	m.Close()
+
+	db, err := sqlx.Connect("postgres", url)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// createPlaintextRows stores a subscription and a delivery the way they were written
+// before encryption was enabled
+func createPlaintextRows(t *testing.T, repo *PostgresRepository) (*models.Subscription, *models.WebhookDelivery) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+
+	secret := "whsec_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	sub := &models.Subscription{
+		ID:              uuid.New(),
+		TargetURL:       "https://example.com/webhooks",
+		SecretKey:       &secret,
+		SignatureScheme: models.SignatureSchemeGitHub,
+		DestinationType: models.DestinationHTTP,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := repo.CreateSubscription(ctx, sub); err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+	t.Cleanup(func() { _ = repo.DeleteSubscriptionWithDataKey(context.Background(), sub.ID) })
+
+	delivery := &models.WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: sub.ID,
+		Payload:        []byte(`{"order": 1}`),
+		CreatedAt:      now,
+		Status:         models.StatusPending,
+		MaxRetries:     5,
+	}
+	if err := repo.CreateWebhookDelivery(ctx, delivery); err != nil {
+		t.Fatalf("CreateWebhookDelivery() error = %v", err)
+	}
+	return sub, delivery
+}
+
+func TestReencrypt(t *testing.T) {
+	ctx := context.Background()
+	db := NewPostgresRepository(testDB(t))
+	sub, delivery := createPlaintextRows(t, db)
+
+	k1 := masterKeyEntry(t, "k1")
+	k2 := masterKeyEntry(t, "k2")
+
+	// Encrypt the rows written in plaintext
+	repo := NewEncryptedRepository(db, newTestKeyring(t, k1))
+	result, err := repo.Reencrypt(ctx, 10)
+	if err != nil {
+		t.Fatalf("Reencrypt() error = %v", err)
+	}
+	if result.SecretsEncrypted < 1 || result.PayloadsEncrypted < 1 {
+		t.Fatalf("Reencrypt() = %+v, want the secret and the payload encrypted", result)
+	}
+
+	stored, err := db.GetSubscription(ctx, sub.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !envelope.IsSealedString(*stored.SecretKey) || stored.DataKeyID == nil {
+		t.Fatalf("secret key stored as %q with data key %v, want it sealed", *stored.SecretKey, stored.DataKeyID)
+	}
+	storedDelivery, err := db.GetWebhookDelivery(ctx, delivery.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storedDelivery.Payload != nil || storedDelivery.PayloadCiphertext == nil {
+		t.Fatal("payload is still stored in plaintext")
+	}
+
+	// A second pass has nothing left to do
+	result, err = repo.Reencrypt(ctx, 10)
+	if err != nil {
+		t.Fatalf("Reencrypt() error = %v", err)
+	}
+	if result != (ReencryptResult{}) {
+		t.Fatalf("second Reencrypt() = %+v, want nothing changed", result)
+	}
+
+	// Rotate to k2, keeping k1 to unwrap the existing data keys
+	rotated := NewEncryptedRepository(db, newTestKeyring(t, k2, k1))
+	result, err = rotated.Reencrypt(ctx, 10)
+	if err != nil {
+		t.Fatalf("Reencrypt() after rotation error = %v", err)
+	}
+	if result.DataKeysRewrapped < 1 {
+		t.Fatalf("Reencrypt() after rotation = %+v, want data keys re-wrapped", result)
+	}
+	dataKey, err := db.GetDataKey(ctx, *stored.DataKeyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dataKey.MasterKeyID != "k2" || dataKey.RotatedAt == nil {
+		t.Fatalf("data key wrapped by %q, rotated at %v, want k2", dataKey.MasterKeyID, dataKey.RotatedAt)
+	}
+
+	// k1 can be retired, everything is readable with k2 alone
+	retired := NewEncryptedRepository(db, newTestKeyring(t, k2))
+	got, err := retired.GetSubscription(ctx, sub.ID)
+	if err != nil {
+		t.Fatalf("GetSubscription() after retiring k1 error = %v", err)
+	}
+	if *got.SecretKey != *sub.SecretKey {
+		t.Fatalf("GetSubscription() secret = %q, want %q", *got.SecretKey, *sub.SecretKey)
+	}
+	gotDelivery, err := retired.GetWebhookDelivery(ctx, delivery.ID)
+	if err != nil {
+		t.Fatalf("GetWebhookDelivery() after retiring k1 error = %v", err)
+	}
+	if !bytes.Equal(gotDelivery.Payload, delivery.Payload) {
+		t.Fatalf("GetWebhookDelivery() payload = %s, want %s", gotDelivery.Payload, delivery.Payload)
+	}
+
+	// A keyring without the master key the data key is wrapped by fails to read,
+	// it does not panic or return the ciphertext
+	unknown := NewEncryptedRepository(db, newTestKeyring(t, masterKeyEntry(t, "k3")))
+	if _, err := unknown.GetSubscription(ctx, sub.ID); err == nil || !strings.Contains(err.Error(), "not in the keyring") {
+		t.Fatalf("GetSubscription() with an unknown master key error = %v, want the key to be missing", err)
+	}
+	if _, err := unknown.Reencrypt(ctx, 10); err == nil {
+		t.Fatal("Reencrypt() with an unknown master key succeeded, want an error")
+	}
+}
@@ -2,11 +2,17 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/internal/tracing"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Repository defines the interface for database operations
@@ -15,24 +21,67 @@ type Repository interface {
 	CreateSubscription(ctx context.Context, sub *models.Subscription) error
 	GetSubscription(ctx context.Context, id uuid.UUID) (*models.Subscription, error)
 	UpdateSubscription(ctx context.Context, sub *models.Subscription) error
+	RotateSubscriptionSecret(ctx context.Context, sub *models.Subscription) error
+	ClearExpiredPreviousSecrets(ctx context.Context, now time.Time) (int64, error)
 	DeleteSubscription(ctx context.Context, id uuid.UUID) error
 	ListSubscriptions(ctx context.Context) ([]models.Subscription, error)
+	ListSubscriptionsForEventType(ctx context.Context, eventType string) ([]models.Subscription, error)
+
+	// Event type catalog
+	CreateEventType(ctx context.Context, eventType *models.EventType, version *models.EventTypeVersion) error
+	CreateEventTypeVersion(ctx context.Context, version *models.EventTypeVersion) error
+	GetEventType(ctx context.Context, name string) (*models.EventType, error)
+	ListEventTypes(ctx context.Context) ([]models.EventType, error)
+	ListEventTypeVersions(ctx context.Context, name string) ([]models.EventTypeVersion, error)
+	GetLatestEventTypeVersion(ctx context.Context, name string) (*models.EventTypeVersion, error)
+	FindUnknownEventTypes(ctx context.Context, names []string) ([]string, error)
 
 	// Webhook delivery operations
 	CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	CreateWebhookDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
 	GetWebhookDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error)
+	GetWebhookDeliveryAt(ctx context.Context, id uuid.UUID, createdAt time.Time) (*models.WebhookDelivery, error)
 	UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
 	GetPendingDeliveries(ctx context.Context, limit int) ([]models.WebhookDelivery, error)
+	CountDeliveriesByStatus(ctx context.Context, status string) (int64, error)
+	ListFailedDeliveries(ctx context.Context, filter models.ReplayRequest) ([]models.WebhookDelivery, error)
+	RequeueFailedDelivery(ctx context.Context, id uuid.UUID, createdAt time.Time, maxRetries int) (bool, error)
+	ClaimWebhookDelivery(ctx context.Context, id uuid.UUID, createdAt time.Time) (bool, error)
+	ListScheduledDeliveries(ctx context.Context, filter models.ScheduledDeliveriesRequest) ([]models.WebhookDelivery, error)
+	CancelScheduledDelivery(ctx context.Context, id uuid.UUID) (bool, error)
 
 	// Delivery attempt operations
 	CreateDeliveryAttempt(ctx context.Context, attempt *models.DeliveryAttempt) error
-	GetDeliveryAttempts(ctx context.Context, deliveryID uuid.UUID) ([]models.DeliveryAttempt, error)
+	GetDeliveryAttempts(ctx context.Context, deliveryID uuid.UUID, deliveryCreatedAt time.Time) ([]models.DeliveryAttempt, error)
 
 	// Log retention
-	DeleteOldDeliveryAttempts(ctx context.Context, olderThan time.Time) (int64, error)
+	ListRetentionPolicies(ctx context.Context) (map[uuid.UUID]models.RetentionPolicy, error)
+	ListRetentionDeliveries(ctx context.Context, filter RetentionFilter) ([]models.WebhookDelivery, error)
+	ListDeliveryAttempts(ctx context.Context, deliveryIDs []uuid.UUID) ([]models.DeliveryAttempt, error)
+	DeleteDeliveries(ctx context.Context, filter RetentionFilter) (RetentionResult, error)
+	StripDeliveryPayloads(ctx context.Context, filter RetentionFilter) (RetentionResult, error)
+	PayloadRefInUse(ctx context.Context, ref string) (bool, error)
+	LockPayloadRef(ctx context.Context, ref string, exclusive bool) (func(), error)
+
+	// Partition maintenance
+	ListPartitions(ctx context.Context, table string) ([]Partition, error)
+	CreatePartition(ctx context.Context, table string, start, end time.Time) (string, error)
+	DropPartition(ctx context.Context, name string) error
+	AttemptPartitionInUse(ctx context.Context, name string) (bool, error)
+	DropDeliveryPartition(ctx context.Context, name string, unlessStatuses []string, attemptsSince time.Time) (bool, error)
+	CountPartitionDeliveries(ctx context.Context, name string) (map[string]int64, error)
+	ListPartitionDeliveries(ctx context.Context, name string, after PartitionCursor, limit int) ([]models.WebhookDelivery, error)
+	ListPartitionPayloadRefs(ctx context.Context, name string) ([]string, error)
+
+	// Audit log
+	CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter models.AuditEventsRequest) ([]models.AuditEvent, error)
 
 	// Analytics
 	GetRecentDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]models.WebhookDelivery, error)
+	RollupDeliveryStats(ctx context.Context, from, to time.Time) (int64, error)
+	GetDeliveryStats(ctx context.Context, subscriptionID uuid.UUID, from, to time.Time) ([]models.DeliveryStats, error)
+	ComputeDeliveryStats(ctx context.Context, subscriptionID uuid.UUID, from, to time.Time) ([]models.DeliveryStats, error)
 }
 
 // PostgresRepository implements the Repository interface
@@ -48,11 +97,13 @@ func NewPostgresRepository(db *sqlx.DB) *PostgresRepository {
 // CreateSubscription creates a new subscription
 func (r *PostgresRepository) CreateSubscription(ctx context.Context, sub *models.Subscription) error {
 	query := `
-		INSERT INTO subscriptions (id, target_url, secret_key, event_types, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO subscriptions (id, target_url, secret_key, event_types, destination_type, destination_config, signature_scheme,
+			max_event_age_seconds, retention_policy, data_key_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 	_, err := r.db.ExecContext(ctx, query,
-		sub.ID, sub.TargetURL, sub.SecretKey, sub.EventTypes, sub.CreatedAt, sub.UpdatedAt)
+		sub.ID, sub.TargetURL, sub.SecretKey, sub.EventTypes, sub.DestinationType, sub.DestinationConfig,
+		sub.SignatureScheme, sub.MaxEventAgeSeconds, sub.RetentionPolicy, sub.DataKeyID, sub.CreatedAt, sub.UpdatedAt)
 	return err
 }
 
@@ -71,17 +122,62 @@ func (r *PostgresRepository) GetSubscription(ctx context.Context, id uuid.UUID)
 func (r *PostgresRepository) UpdateSubscription(ctx context.Context, sub *models.Subscription) error {
 	query := `
 		UPDATE subscriptions
-		SET target_url = $1, secret_key = $2, event_types = $3, updated_at = $4
-		WHERE id = $5
+		SET target_url = $1, secret_key = $2, event_types = $3, destination_type = $4, destination_config = $5,
+			signature_scheme = $6, max_event_age_seconds = $7, retention_policy = $8, updated_at = $9
+		WHERE id = $10
 	`
 	_, err := r.db.ExecContext(ctx, query,
-		sub.TargetURL, sub.SecretKey, sub.EventTypes, time.Now(), sub.ID)
+		sub.TargetURL, sub.SecretKey, sub.EventTypes, sub.DestinationType, sub.DestinationConfig,
+		sub.SignatureScheme, sub.MaxEventAgeSeconds, sub.RetentionPolicy, time.Now(), sub.ID)
 	return err
 }
 
-// DeleteSubscription deletes a subscription by ID
+// RotateSubscriptionSecret stores a subscription's current and previous signing secrets
+func (r *PostgresRepository) RotateSubscriptionSecret(ctx context.Context, sub *models.Subscription) error {
+	query := `
+		UPDATE subscriptions
+		SET secret_key = $1, previous_secret_key = $2, previous_secret_expires_at = $3, updated_at = $4
+		WHERE id = $5
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		sub.SecretKey, sub.PreviousSecretKey, sub.PreviousSecretExpiresAt, sub.UpdatedAt, sub.ID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ClearExpiredPreviousSecrets removes previous signing secrets that expired before now
+func (r *PostgresRepository) ClearExpiredPreviousSecrets(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		UPDATE subscriptions
+		SET previous_secret_key = NULL, previous_secret_expires_at = NULL
+		WHERE previous_secret_expires_at <= $1
+	`
+	result, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteSubscription deletes a subscription by ID. Its deliveries are deleted by the
+// foreign key and their attempts along with them.
 func (r *PostgresRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM subscriptions WHERE id = $1`
+	query := `
+		WITH attempts AS (
+			DELETE FROM delivery_attempts
+			WHERE delivery_id IN (SELECT id FROM webhook_deliveries WHERE subscription_id = $1)
+		)
+		DELETE FROM subscriptions WHERE id = $1
+	`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
@@ -94,19 +190,202 @@ func (r *PostgresRepository) ListSubscriptions(ctx context.Context) ([]models.Su
 	return subs, err
 }
 
+// ListSubscriptionsForEventType returns the subscriptions that accept an event type,
+// including those without an event type filter
+func (r *PostgresRepository) ListSubscriptionsForEventType(ctx context.Context, eventType string) ([]models.Subscription, error) {
+	query := `
+		SELECT * FROM subscriptions
+		WHERE event_types IS NULL OR cardinality(event_types) = 0 OR $1 = ANY(event_types)
+		ORDER BY created_at ASC
+	`
+	var subs []models.Subscription
+	err := r.db.SelectContext(ctx, &subs, query, eventType)
+	return subs, err
+}
+
+// eventTypeColumns selects an event type along with its latest version number
+const eventTypeColumns = `
+	e.name, e.description, e.created_at, e.updated_at,
+	COALESCE((SELECT MAX(v.version) FROM event_type_versions v WHERE v.event_type = e.name), 0) AS latest_version
+`
+
+// CreateEventType creates an event type together with its first schema version
+func (r *PostgresRepository) CreateEventType(ctx context.Context, eventType *models.EventType, version *models.EventTypeVersion) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO event_types (name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.ExecContext(ctx, query,
+		eventType.Name, eventType.Description, eventType.CreatedAt, eventType.UpdatedAt); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO event_type_versions (event_type, version, schema, examples, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := tx.ExecContext(ctx, query,
+		version.EventType, version.Version, version.Schema, version.Examples, version.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateEventTypeVersion adds the next schema version to an event type and sets
+// version.Version to its number
+func (r *PostgresRepository) CreateEventTypeVersion(ctx context.Context, version *models.EventTypeVersion) error {
+	query := `
+		INSERT INTO event_type_versions (event_type, version, schema, examples, created_at)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4
+		FROM event_type_versions WHERE event_type = $1
+		RETURNING version
+	`
+	if err := r.db.GetContext(ctx, &version.Version, query,
+		version.EventType, version.Schema, version.Examples, version.CreatedAt); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `UPDATE event_types SET updated_at = $1 WHERE name = $2`, version.CreatedAt, version.EventType)
+	return err
+}
+
+// GetEventType retrieves an event type by name
+func (r *PostgresRepository) GetEventType(ctx context.Context, name string) (*models.EventType, error) {
+	query := `SELECT ` + eventTypeColumns + ` FROM event_types e WHERE e.name = $1`
+	var eventType models.EventType
+	err := r.db.GetContext(ctx, &eventType, query, name)
+	if err != nil {
+		return nil, err
+	}
+	return &eventType, nil
+}
+
+// ListEventTypes retrieves all event types ordered by name
+func (r *PostgresRepository) ListEventTypes(ctx context.Context) ([]models.EventType, error) {
+	query := `SELECT ` + eventTypeColumns + ` FROM event_types e ORDER BY e.name`
+	var eventTypes []models.EventType
+	err := r.db.SelectContext(ctx, &eventTypes, query)
+	return eventTypes, err
+}
+
+// ListEventTypeVersions retrieves all schema versions of an event type, newest first
+func (r *PostgresRepository) ListEventTypeVersions(ctx context.Context, name string) ([]models.EventTypeVersion, error) {
+	query := `
+		SELECT * FROM event_type_versions
+		WHERE event_type = $1
+		ORDER BY version DESC
+	`
+	var versions []models.EventTypeVersion
+	err := r.db.SelectContext(ctx, &versions, query, name)
+	return versions, err
+}
+
+// GetLatestEventTypeVersion retrieves the newest schema version of an event type
+func (r *PostgresRepository) GetLatestEventTypeVersion(ctx context.Context, name string) (*models.EventTypeVersion, error) {
+	query := `
+		SELECT * FROM event_type_versions
+		WHERE event_type = $1
+		ORDER BY version DESC
+		LIMIT 1
+	`
+	var version models.EventTypeVersion
+	err := r.db.GetContext(ctx, &version, query, name)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// FindUnknownEventTypes returns the names that are not in the event type catalog
+func (r *PostgresRepository) FindUnknownEventTypes(ctx context.Context, names []string) ([]string, error) {
+	query := `
+		SELECT DISTINCT t.name FROM unnest($1::text[]) AS t(name)
+		WHERE NOT EXISTS (SELECT 1 FROM event_types e WHERE e.name = t.name)
+		ORDER BY t.name
+	`
+	unknown := []string{}
+	err := r.db.SelectContext(ctx, &unknown, query, pq.Array(names))
+	return unknown, err
+}
+
 // CreateWebhookDelivery creates a new webhook delivery
 func (r *PostgresRepository) CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	ctx, span := tracing.Tracer().Start(ctx, "repository.CreateWebhookDelivery",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "postgresql")),
+	)
+	defer span.End()
+
+	err := insertWebhookDelivery(ctx, r.db, delivery)
+	if err != nil {
+		tracing.RecordError(span, err)
+	}
+	return err
+}
+
+// CreateWebhookDeliveries creates the deliveries of a published event in a single
+// transaction, so either all of them are stored or none
+func (r *PostgresRepository) CreateWebhookDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	ctx, span := tracing.Tracer().Start(ctx, "repository.CreateWebhookDeliveries",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "postgresql"), attribute.Int("webhook.delivery_count", len(deliveries))),
+	)
+	defer span.End()
+
+	err := r.createWebhookDeliveries(ctx, deliveries)
+	if err != nil {
+		tracing.RecordError(span, err)
+	}
+	return err
+}
+
+// createWebhookDeliveries inserts deliveries in one transaction
+func (r *PostgresRepository) createWebhookDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := range deliveries {
+		if err := insertWebhookDelivery(ctx, tx, &deliveries[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// insertWebhookDelivery inserts a delivery row
+func insertWebhookDelivery(ctx context.Context, exec sqlx.ExecerContext, delivery *models.WebhookDelivery) error {
 	query := `
-		INSERT INTO webhook_deliveries (id, subscription_id, payload, event_type, created_at, status, next_retry_at, retry_count, max_retries)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO webhook_deliveries (id, subscription_id, payload, payload_ciphertext, payload_ref, payload_sha256, payload_size,
+			event_type, created_at, expires_at, status, next_retry_at, retry_count, max_retries)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
-	_, err := r.db.ExecContext(ctx, query,
-		delivery.ID, delivery.SubscriptionID, delivery.Payload, delivery.EventType,
-		delivery.CreatedAt, delivery.Status, delivery.NextRetryAt, delivery.RetryCount, delivery.MaxRetries)
+	_, err := exec.ExecContext(ctx, query,
+		delivery.ID, delivery.SubscriptionID, nullBytes(delivery.Payload), nullBytes(delivery.PayloadCiphertext), delivery.PayloadRef, delivery.PayloadSHA256, delivery.PayloadSize,
+		delivery.EventType, delivery.CreatedAt, delivery.ExpiresAt, delivery.Status, delivery.NextRetryAt, delivery.RetryCount, delivery.MaxRetries)
 	return err
 }
 
-// GetWebhookDelivery retrieves a webhook delivery by ID
+// nullBytes maps an empty byte slice to NULL. Offloaded and encrypted payloads are
+// stored as NULL rather than an empty value.
+func nullBytes(b []byte) interface{} {
+	if b == nil {
+		return nil
+	}
+	return b
+}
+
+// GetWebhookDelivery retrieves a webhook delivery by ID. Without its creation time
+// the lookup probes every partition, GetWebhookDeliveryAt reads only one.
 func (r *PostgresRepository) GetWebhookDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
 	query := `SELECT * FROM webhook_deliveries WHERE id = $1`
 	var delivery models.WebhookDelivery
@@ -117,15 +396,28 @@ func (r *PostgresRepository) GetWebhookDelivery(ctx context.Context, id uuid.UUI
 	return &delivery, nil
 }
 
-// UpdateWebhookDelivery updates an existing webhook delivery
+// GetWebhookDeliveryAt retrieves a webhook delivery by its primary key, ID and
+// creation time
+func (r *PostgresRepository) GetWebhookDeliveryAt(ctx context.Context, id uuid.UUID, createdAt time.Time) (*models.WebhookDelivery, error) {
+	query := `SELECT * FROM webhook_deliveries WHERE id = $1 AND created_at = $2`
+	var delivery models.WebhookDelivery
+	err := r.db.GetContext(ctx, &delivery, query, id, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// UpdateWebhookDelivery updates an existing webhook delivery, found by its ID and
+// creation time
 func (r *PostgresRepository) UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
 	query := `
 		UPDATE webhook_deliveries
 		SET status = $1, next_retry_at = $2, retry_count = $3
-		WHERE id = $4
+		WHERE id = $4 AND created_at = $5
 	`
 	_, err := r.db.ExecContext(ctx, query,
-		delivery.Status, delivery.NextRetryAt, delivery.RetryCount, delivery.ID)
+		delivery.Status, delivery.NextRetryAt, delivery.RetryCount, delivery.ID, delivery.CreatedAt)
 	return err
 }
 
@@ -142,6 +434,113 @@ func (r *PostgresRepository) GetPendingDeliveries(ctx context.Context, limit int
 	return deliveries, err
 }
 
+// CountDeliveriesByStatus counts webhook deliveries in the given status
+func (r *PostgresRepository) CountDeliveriesByStatus(ctx context.Context, status string) (int64, error) {
+	query := `SELECT COUNT(*) FROM webhook_deliveries WHERE status = $1`
+	var count int64
+	err := r.db.GetContext(ctx, &count, query, status)
+	return count, err
+}
+
+// ListFailedDeliveries retrieves failed webhook deliveries matching a replay filter,
+// oldest first
+func (r *PostgresRepository) ListFailedDeliveries(ctx context.Context, filter models.ReplayRequest) ([]models.WebhookDelivery, error) {
+	query := `SELECT * FROM webhook_deliveries WHERE status = $1 AND payload_purged_at IS NULL`
+	args := []interface{}{models.StatusFailed}
+
+	if filter.SubscriptionID != nil {
+		args = append(args, *filter.SubscriptionID)
+		query += fmt.Sprintf(" AND subscription_id = $%d", len(args))
+	}
+	if filter.EventType != "" {
+		args = append(args, filter.EventType)
+		query += fmt.Sprintf(" AND event_type = $%d", len(args))
+	}
+	if filter.Since != nil {
+		args = append(args, *filter.Since)
+		query += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+	if filter.Until != nil {
+		args = append(args, *filter.Until)
+		query += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY created_at ASC LIMIT $%d", len(args))
+
+	var deliveries []models.WebhookDelivery
+	err := r.db.SelectContext(ctx, &deliveries, query, args...)
+	return deliveries, err
+}
+
+// RequeueFailedDelivery moves a failed delivery back to pending with a new retry limit.
+// It reports false if the delivery was not in the FAILED state or its payload was purged.
+func (r *PostgresRepository) RequeueFailedDelivery(ctx context.Context, id uuid.UUID, createdAt time.Time, maxRetries int) (bool, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, next_retry_at = NULL, max_retries = $2
+		WHERE id = $3 AND created_at = $4 AND status = $5 AND payload_purged_at IS NULL
+	`
+	result, err := r.db.ExecContext(ctx, query, models.StatusPending, maxRetries, id, createdAt, models.StatusFailed)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// ClaimWebhookDelivery moves a delivery to PROCESSING for an attempt. Only pending,
+// scheduled and processing deliveries can be claimed, the last ones when the task of
+// an interrupted attempt runs again. It reports false if the delivery is in a final
+// state, e.g. because a stale task fired after it was delivered or cancelled.
+func (r *PostgresRepository) ClaimWebhookDelivery(ctx context.Context, id uuid.UUID, createdAt time.Time) (bool, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1
+		WHERE id = $2 AND created_at = $3 AND status = ANY($4)
+	`
+	result, err := r.db.ExecContext(ctx, query, models.StatusProcessing, id, createdAt, pq.Array(models.ActiveStatuses))
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// ListScheduledDeliveries retrieves scheduled deliveries, the next to fire first
+func (r *PostgresRepository) ListScheduledDeliveries(ctx context.Context, filter models.ScheduledDeliveriesRequest) ([]models.WebhookDelivery, error) {
+	query := `SELECT * FROM webhook_deliveries WHERE status = $1`
+	args := []interface{}{models.StatusScheduled}
+
+	if filter.SubscriptionID != nil {
+		args = append(args, *filter.SubscriptionID)
+		query += fmt.Sprintf(" AND subscription_id = $%d", len(args))
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY next_retry_at ASC LIMIT $%d", len(args))
+
+	var deliveries []models.WebhookDelivery
+	err := r.db.SelectContext(ctx, &deliveries, query, args...)
+	return deliveries, err
+}
+
+// CancelScheduledDelivery cancels a scheduled delivery. It reports false if the
+// delivery was not in the SCHEDULED state.
+func (r *PostgresRepository) CancelScheduledDelivery(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1
+		WHERE id = $2 AND status = $3
+	`
+	result, err := r.db.ExecContext(ctx, query, models.StatusCancelled, id, models.StatusScheduled)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
 // CreateDeliveryAttempt creates a new delivery attempt
 func (r *PostgresRepository) CreateDeliveryAttempt(ctx context.Context, attempt *models.DeliveryAttempt) error {
 	query := `
@@ -154,28 +553,20 @@ func (r *PostgresRepository) CreateDeliveryAttempt(ctx context.Context, attempt
 	return err
 }
 
-// GetDeliveryAttempts retrieves all delivery attempts for a webhook delivery
-func (r *PostgresRepository) GetDeliveryAttempts(ctx context.Context, deliveryID uuid.UUID) ([]models.DeliveryAttempt, error) {
+// GetDeliveryAttempts retrieves all delivery attempts for a webhook delivery. Attempts
+// are never created before their delivery, so only the partitions from the delivery's
+// creation on are read.
+func (r *PostgresRepository) GetDeliveryAttempts(ctx context.Context, deliveryID uuid.UUID, deliveryCreatedAt time.Time) ([]models.DeliveryAttempt, error) {
 	query := `
 		SELECT * FROM delivery_attempts
-		WHERE delivery_id = $1
+		WHERE delivery_id = $1 AND created_at >= $2
 		ORDER BY attempt_number ASC
 	`
 	var attempts []models.DeliveryAttempt
-	err := r.db.SelectContext(ctx, &attempts, query, deliveryID)
+	err := r.db.SelectContext(ctx, &attempts, query, deliveryID, deliveryCreatedAt)
 	return attempts, err
 }
 
-// DeleteOldDeliveryAttempts deletes delivery attempts older than the specified time
-func (r *PostgresRepository) DeleteOldDeliveryAttempts(ctx context.Context, olderThan time.Time) (int64, error) {
-	query := `DELETE FROM delivery_attempts WHERE created_at < $1`
-	result, err := r.db.ExecContext(ctx, query, olderThan)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
-}
-
 // GetRecentDeliveries retrieves recent deliveries for a subscription
 func (r *PostgresRepository) GetRecentDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
 	query := `
@@ -188,3 +579,83 @@ func (r *PostgresRepository) GetRecentDeliveries(ctx context.Context, subscripti
 	err := r.db.SelectContext(ctx, &deliveries, query, subscriptionID, limit)
 	return deliveries, err
 }
+
+// hourlyStatsQuery aggregates deliveries by creation hour and attempts by attempt hour
+// for the window [$1, $2). The %s placeholders take an optional subscription filter.
+const hourlyStatsQuery = `
+	WITH d AS (
+		SELECT subscription_id, COALESCE(event_type, '') AS event_type,
+			date_trunc('hour', created_at) AS bucket_start,
+			COUNT(*) AS deliveries_total,
+			COUNT(*) FILTER (WHERE status = 'DELIVERED') AS deliveries_delivered,
+			COUNT(*) FILTER (WHERE status = 'FAILED') AS deliveries_failed
+		FROM webhook_deliveries
+		WHERE created_at >= $1 AND created_at < $2 %s
+		GROUP BY 1, 2, 3
+	), a AS (
+		SELECT wd.subscription_id, COALESCE(wd.event_type, '') AS event_type,
+			date_trunc('hour', da.created_at) AS bucket_start,
+			COUNT(*) AS attempts_total,
+			COUNT(*) FILTER (WHERE da.status = 'SUCCESS') AS attempts_succeeded,
+			COUNT(*) FILTER (WHERE da.status = 'FAILED') AS attempts_failed
+		FROM delivery_attempts da
+		JOIN webhook_deliveries wd ON wd.id = da.delivery_id
+		WHERE da.created_at >= $1 AND da.created_at < $2 %s
+		GROUP BY 1, 2, 3
+	)
+	SELECT subscription_id, event_type, bucket_start,
+		COALESCE(d.deliveries_total, 0) AS deliveries_total,
+		COALESCE(d.deliveries_delivered, 0) AS deliveries_delivered,
+		COALESCE(d.deliveries_failed, 0) AS deliveries_failed,
+		COALESCE(a.attempts_total, 0) AS attempts_total,
+		COALESCE(a.attempts_succeeded, 0) AS attempts_succeeded,
+		COALESCE(a.attempts_failed, 0) AS attempts_failed
+	FROM d FULL OUTER JOIN a USING (subscription_id, event_type, bucket_start)
+`
+
+// RollupDeliveryStats upserts hourly stats buckets for the window [from, to)
+func (r *PostgresRepository) RollupDeliveryStats(ctx context.Context, from, to time.Time) (int64, error) {
+	query := `
+		INSERT INTO delivery_stats_hourly (subscription_id, event_type, bucket_start,
+			deliveries_total, deliveries_delivered, deliveries_failed,
+			attempts_total, attempts_succeeded, attempts_failed)
+	` + fmt.Sprintf(hourlyStatsQuery, "", "") + `
+		ON CONFLICT (subscription_id, event_type, bucket_start) DO UPDATE
+		SET deliveries_total = EXCLUDED.deliveries_total,
+			deliveries_delivered = EXCLUDED.deliveries_delivered,
+			deliveries_failed = EXCLUDED.deliveries_failed,
+			attempts_total = EXCLUDED.attempts_total,
+			attempts_succeeded = EXCLUDED.attempts_succeeded,
+			attempts_failed = EXCLUDED.attempts_failed,
+			updated_at = NOW()
+	`
+	result, err := r.db.ExecContext(ctx, query, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetDeliveryStats retrieves rolled up hourly stats for a subscription
+func (r *PostgresRepository) GetDeliveryStats(ctx context.Context, subscriptionID uuid.UUID, from, to time.Time) ([]models.DeliveryStats, error) {
+	query := `
+		SELECT subscription_id, event_type, bucket_start,
+			deliveries_total, deliveries_delivered, deliveries_failed,
+			attempts_total, attempts_succeeded, attempts_failed
+		FROM delivery_stats_hourly
+		WHERE subscription_id = $1 AND bucket_start >= $2 AND bucket_start < $3
+		ORDER BY bucket_start ASC, event_type ASC
+	`
+	var stats []models.DeliveryStats
+	err := r.db.SelectContext(ctx, &stats, query, subscriptionID, from, to)
+	return stats, err
+}
+
+// ComputeDeliveryStats computes hourly stats for a subscription directly from the delivery tables
+func (r *PostgresRepository) ComputeDeliveryStats(ctx context.Context, subscriptionID uuid.UUID, from, to time.Time) ([]models.DeliveryStats, error) {
+	query := fmt.Sprintf(hourlyStatsQuery, "AND subscription_id = $3", "AND wd.subscription_id = $3") +
+		`ORDER BY bucket_start ASC, event_type ASC`
+	var stats []models.DeliveryStats
+	err := r.db.SelectContext(ctx, &stats, query, from, to, subscriptionID)
+	return stats, err
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// RetentionFilter selects a batch of deliveries in a terminal state that are past
+// their retention
+type RetentionFilter struct {
+	Status        string
+	CreatedBefore time.Time
+	// SubscriptionID limits the batch to one subscription
+	SubscriptionID *uuid.UUID
+	// ExcludeSubscriptions skips the subscriptions that have a policy of their own
+	ExcludeSubscriptions []uuid.UUID
+	// IDs limits the batch to deliveries already selected, e.g. the ones archived
+	IDs []uuid.UUID
+	// WithPayload skips deliveries whose payload was already removed
+	WithPayload bool
+	// AttemptsBefore skips deliveries with attempts at or after it, which the hourly
+	// stats rollup still counts
+	AttemptsBefore time.Time
+	Limit          int
+}
+
+// where returns the condition selecting the filter's deliveries and its arguments
+func (f RetentionFilter) where() (string, []interface{}) {
+	query := "status = $1 AND created_at < $2"
+	args := []interface{}{f.Status, f.CreatedBefore}
+
+	if f.SubscriptionID != nil {
+		args = append(args, *f.SubscriptionID)
+		query += fmt.Sprintf(" AND subscription_id = $%d", len(args))
+	}
+	if len(f.ExcludeSubscriptions) > 0 {
+		args = append(args, uuidArray(f.ExcludeSubscriptions))
+		query += fmt.Sprintf(" AND subscription_id <> ALL($%d::uuid[])", len(args))
+	}
+	if f.IDs != nil {
+		args = append(args, uuidArray(f.IDs))
+		query += fmt.Sprintf(" AND id = ANY($%d::uuid[])", len(args))
+	}
+	if f.WithPayload {
+		query += " AND payload_purged_at IS NULL"
+	}
+	if !f.AttemptsBefore.IsZero() {
+		args = append(args, f.AttemptsBefore)
+		query += fmt.Sprintf(" AND NOT EXISTS (SELECT 1 FROM delivery_attempts da WHERE da.delivery_id = webhook_deliveries.id AND da.created_at >= $%d)", len(args))
+	}
+	return query, args
+}
+
+// uuidArray returns a query argument for a uuid[] parameter
+func uuidArray(ids []uuid.UUID) interface{} {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	return pq.Array(strs)
+}
+
+// RetentionResult is the outcome of a retention batch
+type RetentionResult struct {
+	Deliveries int64
+	// PayloadRefs are the blob references of the offloaded payloads removed. The blobs
+	// may still be referenced by other deliveries.
+	PayloadRefs []string
+}
+
+// ListRetentionPolicies returns the retention policies of the subscriptions that
+// override the global one
+func (r *PostgresRepository) ListRetentionPolicies(ctx context.Context) (map[uuid.UUID]models.RetentionPolicy, error) {
+	query := `SELECT id, retention_policy FROM subscriptions WHERE retention_policy IS NOT NULL`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := make(map[uuid.UUID]models.RetentionPolicy)
+	for rows.Next() {
+		var id uuid.UUID
+		var policy models.RetentionPolicy
+		if err := rows.Scan(&id, &policy); err != nil {
+			return nil, err
+		}
+		policies[id] = policy
+	}
+	return policies, rows.Err()
+}
+
+// ListRetentionDeliveries returns up to filter.Limit deliveries, oldest first, that
+// the same filter would remove
+func (r *PostgresRepository) ListRetentionDeliveries(ctx context.Context, filter RetentionFilter) ([]models.WebhookDelivery, error) {
+	where, args := filter.where()
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT * FROM webhook_deliveries
+		WHERE %s
+		ORDER BY created_at ASC
+		LIMIT $%d
+	`, where, len(args))
+	var deliveries []models.WebhookDelivery
+	err := r.db.SelectContext(ctx, &deliveries, query, args...)
+	return deliveries, err
+}
+
+// ListDeliveryAttempts retrieves the attempts of several deliveries, ordered by
+// delivery and attempt number
+func (r *PostgresRepository) ListDeliveryAttempts(ctx context.Context, deliveryIDs []uuid.UUID) ([]models.DeliveryAttempt, error) {
+	query := `
+		SELECT * FROM delivery_attempts
+		WHERE delivery_id = ANY($1::uuid[])
+		ORDER BY delivery_id, attempt_number ASC
+	`
+	var attempts []models.DeliveryAttempt
+	err := r.db.SelectContext(ctx, &attempts, query, uuidArray(deliveryIDs))
+	return attempts, err
+}
+
+// DeleteDeliveries deletes up to filter.Limit deliveries, oldest first, along with
+// their attempts. Rows locked by a concurrent update are skipped.
+func (r *PostgresRepository) DeleteDeliveries(ctx context.Context, filter RetentionFilter) (RetentionResult, error) {
+	where, args := filter.where()
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`
+		WITH deleted AS (
+			DELETE FROM webhook_deliveries
+			WHERE status = $1 AND id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE %s
+				ORDER BY created_at ASC
+				LIMIT $%d
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, payload_ref
+		), attempts AS (
+			DELETE FROM delivery_attempts WHERE delivery_id IN (SELECT id FROM deleted)
+		)
+		SELECT payload_ref FROM deleted
+	`, where, len(args))
+	return r.retentionBatch(ctx, query, args)
+}
+
+// StripDeliveryPayloads removes the payloads of up to filter.Limit deliveries, oldest
+// first, keeping the deliveries and their attempts. Rows locked by a concurrent update
+// are skipped.
+func (r *PostgresRepository) StripDeliveryPayloads(ctx context.Context, filter RetentionFilter) (RetentionResult, error) {
+	filter.WithPayload = true
+	where, args := filter.where()
+	args = append(args, filter.Limit, time.Now())
+	query := fmt.Sprintf(`
+		UPDATE webhook_deliveries wd
+		SET payload = NULL, payload_ciphertext = NULL, payload_ref = NULL, payload_purged_at = $%d
+		FROM (
+			SELECT id, payload_ref FROM webhook_deliveries
+			WHERE %s
+			ORDER BY created_at ASC
+			LIMIT $%d
+			FOR UPDATE SKIP LOCKED
+		) old
+		WHERE wd.id = old.id AND wd.status = $1
+		RETURNING old.payload_ref
+	`, len(args), where, len(args)-1)
+	return r.retentionBatch(ctx, query, args)
+}
+
+// retentionBatch runs a retention query returning the payload_ref of each row
+func (r *PostgresRepository) retentionBatch(ctx context.Context, query string, args []interface{}) (RetentionResult, error) {
+	var refs []sql.NullString
+	if err := r.db.SelectContext(ctx, &refs, query, args...); err != nil {
+		return RetentionResult{}, err
+	}
+
+	result := RetentionResult{Deliveries: int64(len(refs))}
+	for _, ref := range refs {
+		if ref.Valid {
+			result.PayloadRefs = append(result.PayloadRefs, ref.String)
+		}
+	}
+	return result, nil
+}
+
+// PayloadRefInUse reports whether any delivery still references an offloaded payload
+func (r *PostgresRepository) PayloadRefInUse(ctx context.Context, ref string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM webhook_deliveries WHERE payload_ref = $1)`
+	var inUse bool
+	err := r.db.GetContext(ctx, &inUse, query, ref)
+	return inUse, err
+}
+
+// LockPayloadRef takes an advisory lock on an offloaded payload and returns the
+// function that releases it. Ingest holds the lock shared from writing the blob until
+// its deliveries are stored, the blob cleanup holds it exclusively while it checks
+// and deletes the blob. The lock is held on a dedicated connection.
+func (r *PostgresRepository) LockPayloadRef(ctx context.Context, ref string, exclusive bool) (func(), error) {
+	conn, err := r.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, unlock := `SELECT pg_advisory_lock_shared(hashtextextended($1, 0))`, `SELECT pg_advisory_unlock_shared(hashtextextended($1, 0))`
+	if exclusive {
+		lock, unlock = `SELECT pg_advisory_lock(hashtextextended($1, 0))`, `SELECT pg_advisory_unlock(hashtextextended($1, 0))`
+	}
+	if _, err := conn.ExecContext(ctx, lock, ref); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to lock payload ref: %w", err)
+	}
+
+	return func() {
+		// Unlock even when the request was cancelled. A connection that failed to
+		// unlock is discarded, so the lock is not kept by the pool.
+		if _, err := conn.ExecContext(context.Background(), unlock, ref); err != nil {
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}, nil
+}
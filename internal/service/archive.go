@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/archive"
+	"github.com/Unic-X/webhook-delivery/internal/blob"
+	"github.com/Unic-X/webhook-delivery/internal/metrics"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// ErrArchiveDisabled is returned when reading the archive with no ARCHIVE_SINK set
+var ErrArchiveDisabled = errors.New("delivery archiving is not configured")
+
+// ErrArchiveNotFound is returned when nothing was archived for a day
+var ErrArchiveNotFound = errors.New("no archived deliveries for the day")
+
+// archiveSealer is implemented by repositories that encrypt data at rest. Payloads
+// are sealed under their subscription's data key before they are archived.
+type archiveSealer interface {
+	SealArchivedPayload(ctx context.Context, delivery *models.WebhookDelivery) ([]byte, error)
+	OpenArchivedPayload(ctx context.Context, deliveryID uuid.UUID, sealed []byte) ([]byte, error)
+}
+
+// SetArchiveSink sets the sink deliveries are archived to before retention removes
+// them. Deliveries are removed without a copy when no sink is set.
+func (s *WebhookService) SetArchiveSink(sink archive.Sink) {
+	s.archive = sink
+}
+
+// archiveDeliveries writes a retention batch with its attempts to the archive sink,
+// one file per day and subscription. Payloads are encrypted when the repository
+// encrypts at rest. A file is named after its first delivery, so
+// archiving a batch again after a failure replaces the files written the first time.
+func (s *WebhookService) archiveDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	ids := make([]uuid.UUID, len(deliveries))
+	for i, delivery := range deliveries {
+		ids[i] = delivery.ID
+	}
+	attempts, err := s.repo.ListDeliveryAttempts(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list delivery attempts: %w", err)
+	}
+	byDelivery := make(map[uuid.UUID][]models.DeliveryAttempt, len(deliveries))
+	for _, attempt := range attempts {
+		byDelivery[attempt.DeliveryID] = append(byDelivery[attempt.DeliveryID], attempt)
+	}
+
+	type partition struct {
+		day            string
+		subscriptionID uuid.UUID
+	}
+	var order []partition
+	files := make(map[partition][]models.ArchivedDelivery)
+	sealer, seal := s.repo.(archiveSealer)
+	for _, delivery := range deliveries {
+		if err := s.inlinePayload(ctx, &delivery); err != nil {
+			return err
+		}
+		record := models.ArchivedDelivery{
+			Delivery: delivery,
+			Attempts: byDelivery[delivery.ID],
+		}
+		if seal && delivery.Payload != nil {
+			sealed, err := sealer.SealArchivedPayload(ctx, &delivery)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt payload of delivery %s: %w", delivery.ID, err)
+			}
+			record.Delivery.Payload = nil
+			record.PayloadCiphertext = sealed
+		}
+
+		p := partition{day: delivery.CreatedAt.UTC().Format(archive.DayLayout), subscriptionID: delivery.SubscriptionID}
+		if _, ok := files[p]; !ok {
+			order = append(order, p)
+		}
+		files[p] = append(files[p], record)
+	}
+
+	for _, p := range order {
+		records := files[p]
+		first := records[0].Delivery
+		key := archive.Key(first.CreatedAt, p.subscriptionID, first.ID.String())
+		if err := archive.Write(ctx, s.archive, key, records); err != nil {
+			return fmt.Errorf("failed to archive deliveries to %s: %w", key, err)
+		}
+	}
+	metrics.DeliveriesArchived.Add(float64(len(deliveries)))
+	return nil
+}
+
+// inlinePayload reads an offloaded payload into the delivery so that its archive
+// record does not depend on a blob retention may delete. A blob that is already
+// gone is logged and its reference kept.
+func (s *WebhookService) inlinePayload(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if !delivery.IsOffloaded() {
+		return nil
+	}
+	payload, err := s.deliveryPayload(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to read payload of delivery %s: %w", delivery.ID, err)
+	}
+	data, err := payload.Bytes(ctx)
+	if errors.Is(err, blob.ErrNotFound) {
+		s.logger.WithField("delivery_id", delivery.ID).Warn("Archiving delivery without its payload, the blob no longer exists")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read payload of delivery %s: %w", delivery.ID, err)
+	}
+	delivery.Payload = data
+	return nil
+}
+
+// RestoreArchive reads back the deliveries archived for a day, in UTC, optionally of
+// one subscription, and calls fn with each of them. Records are passed in file order
+// and reading stops at the first error fn returns.
+func (s *WebhookService) RestoreArchive(ctx context.Context, day time.Time, subscriptionID *uuid.UUID, fn func(models.ArchivedDelivery) error) error {
+	if s.archive == nil {
+		return ErrArchiveDisabled
+	}
+
+	keys, err := s.archive.List(ctx, archive.Prefix(day, subscriptionID))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list archive files")
+		return err
+	}
+	if len(keys) == 0 {
+		return ErrArchiveNotFound
+	}
+
+	for _, key := range keys {
+		if err := s.restoreArchiveFile(ctx, key, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreArchiveFile calls fn with each record of an archive file
+func (s *WebhookService) restoreArchiveFile(ctx context.Context, key string, fn func(models.ArchivedDelivery) error) error {
+	rc, err := s.archive.Open(ctx, key)
+	if err != nil {
+		s.logger.WithError(err).WithField("key", key).Error("Failed to open archive file")
+		return err
+	}
+	defer rc.Close()
+
+	reader, err := archive.NewReader(rc)
+	if err != nil {
+		s.logger.WithError(err).WithField("key", key).Error("Failed to read archive file")
+		return err
+	}
+	defer reader.Close()
+
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			s.logger.WithError(err).WithField("key", key).Error("Failed to read archive file")
+			return err
+		}
+		if err := s.openArchivedPayload(ctx, &record); err != nil {
+			s.logger.WithError(err).WithField("key", key).Error("Failed to decrypt archived delivery")
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// openArchivedPayload decrypts the payload of an archived delivery in place. The
+// payload of a deleted subscription can no longer be decrypted, as its data key was
+// deleted with it, and the delivery is returned without it.
+func (s *WebhookService) openArchivedPayload(ctx context.Context, record *models.ArchivedDelivery) error {
+	if record.PayloadCiphertext == nil {
+		return nil
+	}
+	sealer, ok := s.repo.(archiveSealer)
+	if !ok {
+		return errors.New("archived payload is encrypted but encryption at rest is not configured")
+	}
+
+	payload, err := sealer.OpenArchivedPayload(ctx, record.Delivery.ID, record.PayloadCiphertext)
+	if errors.Is(err, sql.ErrNoRows) {
+		s.logger.WithField("delivery_id", record.Delivery.ID).Warn("Restoring archived delivery without its payload, its data key was deleted")
+		record.PayloadCiphertext = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	record.Delivery.Payload = payload
+	record.PayloadCiphertext = nil
+	return nil
+}
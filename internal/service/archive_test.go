@@ -0,0 +1,93 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Unic-X/webhook-delivery/internal/archive"
+	"github.com/Unic-X/webhook-delivery/internal/envelope"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+func TestArchiveDeliveriesEncryptsPayloads(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	sink, err := archive.NewFilesystem(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := envelope.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subscriptionID := uuid.New()
+	delivery := models.WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		Payload:        json.RawMessage(`{"email":"someone@example.com"}`),
+		CreatedAt:      time.Now().UTC(),
+		Status:         models.StatusDelivered,
+	}
+	repo := &sealingRepository{
+		memoryRepository: &memoryRepository{},
+		keys:             map[uuid.UUID][]byte{subscriptionID: key},
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &WebhookService{repo: repo, archive: sink, logger: logger}
+
+	if err := s.archiveDeliveries(ctx, []models.WebhookDelivery{delivery}); err != nil {
+		t.Fatalf("archiveDeliveries() error = %v", err)
+	}
+
+	// The file on disk must not hold the payload
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*", "*.jsonl.gz"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("found archive files %v, %v, want one", files, err)
+	}
+	file, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(content, []byte("someone@example.com")) {
+		t.Error("archive file holds the payload in plaintext")
+	}
+
+	var records []models.ArchivedDelivery
+	err = s.RestoreArchive(ctx, delivery.CreatedAt, &subscriptionID, func(record models.ArchivedDelivery) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RestoreArchive() error = %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("restored %d deliveries, want 1", len(records))
+	}
+	if !bytes.Equal(records[0].Delivery.Payload, delivery.Payload) {
+		t.Errorf("restored payload %s, want %s", records[0].Delivery.Payload, delivery.Payload)
+	}
+	if records[0].PayloadCiphertext != nil {
+		t.Error("restored record still holds the ciphertext")
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Unic-X/webhook-delivery/internal/metrics"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// defaultAuditLimit caps an audit event listing that does not set a limit
+const defaultAuditLimit = 100
+
+// redactedSecret replaces signing secrets in audit events
+const redactedSecret = "[REDACTED]"
+
+// AuditActor identifies the caller of an audited operation. Actor is the identity
+// passed in by the proxy authenticating API callers, empty when there is none.
+type AuditActor struct {
+	Actor      string
+	RemoteAddr string
+	UserAgent  string
+	RequestID  string
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor returns a context carrying the caller of the operations made with it
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFromContext returns the caller carried by a context, the zero value when
+// there is none
+func AuditActorFromContext(ctx context.Context) AuditActor {
+	actor, _ := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor
+}
+
+// auditedSubscription is a subscription as written to the audit log. Its secrets are
+// redacted, the secret key hint tells a rotated secret apart.
+type auditedSubscription struct {
+	models.Subscription
+	SecretKey         string `json:"secret_key,omitempty"`
+	PreviousSecretKey string `json:"previous_secret_key,omitempty"`
+}
+
+// auditSubscription returns the audited state of a subscription
+func auditSubscription(sub models.Subscription) auditedSubscription {
+	sub.SetSecretKeyHint()
+	audited := auditedSubscription{Subscription: sub}
+	if sub.SecretKey != nil && *sub.SecretKey != "" {
+		audited.SecretKey = redactedSecret
+	}
+	if sub.PreviousSecretKey != nil && *sub.PreviousSecretKey != "" {
+		audited.PreviousSecretKey = redactedSecret
+	}
+	return audited
+}
+
+// auditedDelivery is the audited state of a delivery, its payload is left out
+type auditedDelivery struct {
+	Status string `json:"status"`
+}
+
+// auditJSON encodes a value of an audit event, nil stays empty
+func auditJSON(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// writeAudit writes an audit event for an operation made by the caller in ctx.
+// before and after are the state of the resource around the operation, nil when it
+// did not exist, and metadata holds the operation's parameters.
+func (s *WebhookService) writeAudit(ctx context.Context, action, resourceType, resourceID string, before, after, metadata interface{}) error {
+	actor := AuditActorFromContext(ctx)
+	event := models.AuditEvent{
+		ID:           uuid.New(),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Actor:        actor.Actor,
+		SourceIP:     actor.RemoteAddr,
+		UserAgent:    actor.UserAgent,
+		RequestID:    actor.RequestID,
+		CreatedAt:    time.Now(),
+	}
+
+	var err error
+	if event.Before, err = auditJSON(before); err != nil {
+		return err
+	}
+	if event.After, err = auditJSON(after); err != nil {
+		return err
+	}
+	if event.Metadata, err = auditJSON(metadata); err != nil {
+		return err
+	}
+
+	// The operation has taken effect, record it even if the caller went away
+	if err := s.repo.CreateAuditEvent(context.WithoutCancel(ctx), &event); err != nil {
+		metrics.AuditWriteFailures.Inc()
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"audit":         event.Action,
+			"resource_type": event.ResourceType,
+			"resource_id":   event.ResourceID,
+			"actor":         event.Actor,
+			"remote_addr":   event.SourceIP,
+			"request_id":    event.RequestID,
+			"before":        string(event.Before),
+			"after":         string(event.After),
+			"metadata":      string(event.Metadata),
+		}).Error("Failed to write audit event")
+		return err
+	}
+	return nil
+}
+
+// recordAudit writes an audit event for an operation that already took effect. A
+// failed write does not fail the operation, the event is logged instead.
+func (s *WebhookService) recordAudit(ctx context.Context, action, resourceType, resourceID string, before, after, metadata interface{}) {
+	_ = s.writeAudit(ctx, action, resourceType, resourceID, before, after, metadata)
+}
+
+// ListAuditEvents returns the audit events matching a filter, newest first
+func (s *WebhookService) ListAuditEvents(ctx context.Context, filter models.AuditEventsRequest) ([]models.AuditEvent, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+
+	events, err := s.repo.ListAuditEvents(ctx, filter)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list audit events")
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Unic-X/webhook-delivery/internal/archive"
+	"github.com/Unic-X/webhook-delivery/internal/config"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+func TestCleanupOldLogsArchivesAttempts(t *testing.T) {
+	tests := []struct {
+		name string
+		mode string
+	}{
+		// Delivery partitions are archived and dropped
+		{name: "delete", mode: models.RetentionModeDelete},
+		// Partitions are kept, deliveries are archived and stripped row by row
+		{name: "strip payload", mode: models.RetentionModeStripPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Now().UTC()
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+			repo := newMemoryRepository(today.AddDate(0, 0, -60), today.AddDate(0, 0, 2))
+
+			// A delivery that succeeded on its third attempt, two days after it was
+			// created, so its attempts span three partitions
+			created := today.AddDate(0, 0, -40).Add(time.Hour)
+			delivery := models.WebhookDelivery{
+				ID:             uuid.New(),
+				SubscriptionID: uuid.New(),
+				Payload:        json.RawMessage(`{"order":1}`),
+				CreatedAt:      created,
+				Status:         models.StatusDelivered,
+				RetryCount:     2,
+				MaxRetries:     5,
+			}
+			repo.deliveries = append(repo.deliveries, delivery)
+			for i, status := range []string{"FAILED", "FAILED", "SUCCESS"} {
+				repo.attempts = append(repo.attempts, models.DeliveryAttempt{
+					ID:            uuid.New(),
+					DeliveryID:    delivery.ID,
+					AttemptNumber: i + 1,
+					Status:        status,
+					CreatedAt:     created.AddDate(0, 0, i),
+				})
+			}
+
+			sink, err := archive.NewFilesystem(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+			s := &WebhookService{
+				repo:    repo,
+				archive: sink,
+				logger:  logger,
+				config: &config.Config{
+					LogRetentionHours:          72,
+					DeliveryRetentionDelivered: 30 * 24 * time.Hour,
+					DeliveryRetentionFailed:    30 * 24 * time.Hour,
+					DeliveryRetentionCancelled: 30 * 24 * time.Hour,
+					DeliveryRetentionExpired:   30 * 24 * time.Hour,
+					DeliveryRetentionMode:      tt.mode,
+					RetentionBatchSize:         100,
+				},
+			}
+
+			// Stripping archives the delivery after the attempt partitions were checked,
+			// so the second run drops the ones the first run kept
+			for run := 0; run < 2; run++ {
+				if err := s.CleanupOldLogs(ctx); err != nil {
+					t.Fatalf("CleanupOldLogs() error = %v", err)
+				}
+			}
+			if len(repo.attempts) != 0 {
+				t.Errorf("%d attempts left after cleanup, want 0", len(repo.attempts))
+			}
+
+			var records []models.ArchivedDelivery
+			err = s.RestoreArchive(ctx, created, &delivery.SubscriptionID, func(record models.ArchivedDelivery) error {
+				records = append(records, record)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("RestoreArchive() error = %v", err)
+			}
+			if len(records) != 1 {
+				t.Fatalf("archived %d deliveries, want 1", len(records))
+			}
+			if records[0].Delivery.ID != delivery.ID {
+				t.Errorf("archived delivery %s, want %s", records[0].Delivery.ID, delivery.ID)
+			}
+			if got := len(records[0].Attempts); got != 3 {
+				t.Fatalf("archived %d attempts, want 3", got)
+			}
+			for i, attempt := range records[0].Attempts {
+				if attempt.AttemptNumber != i+1 {
+					t.Errorf("attempt %d has number %d", i, attempt.AttemptNumber)
+				}
+			}
+		})
+	}
+}
+
+// TestCleanupOldLogsKeepsRolledUpAttempts checks that a delivery past its retention
+// is kept while the stats rollup still counts its attempts, here after a replay
+func TestCleanupOldLogsKeepsRolledUpAttempts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	repo := newMemoryRepository(today.AddDate(0, 0, -60), today.AddDate(0, 0, 2))
+
+	created := today.AddDate(0, 0, -40).Add(time.Hour)
+	expired := models.WebhookDelivery{ID: uuid.New(), SubscriptionID: uuid.New(), CreatedAt: created, Status: models.StatusDelivered}
+	replayed := models.WebhookDelivery{ID: uuid.New(), SubscriptionID: uuid.New(), CreatedAt: created.Add(time.Minute), Status: models.StatusDelivered}
+	repo.deliveries = append(repo.deliveries, expired, replayed)
+	repo.attempts = append(repo.attempts,
+		models.DeliveryAttempt{ID: uuid.New(), DeliveryID: expired.ID, AttemptNumber: 1, Status: "SUCCESS", CreatedAt: expired.CreatedAt},
+		models.DeliveryAttempt{ID: uuid.New(), DeliveryID: replayed.ID, AttemptNumber: 1, Status: "SUCCESS", CreatedAt: replayed.CreatedAt},
+		models.DeliveryAttempt{ID: uuid.New(), DeliveryID: replayed.ID, AttemptNumber: 2, Status: "SUCCESS", CreatedAt: now.Add(-2 * time.Hour)},
+	)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &WebhookService{
+		repo:   repo,
+		logger: logger,
+		config: &config.Config{
+			LogRetentionHours:          72,
+			DeliveryRetentionDelivered: 30 * 24 * time.Hour,
+			DeliveryRetentionFailed:    30 * 24 * time.Hour,
+			DeliveryRetentionCancelled: 30 * 24 * time.Hour,
+			DeliveryRetentionExpired:   30 * 24 * time.Hour,
+			DeliveryRetentionMode:      models.RetentionModeDelete,
+			RetentionBatchSize:         100,
+		},
+	}
+
+	if err := s.CleanupOldLogs(ctx); err != nil {
+		t.Fatalf("CleanupOldLogs() error = %v", err)
+	}
+
+	// The partition is kept and the expired delivery removed row by row
+	if len(repo.deliveries) != 1 || repo.deliveries[0].ID != replayed.ID {
+		t.Fatalf("deliveries left = %+v, want only the replayed one", repo.deliveries)
+	}
+	if len(repo.attempts) != 1 || repo.attempts[0].AttemptNumber != 2 {
+		t.Fatalf("attempts left = %+v, want the replay attempt", repo.attempts)
+	}
+}
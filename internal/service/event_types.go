@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/patrickmn/go-cache"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/Unic-X/webhook-delivery/internal/eventschema"
+	"github.com/Unic-X/webhook-delivery/internal/metrics"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// ErrEventTypeExists is returned when registering an event type name that is taken
+var ErrEventTypeExists = errors.New("event type already exists")
+
+// eventTypeNamePattern restricts event type names to characters that are safe in
+// URLs and headers, e.g. order.created
+var eventTypeNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// SchemaValidationError is returned when a payload does not match the latest schema
+// version of its event type
+type SchemaValidationError struct {
+	EventType  string
+	Version    int
+	Violations []models.SchemaViolation
+}
+
+func (e *SchemaValidationError) Error() string {
+	return fmt.Sprintf("payload does not match schema version %d of event type %q", e.Version, e.EventType)
+}
+
+// compiledSchema is a cached schema for an event type. A nil schema marks an event
+// type that is not in the catalog, whose payloads are not validated.
+type compiledSchema struct {
+	version int
+	schema  *jsonschema.Schema
+}
+
+func eventSchemaCacheKey(name string) string {
+	return fmt.Sprintf("event_type_schema:%s", name)
+}
+
+// CreateEventType registers an event type with its first schema version
+func (s *WebhookService) CreateEventType(ctx context.Context, req models.EventTypeRequest) (models.EventType, error) {
+	if !eventTypeNamePattern.MatchString(req.Name) {
+		return models.EventType{}, &ValidationError{Message: "name may only contain letters, digits, '.', '_' and '-'"}
+	}
+
+	now := time.Now()
+	version, err := buildEventTypeVersion(req.Name, 1, req.Schema, req.Examples, now)
+	if err != nil {
+		return models.EventType{}, err
+	}
+
+	eventType := models.EventType{
+		Name:          req.Name,
+		Description:   req.Description,
+		LatestVersion: version.Version,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.repo.CreateEventType(ctx, &eventType, &version); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return models.EventType{}, ErrEventTypeExists
+		}
+		s.logger.WithError(err).WithField("event_type", req.Name).Error("Failed to create event type")
+		return models.EventType{}, err
+	}
+
+	s.cache.Delete(eventSchemaCacheKey(eventType.Name))
+
+	eventType.Versions = []models.EventTypeVersion{version}
+	s.recordAudit(ctx, models.AuditEventTypeCreated, models.AuditResourceEventType, eventType.Name, nil, eventType, nil)
+	return eventType, nil
+}
+
+// AddEventTypeVersion adds a new schema version to an event type. Payloads are
+// validated against the latest version.
+func (s *WebhookService) AddEventTypeVersion(ctx context.Context, name string, req models.EventTypeVersionRequest) (models.EventTypeVersion, error) {
+	if _, err := s.repo.GetEventType(ctx, name); err != nil {
+		s.logger.WithError(err).WithField("event_type", name).Error("Failed to get event type")
+		return models.EventTypeVersion{}, err
+	}
+
+	version, err := buildEventTypeVersion(name, 0, req.Schema, req.Examples, time.Now())
+	if err != nil {
+		return models.EventTypeVersion{}, err
+	}
+
+	if err := s.repo.CreateEventTypeVersion(ctx, &version); err != nil {
+		s.logger.WithError(err).WithField("event_type", name).Error("Failed to create event type version")
+		return models.EventTypeVersion{}, err
+	}
+
+	s.cache.Delete(eventSchemaCacheKey(name))
+	s.recordAudit(ctx, models.AuditEventTypeVersionAdded, models.AuditResourceEventType, name, nil, version, nil)
+
+	return version, nil
+}
+
+// GetEventType retrieves an event type with all of its schema versions
+func (s *WebhookService) GetEventType(ctx context.Context, name string) (models.EventType, error) {
+	eventType, err := s.repo.GetEventType(ctx, name)
+	if err != nil {
+		s.logger.WithError(err).WithField("event_type", name).Error("Failed to get event type")
+		return models.EventType{}, err
+	}
+
+	versions, err := s.repo.ListEventTypeVersions(ctx, name)
+	if err != nil {
+		s.logger.WithError(err).WithField("event_type", name).Error("Failed to list event type versions")
+		return models.EventType{}, err
+	}
+	eventType.Versions = versions
+
+	return *eventType, nil
+}
+
+// ListEventTypes returns the event type catalog
+func (s *WebhookService) ListEventTypes(ctx context.Context) ([]models.EventType, error) {
+	eventTypes, err := s.repo.ListEventTypes(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list event types")
+		return nil, err
+	}
+	return eventTypes, nil
+}
+
+// buildEventTypeVersion compiles a schema and checks the examples against it
+func buildEventTypeVersion(name string, number int, schema json.RawMessage, examples []json.RawMessage, now time.Time) (models.EventTypeVersion, error) {
+	compiled, err := eventschema.Compile(schema)
+	if err != nil {
+		return models.EventTypeVersion{}, &ValidationError{Message: "invalid schema: " + err.Error()}
+	}
+
+	for i, example := range examples {
+		violations, err := eventschema.Validate(compiled, example)
+		if err != nil {
+			return models.EventTypeVersion{}, &ValidationError{Message: fmt.Sprintf("examples[%d]: %s", i, err.Error())}
+		}
+		if len(violations) > 0 {
+			return models.EventTypeVersion{}, &ValidationError{
+				Message: fmt.Sprintf("examples[%d] does not match the schema at %s: %s", i, violations[0].Path, violations[0].Message),
+			}
+		}
+	}
+
+	if examples == nil {
+		examples = []json.RawMessage{}
+	}
+	encodedExamples, err := json.Marshal(examples)
+	if err != nil {
+		return models.EventTypeVersion{}, err
+	}
+
+	return models.EventTypeVersion{
+		EventType: name,
+		Version:   number,
+		Schema:    schema,
+		Examples:  encodedExamples,
+		CreatedAt: now,
+	}, nil
+}
+
+// schemaFor returns the compiled latest schema of an event type, caching it like
+// subscriptions
+func (s *WebhookService) schemaFor(ctx context.Context, eventType string) (*compiledSchema, error) {
+	cacheKey := eventSchemaCacheKey(eventType)
+	if cached, found := s.cache.Get(cacheKey); found {
+		return cached.(*compiledSchema), nil
+	}
+
+	version, err := s.repo.GetLatestEventTypeVersion(ctx, eventType)
+	if errors.Is(err, sql.ErrNoRows) {
+		unknown := &compiledSchema{}
+		s.cache.Set(cacheKey, unknown, cache.DefaultExpiration)
+		return unknown, nil
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("event_type", eventType).Error("Failed to get event type schema")
+		return nil, err
+	}
+
+	compiled, err := eventschema.Compile(version.Schema)
+	if err != nil {
+		s.logger.WithError(err).WithField("event_type", eventType).Error("Failed to compile stored event type schema")
+		return nil, err
+	}
+
+	schema := &compiledSchema{version: version.Version, schema: compiled}
+	s.cache.Set(cacheKey, schema, cache.DefaultExpiration)
+	return schema, nil
+}
+
+// validatePayload validates a payload against the latest schema of its event type.
+// Payloads without an event type or of an event type outside the catalog pass.
+func (s *WebhookService) validatePayload(ctx context.Context, eventType string, payload json.RawMessage) error {
+	if eventType == "" {
+		return nil
+	}
+
+	schema, err := s.schemaFor(ctx, eventType)
+	if err != nil {
+		return err
+	}
+	if schema.schema == nil {
+		return nil
+	}
+
+	violations, err := eventschema.Validate(schema.schema, payload)
+	if err != nil {
+		return &ValidationError{Message: err.Error()}
+	}
+	if len(violations) > 0 {
+		return &SchemaValidationError{
+			EventType:  eventType,
+			Version:    schema.version,
+			Violations: violations,
+		}
+	}
+	return nil
+}
+
+// schemaOutcome returns the ingest metric outcome for a validatePayload error
+func schemaOutcome(err error) string {
+	var schemaErr *SchemaValidationError
+	if errors.As(err, &schemaErr) {
+		return metrics.OutcomeSchemaInvalid
+	}
+	return metrics.OutcomeError
+}
+
+// validateEventTypes checks that every event type a subscription asks for is in the catalog
+func (s *WebhookService) validateEventTypes(ctx context.Context, eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return nil
+	}
+
+	unknown, err := s.repo.FindUnknownEventTypes(ctx, eventTypes)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to look up event types")
+		return err
+	}
+	if len(unknown) > 0 {
+		return &ValidationError{Message: "unknown event types: " + strings.Join(unknown, ", ")}
+	}
+	return nil
+}
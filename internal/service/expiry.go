@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Unic-X/webhook-delivery/internal/metrics"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// expireDelivery moves a delivery that expired before it could be sent to EXPIRED,
+// recording why on an attempt that was not sent
+func (s *WebhookService) expireDelivery(ctx context.Context, delivery *models.WebhookDelivery, expiry time.Time, reason string) error {
+	details := fmt.Sprintf("not sent, the event expired at %s (%s)", expiry.UTC().Format(time.RFC3339), reason)
+	attempt := models.DeliveryAttempt{
+		ID:            uuid.New(),
+		DeliveryID:    delivery.ID,
+		AttemptNumber: delivery.RetryCount + 1,
+		Status:        models.StatusExpired,
+		ErrorDetails:  &details,
+		CreatedAt:     attemptTime(delivery),
+	}
+	if err := s.repo.CreateDeliveryAttempt(ctx, &attempt); err != nil {
+		s.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to create delivery attempt record")
+	}
+
+	delivery.Status = models.StatusExpired
+	delivery.NextRetryAt = nil
+	if err := s.repo.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		s.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to update webhook delivery status to expired")
+		return err
+	}
+	metrics.DeliveriesExpired.WithLabelValues(reason).Inc()
+	s.publishDeliveryEvent(ctx, models.EventDeliveryExpired, delivery, &attempt)
+
+	s.logger.WithFields(logrus.Fields{
+		"delivery_id": delivery.ID,
+		"expired_at":  expiry,
+		"reason":      reason,
+	}).Info("Webhook delivery expired before it could be sent")
+
+	return nil
+}
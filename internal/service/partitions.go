@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Unic-X/webhook-delivery/internal/metrics"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/internal/repository"
+)
+
+// partitionedTables are the tables range partitioned on created_at
+var partitionedTables = []string{repository.DeliveriesTable, repository.AttemptsTable}
+
+// CreatePartitions creates the partitions of webhook_deliveries and delivery_attempts
+// up to PARTITION_PREMAKE_DAYS ahead, each covering PARTITION_DAYS days from the end of
+// the last one. Rows created while no partition covered them are in the default
+// partition and are moved into the new ones.
+func (s *WebhookService) CreatePartitions(ctx context.Context) error {
+	days := s.config.PartitionDays
+	if days <= 0 {
+		days = 1
+	}
+	now := time.Now().UTC()
+	horizon := now.AddDate(0, 0, s.config.PartitionPremakeDays)
+
+	for _, table := range partitionedTables {
+		partitions, err := s.repo.ListPartitions(ctx, table)
+		if err != nil {
+			s.logger.WithError(err).WithField("table", table).Error("Failed to list partitions")
+			return err
+		}
+
+		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		if n := len(partitions); n > 0 && partitions[n-1].End != nil {
+			start = partitions[n-1].End.UTC()
+		}
+		for ; start.Before(horizon); start = start.AddDate(0, 0, days) {
+			name, err := s.repo.CreatePartition(ctx, table, start, start.AddDate(0, 0, days))
+			if err != nil {
+				s.logger.WithError(err).WithFields(logrus.Fields{
+					"table": table,
+					"start": start,
+				}).Error("Failed to create partition")
+				return err
+			}
+			s.logger.WithField("partition", name).Info("Partition created")
+		}
+	}
+	return nil
+}
+
+// DropExpiredPartitions drops the partitions of webhook_deliveries whose deliveries are
+// all past the retention of their status, then the partitions of delivery_attempts
+// older than the log retention period. Dropping a partition replaces deleting its rows
+// one at a time. When an archive sink is set, a partition of delivery_attempts is kept
+// until the deliveries it holds attempts of are archived.
+func (s *WebhookService) DropExpiredPartitions(ctx context.Context) error {
+	now := time.Now()
+
+	// Delivery partitions go first, so their deliveries are archived with all of
+	// their attempts
+	if err := s.dropExpiredDeliveryPartitions(ctx, now); err != nil {
+		return err
+	}
+
+	attempts, err := s.repo.ListPartitions(ctx, repository.AttemptsTable)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list delivery attempt partitions")
+		return err
+	}
+	cutoff := now.Add(-time.Duration(s.config.LogRetentionHours) * time.Hour)
+	for _, partition := range attempts {
+		if partition.End == nil || partition.End.After(cutoff) {
+			continue
+		}
+		if s.archive != nil {
+			inUse, err := s.repo.AttemptPartitionInUse(ctx, partition.Name)
+			if err != nil {
+				s.logger.WithError(err).WithField("partition", partition.Name).Error("Failed to check delivery attempt partition")
+				return err
+			}
+			if inUse {
+				s.logger.WithField("partition", partition.Name).Info("Keeping delivery attempt partition until its deliveries are archived")
+				continue
+			}
+		}
+		if err := s.repo.DropPartition(ctx, partition.Name); err != nil {
+			s.logger.WithError(err).WithField("partition", partition.Name).Error("Failed to drop partition")
+			return err
+		}
+		s.logger.WithField("partition", partition.Name).Info("Delivery attempt partition dropped")
+	}
+	return nil
+}
+
+// dropExpiredDeliveryPartitions drops the partitions of webhook_deliveries that end
+// before the delivery partition cutoff
+func (s *WebhookService) dropExpiredDeliveryPartitions(ctx context.Context, now time.Time) error {
+	cutoff, ok, err := s.deliveryPartitionCutoff(ctx, now)
+	if err != nil || !ok {
+		return err
+	}
+	deliveries, err := s.repo.ListPartitions(ctx, repository.DeliveriesTable)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list delivery partitions")
+		return err
+	}
+	for _, partition := range deliveries {
+		if partition.End == nil || partition.End.After(cutoff) {
+			continue
+		}
+		if err := s.dropDeliveryPartition(ctx, partition.Name, s.statsCutoff(now)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliveryPartitionCutoff returns the time partitions of webhook_deliveries must end
+// by to be dropped: the longest retention of any status under the global policy and
+// every override, and no later than the stats rollup allows. ok is false when some
+// deliveries are kept forever or only stripped of their payload, partitions are then
+// left to the row by row retention.
+func (s *WebhookService) deliveryPartitionCutoff(ctx context.Context, now time.Time) (time.Time, bool, error) {
+	global := s.globalRetentionPolicy()
+	overrides, err := s.repo.ListRetentionPolicies(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list subscription retention policies")
+		return time.Time{}, false, err
+	}
+
+	policies := []models.RetentionPolicy{global}
+	for _, override := range overrides {
+		policies = append(policies, global.Override(override))
+	}
+
+	var longest time.Duration
+	for _, policy := range policies {
+		if policy.Mode != models.RetentionModeDelete {
+			return time.Time{}, false, nil
+		}
+		for _, status := range models.RetentionStatuses {
+			retention := policy.Retention(status)
+			if retention <= 0 {
+				return time.Time{}, false, nil
+			}
+			if retention > longest {
+				longest = retention
+			}
+		}
+	}
+
+	cutoff := now.Add(-longest)
+	if statsCutoff := s.statsCutoff(now); cutoff.After(statsCutoff) {
+		cutoff = statsCutoff
+	}
+	return cutoff, true, nil
+}
+
+// dropDeliveryPartition archives a partition of webhook_deliveries when an archive
+// sink is set, then drops it along with the attempts of its deliveries, which retries
+// may have recorded in later attempt partitions. A partition that still holds
+// deliveries in progress, e.g. scheduled far ahead, or deliveries with attempts since
+// statsCutoff that the stats rollup still counts, is kept and its deliveries are
+// removed row by row.
+func (s *WebhookService) dropDeliveryPartition(ctx context.Context, name string, statsCutoff time.Time) error {
+	logger := s.logger.WithField("partition", name)
+
+	counts, err := s.repo.CountPartitionDeliveries(ctx, name)
+	if err != nil {
+		logger.WithError(err).Error("Failed to count partition deliveries")
+		return err
+	}
+	for _, status := range models.ActiveStatuses {
+		if counts[status] > 0 {
+			logger.WithField("status", status).Warn("Keeping delivery partition with deliveries in progress")
+			return nil
+		}
+	}
+
+	if s.archive != nil {
+		if err := s.archivePartition(ctx, name); err != nil {
+			logger.WithError(err).Error("Failed to archive partition")
+			return err
+		}
+	}
+	refs, err := s.repo.ListPartitionPayloadRefs(ctx, name)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list partition payload blobs")
+		return err
+	}
+
+	dropped, err := s.repo.DropDeliveryPartition(ctx, name, models.ActiveStatuses, statsCutoff)
+	if err != nil {
+		logger.WithError(err).Error("Failed to drop partition")
+		return err
+	}
+	if !dropped {
+		logger.Warn("Keeping delivery partition with deliveries in progress or recent attempts")
+		return nil
+	}
+
+	var total int64
+	for status, count := range counts {
+		metrics.DeliveriesPurged.WithLabelValues(status, models.RetentionModeDelete).Add(float64(count))
+		total += count
+	}
+	s.deleteUnreferencedBlobs(ctx, refs)
+	logger.WithField("delivery_count", total).Info("Delivery partition dropped")
+	return nil
+}
+
+// archivePartition writes every delivery of a partition to the archive sink, one
+// batch at a time
+func (s *WebhookService) archivePartition(ctx context.Context, name string) error {
+	limit := s.retentionBatchSize()
+	var cursor repository.PartitionCursor
+	for {
+		deliveries, err := s.repo.ListPartitionDeliveries(ctx, name, cursor, limit)
+		if err != nil {
+			return err
+		}
+		if len(deliveries) > 0 {
+			if err := s.archiveDeliveries(ctx, deliveries); err != nil {
+				return err
+			}
+		}
+		if len(deliveries) < limit {
+			return nil
+		}
+		last := deliveries[len(deliveries)-1]
+		cursor = repository.PartitionCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
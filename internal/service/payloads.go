@@ -0,0 +1,179 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/blob"
+	"github.com/Unic-X/webhook-delivery/internal/destination"
+	"github.com/Unic-X/webhook-delivery/internal/metrics"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// ErrPayloadTooLarge is returned when a payload exceeds MAX_INGEST_BYTES
+var ErrPayloadTooLarge = errors.New("payload exceeds the maximum ingest size")
+
+// storedPayload is a payload as it is kept on delivery rows, either inline or as a
+// reference to the blob store
+type storedPayload struct {
+	inline json.RawMessage
+	ref    *string
+	hash   *string
+	size   *int64
+}
+
+// apply sets the payload columns of a delivery
+func (p storedPayload) apply(delivery *models.WebhookDelivery) {
+	delivery.Payload = p.inline
+	delivery.PayloadRef = p.ref
+	delivery.PayloadSHA256 = p.hash
+	delivery.PayloadSize = p.size
+}
+
+// checkPayloadSize rejects payloads above the maximum ingest size
+func (s *WebhookService) checkPayloadSize(payload json.RawMessage) error {
+	if s.config.MaxIngestBytes > 0 && len(payload) > s.config.MaxIngestBytes {
+		metrics.WebhooksIngested.WithLabelValues(metrics.OutcomePayloadTooLarge).Inc()
+		return ErrPayloadTooLarge
+	}
+	return nil
+}
+
+// blobSealer is implemented by repositories that encrypt data at rest. Offloaded
+// payloads are sealed under the data key of the subscription they are delivered to.
+type blobSealer interface {
+	SealBlob(ctx context.Context, subscriptionID uuid.UUID, data []byte) ([]byte, error)
+	OpenBlob(ctx context.Context, subscriptionID uuid.UUID, sealed []byte) ([]byte, error)
+}
+
+// storePayload stores a payload for the deliveries of a set of subscriptions and
+// returns how each of them keeps it. A payload above the offload threshold goes to the
+// blob store. Blobs are content-addressed, so the deliveries of a published event
+// share one, unless payloads are encrypted at rest. Each subscription then gets a blob
+// sealed under its own data key, addressed by the hash of the ciphertext.
+// The returned function must be called once the deliveries are stored, until then the
+// blobs are locked against the cleanup of unreferenced blobs.
+func (s *WebhookService) storePayload(ctx context.Context, subscriptionIDs []uuid.UUID, payload json.RawMessage) ([]storedPayload, func(), error) {
+	stored := make([]storedPayload, len(subscriptionIDs))
+	// Nothing is offloaded when there is no delivery to reference it
+	if s.blobs == nil || len(payload) <= s.config.PayloadOffloadBytes || len(subscriptionIDs) == 0 {
+		for i := range stored {
+			stored[i] = storedPayload{inline: payload}
+		}
+		return stored, func() {}, nil
+	}
+
+	sealer, ok := s.repo.(blobSealer)
+	if !ok {
+		hash := blob.Hash(payload)
+		shared, release, err := s.putPayloadBlob(ctx, blob.ContentKey(hash), hash, payload, len(payload))
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range stored {
+			stored[i] = shared
+		}
+		return stored, release, nil
+	}
+
+	var releases []func()
+	release := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+	for i, subscriptionID := range subscriptionIDs {
+		sealed, err := sealer.SealBlob(ctx, subscriptionID, payload)
+		if err != nil {
+			release()
+			s.logger.WithError(err).WithField("subscription_id", subscriptionID).Error("Failed to encrypt offloaded payload")
+			metrics.WebhooksIngested.WithLabelValues(metrics.OutcomeError).Inc()
+			return nil, nil, err
+		}
+		hash := blob.Hash(sealed)
+		p, unlock, err := s.putPayloadBlob(ctx, blob.SealedContentKey(hash), hash, sealed, len(payload))
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		releases = append(releases, unlock)
+		stored[i] = p
+	}
+	return stored, release, nil
+}
+
+// putPayloadBlob writes a payload blob under key, holding the lock on it that the
+// returned function releases. hash is the hash of data and size that of the payload.
+func (s *WebhookService) putPayloadBlob(ctx context.Context, key, hash string, data []byte, size int) (storedPayload, func(), error) {
+	release, err := s.repo.LockPayloadRef(ctx, key, false)
+	if err != nil {
+		s.logger.WithError(err).WithField("payload_ref", key).Error("Failed to lock payload blob")
+		metrics.WebhooksIngested.WithLabelValues(metrics.OutcomeError).Inc()
+		return storedPayload{}, nil, err
+	}
+
+	ref, err := s.blobs.Put(ctx, key, bytes.NewReader(data))
+	if err != nil {
+		release()
+		s.logger.WithError(err).WithField("payload_size", size).Error("Failed to offload payload to blob store")
+		metrics.WebhooksIngested.WithLabelValues(metrics.OutcomeError).Inc()
+		return storedPayload{}, nil, err
+	}
+
+	payloadSize := int64(size)
+	return storedPayload{ref: &ref, hash: &hash, size: &payloadSize}, release, nil
+}
+
+// deliveryPayload returns the payload of a delivery for its destination. Offloaded
+// payloads are streamed from the blob store and checked against their hash. Sealed
+// blobs are read whole, checked and decrypted before they are sent.
+func (s *WebhookService) deliveryPayload(delivery *models.WebhookDelivery) (destination.Payload, error) {
+	if !delivery.IsOffloaded() {
+		return destination.InlinePayload(delivery.Payload), nil
+	}
+	if s.blobs == nil {
+		return destination.Payload{}, errors.New("payload is offloaded but no blob store is configured")
+	}
+	if delivery.PayloadSHA256 == nil || delivery.PayloadSize == nil {
+		return destination.Payload{}, errors.New("offloaded payload has no hash or size")
+	}
+
+	ref, hash, subscriptionID := *delivery.PayloadRef, *delivery.PayloadSHA256, delivery.SubscriptionID
+	if !blob.IsSealed(ref) {
+		return destination.StreamedPayload(*delivery.PayloadSize, func(ctx context.Context) (io.ReadCloser, error) {
+			rc, err := s.blobs.Open(ctx, ref)
+			if err != nil {
+				return nil, err
+			}
+			return blob.VerifyingReader(rc, hash), nil
+		}), nil
+	}
+
+	sealer, ok := s.repo.(blobSealer)
+	if !ok {
+		return destination.Payload{}, errors.New("payload blob is encrypted but encryption at rest is not configured")
+	}
+	return destination.StreamedPayload(*delivery.PayloadSize, func(ctx context.Context) (io.ReadCloser, error) {
+		rc, err := s.blobs.Open(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		verified := blob.VerifyingReader(rc, hash)
+		defer verified.Close()
+
+		sealed, err := io.ReadAll(verified)
+		if err != nil {
+			return nil, err
+		}
+		data, err := sealer.OpenBlob(ctx, subscriptionID, sealed)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}), nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Unic-X/webhook-delivery/internal/blob"
+	"github.com/Unic-X/webhook-delivery/internal/config"
+	"github.com/Unic-X/webhook-delivery/internal/envelope"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// sealingRepository seals payload blobs like a repository that encrypts at rest,
+// with a data key per subscription
+type sealingRepository struct {
+	*memoryRepository
+	keys map[uuid.UUID][]byte
+}
+
+func (r *sealingRepository) SealBlob(_ context.Context, subscriptionID uuid.UUID, data []byte) ([]byte, error) {
+	return envelope.Seal(subscriptionID, r.keys[subscriptionID], data, subscriptionID[:])
+}
+
+func (r *sealingRepository) OpenBlob(_ context.Context, subscriptionID uuid.UUID, sealed []byte) ([]byte, error) {
+	return envelope.Open(r.keys[subscriptionID], sealed, subscriptionID[:])
+}
+
+func (r *sealingRepository) SealArchivedPayload(_ context.Context, delivery *models.WebhookDelivery) ([]byte, error) {
+	return envelope.Seal(delivery.SubscriptionID, r.keys[delivery.SubscriptionID], delivery.Payload, delivery.ID[:])
+}
+
+func (r *sealingRepository) OpenArchivedPayload(_ context.Context, deliveryID uuid.UUID, sealed []byte) ([]byte, error) {
+	keyID, err := envelope.KeyID(sealed)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.Open(r.keys[keyID], sealed, deliveryID[:])
+}
+
+// readBlob returns the content of a blob as it is stored
+func readBlob(t *testing.T, store blob.Store, ref string) []byte {
+	t.Helper()
+	rc, err := store.Open(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("Open(%s) error = %v", ref, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestStorePayloadOffload(t *testing.T) {
+	payload := json.RawMessage(`{"email":"someone@example.com","note":"` + string(bytes.Repeat([]byte("x"), 64)) + `"}`)
+	subs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	tests := []struct {
+		name   string
+		sealed bool
+	}{
+		{name: "plaintext blobs are shared", sealed: false},
+		{name: "sealed blobs per subscription", sealed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, err := blob.NewFilesystem(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+			s := &WebhookService{
+				repo:   &memoryRepository{},
+				blobs:  store,
+				logger: logger,
+				config: &config.Config{PayloadOffloadBytes: 16},
+			}
+			if tt.sealed {
+				keys := make(map[uuid.UUID][]byte)
+				for _, id := range subs {
+					if keys[id], err = envelope.GenerateKey(); err != nil {
+						t.Fatal(err)
+					}
+				}
+				s.repo = &sealingRepository{memoryRepository: &memoryRepository{}, keys: keys}
+			}
+
+			stored, release, err := s.storePayload(ctx, subs, payload)
+			if err != nil {
+				t.Fatalf("storePayload() error = %v", err)
+			}
+			release()
+			if len(stored) != len(subs) {
+				t.Fatalf("storePayload() returned %d payloads, want %d", len(stored), len(subs))
+			}
+
+			refs := make(map[string]bool)
+			for i, p := range stored {
+				if p.ref == nil {
+					t.Fatalf("payload %d was not offloaded", i)
+				}
+				refs[*p.ref] = true
+				if blob.IsSealed(*p.ref) != tt.sealed {
+					t.Errorf("IsSealed(%s) = %v, want %v", *p.ref, !tt.sealed, tt.sealed)
+				}
+				if *p.size != int64(len(payload)) {
+					t.Errorf("size = %d, want %d", *p.size, len(payload))
+				}
+
+				content := readBlob(t, store, *p.ref)
+				if got := blob.Hash(content); got != *p.hash {
+					t.Errorf("hash = %s, want the hash of the stored blob %s", *p.hash, got)
+				}
+				if tt.sealed && bytes.Contains(content, []byte("someone@example.com")) {
+					t.Errorf("blob %s holds the payload in plaintext", *p.ref)
+				}
+
+				delivery := models.WebhookDelivery{ID: uuid.New(), SubscriptionID: subs[i]}
+				p.apply(&delivery)
+				body, err := s.deliveryPayload(&delivery)
+				if err != nil {
+					t.Fatalf("deliveryPayload() error = %v", err)
+				}
+				data, err := body.Bytes(ctx)
+				if err != nil {
+					t.Fatalf("Bytes() error = %v", err)
+				}
+				if !bytes.Equal(data, payload) {
+					t.Errorf("delivered %s, want %s", data, payload)
+				}
+			}
+
+			want := 1
+			if tt.sealed {
+				want = len(subs)
+			}
+			if len(refs) != want {
+				t.Errorf("stored %d blobs, want %d", len(refs), want)
+			}
+		})
+	}
+}
+
+func TestDeliveryPayloadRejectsSealedBlobOfOtherSubscription(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewFilesystem(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, other := uuid.New(), uuid.New()
+	key, err := envelope.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both subscriptions share a key here, so only the binding to the subscription
+	// tells the blobs apart
+	repo := &sealingRepository{memoryRepository: &memoryRepository{}, keys: map[uuid.UUID][]byte{owner: key, other: key}}
+	s := &WebhookService{repo: repo, blobs: store, logger: logrus.New(), config: &config.Config{PayloadOffloadBytes: 1}}
+
+	stored, release, err := s.storePayload(ctx, []uuid.UUID{owner}, json.RawMessage(`{"secret":true}`))
+	if err != nil {
+		t.Fatalf("storePayload() error = %v", err)
+	}
+	release()
+
+	delivery := models.WebhookDelivery{ID: uuid.New(), SubscriptionID: other}
+	stored[0].apply(&delivery)
+	body, err := s.deliveryPayload(&delivery)
+	if err != nil {
+		t.Fatalf("deliveryPayload() error = %v", err)
+	}
+	if _, err := body.Bytes(ctx); err == nil {
+		t.Error("Bytes() read the blob of another subscription")
+	}
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/internal/repository"
+)
+
+// memoryRepository keeps deliveries, attempts and their partitions in memory. It
+// implements the repository methods the retention and partition maintenance use,
+// the others panic.
+type memoryRepository struct {
+	repository.Repository
+
+	mu         sync.Mutex
+	deliveries []models.WebhookDelivery
+	attempts   []models.DeliveryAttempt
+	partitions map[string][]repository.Partition
+}
+
+// newMemoryRepository returns a memoryRepository with daily partitions of both tables
+// covering [from, to)
+func newMemoryRepository(from, to time.Time) *memoryRepository {
+	r := &memoryRepository{partitions: make(map[string][]repository.Partition)}
+	for _, table := range partitionedTables {
+		for start := from; start.Before(to); start = start.AddDate(0, 0, 1) {
+			start, end := start, start.AddDate(0, 0, 1)
+			r.partitions[table] = append(r.partitions[table], repository.Partition{
+				Name:  repository.PartitionName(table, start),
+				Start: &start,
+				End:   &end,
+			})
+		}
+	}
+	return r
+}
+
+// partition returns a partition by name
+func (r *memoryRepository) partition(name string) (string, repository.Partition, bool) {
+	for table, partitions := range r.partitions {
+		for _, p := range partitions {
+			if p.Name == name {
+				return table, p, true
+			}
+		}
+	}
+	return "", repository.Partition{}, false
+}
+
+// inPartition reports whether a row created at t belongs to a partition
+func inPartition(p repository.Partition, t time.Time) bool {
+	return !t.Before(*p.Start) && t.Before(*p.End)
+}
+
+// delivery returns the index of a delivery, -1 when it does not exist
+func (r *memoryRepository) delivery(id uuid.UUID) int {
+	for i, delivery := range r.deliveries {
+		if delivery.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// matches reports whether a delivery is selected by a retention filter
+func matches(filter repository.RetentionFilter, delivery models.WebhookDelivery) bool {
+	if delivery.Status != filter.Status || !delivery.CreatedAt.Before(filter.CreatedBefore) {
+		return false
+	}
+	if filter.SubscriptionID != nil && delivery.SubscriptionID != *filter.SubscriptionID {
+		return false
+	}
+	for _, id := range filter.ExcludeSubscriptions {
+		if delivery.SubscriptionID == id {
+			return false
+		}
+	}
+	if filter.IDs != nil {
+		found := false
+		for _, id := range filter.IDs {
+			found = found || delivery.ID == id
+		}
+		if !found {
+			return false
+		}
+	}
+	return !filter.WithPayload || delivery.PayloadPurgedAt == nil
+}
+
+// selectDeliveries returns the indexes of up to filter.Limit deliveries a retention
+// filter selects, oldest first
+func (r *memoryRepository) selectDeliveries(filter repository.RetentionFilter) []int {
+	var selected []int
+	for i, delivery := range r.deliveries {
+		if matches(filter, delivery) && (filter.AttemptsBefore.IsZero() || !r.attemptedSince(delivery, filter.AttemptsBefore)) {
+			selected = append(selected, i)
+		}
+	}
+	sort.Slice(selected, func(a, b int) bool {
+		return r.deliveries[selected[a]].CreatedAt.Before(r.deliveries[selected[b]].CreatedAt)
+	})
+	if len(selected) > filter.Limit {
+		selected = selected[:filter.Limit]
+	}
+	return selected
+}
+
+// attemptedSince reports whether a delivery has attempts at or after t
+func (r *memoryRepository) attemptedSince(delivery models.WebhookDelivery, t time.Time) bool {
+	for _, attempt := range r.attempts {
+		if attempt.DeliveryID == delivery.ID && !attempt.CreatedAt.Before(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteAttempts deletes the attempts for which drop returns true
+func (r *memoryRepository) deleteAttempts(drop func(models.DeliveryAttempt) bool) {
+	kept := r.attempts[:0]
+	for _, attempt := range r.attempts {
+		if !drop(attempt) {
+			kept = append(kept, attempt)
+		}
+	}
+	r.attempts = kept
+}
+
+func (r *memoryRepository) RollupDeliveryStats(context.Context, time.Time, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryRepository) ListRetentionPolicies(context.Context) (map[uuid.UUID]models.RetentionPolicy, error) {
+	return map[uuid.UUID]models.RetentionPolicy{}, nil
+}
+
+func (r *memoryRepository) ListRetentionDeliveries(_ context.Context, filter repository.RetentionFilter) ([]models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deliveries []models.WebhookDelivery
+	for _, i := range r.selectDeliveries(filter) {
+		deliveries = append(deliveries, r.deliveries[i])
+	}
+	return deliveries, nil
+}
+
+func (r *memoryRepository) ListDeliveryAttempts(_ context.Context, deliveryIDs []uuid.UUID) ([]models.DeliveryAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var attempts []models.DeliveryAttempt
+	for _, attempt := range r.attempts {
+		for _, id := range deliveryIDs {
+			if attempt.DeliveryID == id {
+				attempts = append(attempts, attempt)
+			}
+		}
+	}
+	return attempts, nil
+}
+
+func (r *memoryRepository) DeleteDeliveries(_ context.Context, filter repository.RetentionFilter) (repository.RetentionResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := make(map[uuid.UUID]bool)
+	for _, i := range r.selectDeliveries(filter) {
+		deleted[r.deliveries[i].ID] = true
+	}
+	kept := r.deliveries[:0]
+	for _, delivery := range r.deliveries {
+		if !deleted[delivery.ID] {
+			kept = append(kept, delivery)
+		}
+	}
+	r.deliveries = kept
+	r.deleteAttempts(func(attempt models.DeliveryAttempt) bool { return deleted[attempt.DeliveryID] })
+	return repository.RetentionResult{Deliveries: int64(len(deleted))}, nil
+}
+
+func (r *memoryRepository) StripDeliveryPayloads(_ context.Context, filter repository.RetentionFilter) (repository.RetentionResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filter.WithPayload = true
+	now := time.Now()
+	selected := r.selectDeliveries(filter)
+	for _, i := range selected {
+		r.deliveries[i].Payload = nil
+		r.deliveries[i].PayloadPurgedAt = &now
+	}
+	return repository.RetentionResult{Deliveries: int64(len(selected))}, nil
+}
+
+func (r *memoryRepository) ListPartitions(_ context.Context, table string) ([]repository.Partition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]repository.Partition(nil), r.partitions[table]...), nil
+}
+
+// removePartition removes a partition from the list of its table
+func (r *memoryRepository) removePartition(table, name string) {
+	kept := r.partitions[table][:0]
+	for _, p := range r.partitions[table] {
+		if p.Name != name {
+			kept = append(kept, p)
+		}
+	}
+	r.partitions[table] = kept
+}
+
+func (r *memoryRepository) DropPartition(_ context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	table, p, ok := r.partition(name)
+	if !ok {
+		return nil
+	}
+	if table == repository.AttemptsTable {
+		r.deleteAttempts(func(attempt models.DeliveryAttempt) bool { return inPartition(p, attempt.CreatedAt) })
+	}
+	r.removePartition(table, name)
+	return nil
+}
+
+func (r *memoryRepository) AttemptPartitionInUse(_ context.Context, name string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, p, _ := r.partition(name)
+	for _, attempt := range r.attempts {
+		if !inPartition(p, attempt.CreatedAt) {
+			continue
+		}
+		if i := r.delivery(attempt.DeliveryID); i >= 0 && r.deliveries[i].PayloadPurgedAt == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryRepository) DropDeliveryPartition(_ context.Context, name string, unlessStatuses []string, attemptsSince time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, p, _ := r.partition(name)
+	for _, delivery := range r.deliveries {
+		if inPartition(p, delivery.CreatedAt) && r.attemptedSince(delivery, attemptsSince) {
+			return false, nil
+		}
+		for _, status := range unlessStatuses {
+			if inPartition(p, delivery.CreatedAt) && delivery.Status == status {
+				return false, nil
+			}
+		}
+	}
+	dropped := make(map[uuid.UUID]bool)
+	kept := r.deliveries[:0]
+	for _, delivery := range r.deliveries {
+		if inPartition(p, delivery.CreatedAt) {
+			dropped[delivery.ID] = true
+		} else {
+			kept = append(kept, delivery)
+		}
+	}
+	r.deliveries = kept
+	r.deleteAttempts(func(attempt models.DeliveryAttempt) bool { return dropped[attempt.DeliveryID] })
+	r.removePartition(repository.DeliveriesTable, name)
+	return true, nil
+}
+
+func (r *memoryRepository) CountPartitionDeliveries(_ context.Context, name string) (map[string]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, p, _ := r.partition(name)
+	counts := make(map[string]int64)
+	for _, delivery := range r.deliveries {
+		if inPartition(p, delivery.CreatedAt) {
+			counts[delivery.Status]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *memoryRepository) ListPartitionDeliveries(_ context.Context, name string, after repository.PartitionCursor, limit int) ([]models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, p, _ := r.partition(name)
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if !inPartition(p, delivery.CreatedAt) {
+			continue
+		}
+		if delivery.CreatedAt.Before(after.CreatedAt) || (delivery.CreatedAt.Equal(after.CreatedAt) &&
+			strings.Compare(delivery.ID.String(), after.ID.String()) <= 0) {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	sort.Slice(deliveries, func(a, b int) bool {
+		if !deliveries[a].CreatedAt.Equal(deliveries[b].CreatedAt) {
+			return deliveries[a].CreatedAt.Before(deliveries[b].CreatedAt)
+		}
+		return deliveries[a].ID.String() < deliveries[b].ID.String()
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (r *memoryRepository) ListPartitionPayloadRefs(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
+func (r *memoryRepository) LockPayloadRef(context.Context, string, bool) (func(), error) {
+	return func() {}, nil
+}
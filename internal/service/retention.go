@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Unic-X/webhook-delivery/internal/metrics"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/internal/repository"
+)
+
+// defaultRetentionBatchSize is used when RETENTION_BATCH_SIZE is not positive
+const defaultRetentionBatchSize = 1000
+
+// retentionBatchSize returns how many deliveries retention removes at once
+func (s *WebhookService) retentionBatchSize() int {
+	if s.config.RetentionBatchSize <= 0 {
+		return defaultRetentionBatchSize
+	}
+	return s.config.RetentionBatchSize
+}
+
+// globalRetentionPolicy returns the retention policy of the DELIVERY_RETENTION_*
+// settings
+func (s *WebhookService) globalRetentionPolicy() models.RetentionPolicy {
+	return models.RetentionPolicy{
+		DeliveredSeconds: int(s.config.DeliveryRetentionDelivered / time.Second),
+		FailedSeconds:    int(s.config.DeliveryRetentionFailed / time.Second),
+		CancelledSeconds: int(s.config.DeliveryRetentionCancelled / time.Second),
+		ExpiredSeconds:   int(s.config.DeliveryRetentionExpired / time.Second),
+		Mode:             s.config.DeliveryRetentionMode,
+	}
+}
+
+// retentionOverride returns the policy a subscription request sets, nil when it is
+// empty and the global policy applies
+func retentionOverride(policy *models.RetentionPolicy) *models.RetentionPolicy {
+	if policy == nil || *policy == (models.RetentionPolicy{}) {
+		return nil
+	}
+	return policy
+}
+
+// ApplyRetention deletes, or strips the payloads of, terminal deliveries past the
+// retention of their status. Subscriptions with a policy of their own are handled
+// separately from the rest, each in batches of RETENTION_BATCH_SIZE. When an archive
+// sink is set, each batch is archived first and nothing is removed if that fails.
+func (s *WebhookService) ApplyRetention(ctx context.Context) error {
+	global := s.globalRetentionPolicy()
+	if global.Mode != models.RetentionModeDelete && global.Mode != models.RetentionModeStripPayload {
+		return fmt.Errorf("unknown DELIVERY_RETENTION_MODE %q", global.Mode)
+	}
+
+	overrides, err := s.repo.ListRetentionPolicies(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list subscription retention policies")
+		return err
+	}
+	overridden := make([]uuid.UUID, 0, len(overrides))
+	for id := range overrides {
+		overridden = append(overridden, id)
+	}
+
+	now := time.Now()
+	var total int64
+	for _, status := range models.RetentionStatuses {
+		count, err := s.purgeDeliveries(ctx, global, repository.RetentionFilter{
+			Status:               status,
+			ExcludeSubscriptions: overridden,
+		}, now)
+		total += count
+		if err != nil {
+			return err
+		}
+
+		for id, override := range overrides {
+			id := id
+			count, err := s.purgeDeliveries(ctx, global.Override(override), repository.RetentionFilter{
+				Status:         status,
+				SubscriptionID: &id,
+			}, now)
+			total += count
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	s.logger.WithField("delivery_count", total).Info("Delivery retention applied")
+	return nil
+}
+
+// purgeDeliveries applies a retention policy to the deliveries of a filter, one batch
+// at a time so that no statement holds its locks for long
+func (s *WebhookService) purgeDeliveries(ctx context.Context, policy models.RetentionPolicy, filter repository.RetentionFilter, now time.Time) (int64, error) {
+	retention := policy.Retention(filter.Status)
+	if retention <= 0 {
+		return 0, nil
+	}
+	filter.CreatedBefore = now.Add(-retention)
+	filter.Limit = s.retentionBatchSize()
+
+	purge := s.repo.StripDeliveryPayloads
+	if policy.Mode == models.RetentionModeDelete {
+		purge = s.repo.DeleteDeliveries
+		// Deleting deliveries, or the attempts of deliveries, of hours the stats rollup
+		// still recomputes would drop them from their buckets
+		cutoff := s.statsCutoff(now)
+		if filter.CreatedBefore.After(cutoff) {
+			filter.CreatedBefore = cutoff
+		}
+		filter.AttemptsBefore = cutoff
+	}
+
+	// Stripping deliveries again would only archive them twice
+	filter.WithPayload = policy.Mode == models.RetentionModeStripPayload
+
+	logger := s.logger.WithFields(logrus.Fields{
+		"status": filter.Status,
+		"mode":   policy.Mode,
+	})
+	var total int64
+	for {
+		batch := filter
+		selected := -1
+		if s.archive != nil {
+			// Only the deliveries archived are removed, the ones that changed state
+			// since are skipped by the status check of the purge
+			deliveries, err := s.repo.ListRetentionDeliveries(ctx, filter)
+			if err != nil {
+				logger.WithError(err).Error("Failed to list deliveries to archive")
+				return total, err
+			}
+			if len(deliveries) == 0 {
+				return total, nil
+			}
+			if err := s.archiveDeliveries(ctx, deliveries); err != nil {
+				logger.WithError(err).Error("Failed to archive deliveries")
+				return total, err
+			}
+			batch.IDs = make([]uuid.UUID, len(deliveries))
+			for i, delivery := range deliveries {
+				batch.IDs[i] = delivery.ID
+			}
+			selected = len(deliveries)
+		}
+
+		result, err := purge(ctx, batch)
+		if err != nil {
+			logger.WithError(err).Error("Failed to apply delivery retention")
+			return total, err
+		}
+		total += result.Deliveries
+		metrics.DeliveriesPurged.WithLabelValues(filter.Status, policy.Mode).Add(float64(result.Deliveries))
+		s.deleteUnreferencedBlobs(ctx, result.PayloadRefs)
+
+		if selected < 0 {
+			selected = int(result.Deliveries)
+		}
+		// Stop when the filter is drained, or when every archived delivery was locked
+		// and the next batch would select them again
+		if selected < filter.Limit || result.Deliveries == 0 {
+			return total, nil
+		}
+	}
+}
+
+// deleteUnreferencedBlobs deletes the blobs of removed payloads that no delivery
+// references any more. Blobs are content-addressed and shared by identical payloads,
+// so one may still be in use. The blob is locked while it is checked and deleted, so
+// an ingest that is storing the same payload cannot reference it in between. Failures
+// are logged only, an orphaned blob is harmless.
+func (s *WebhookService) deleteUnreferencedBlobs(ctx context.Context, refs []string) {
+	if s.blobs == nil {
+		return
+	}
+
+	seen := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+
+		s.deleteBlobIfUnreferenced(ctx, ref)
+	}
+}
+
+// deleteBlobIfUnreferenced deletes a payload blob when no delivery references it
+func (s *WebhookService) deleteBlobIfUnreferenced(ctx context.Context, ref string) {
+	release, err := s.repo.LockPayloadRef(ctx, ref, true)
+	if err != nil {
+		s.logger.WithError(err).WithField("payload_ref", ref).Error("Failed to lock payload blob")
+		return
+	}
+	defer release()
+
+	inUse, err := s.repo.PayloadRefInUse(ctx, ref)
+	if err != nil {
+		s.logger.WithError(err).WithField("payload_ref", ref).Error("Failed to check payload blob references")
+		return
+	}
+	if inUse {
+		return
+	}
+	if err := s.blobs.Delete(ctx, ref); err != nil {
+		s.logger.WithError(err).WithField("payload_ref", ref).Error("Failed to delete payload blob")
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// ErrNotCancellable is returned when cancelling a delivery that is not scheduled
+var ErrNotCancellable = errors.New("delivery is not in the SCHEDULED state")
+
+// defaultScheduledLimit caps a scheduled delivery listing that does not set a limit
+const defaultScheduledLimit = 100
+
+// checkSchedule rejects deliveries scheduled further ahead than MAX_SCHEDULE_AHEAD,
+// and expiry times that have passed or come before the delivery time. Delivery times
+// in the past are delivered right away.
+func (s *WebhookService) checkSchedule(schedule models.DeliverySchedule) error {
+	now := time.Now()
+	if s.config.MaxScheduleAhead > 0 && schedule.DeliverAt.After(now.Add(s.config.MaxScheduleAhead)) {
+		return &ValidationError{Message: fmt.Sprintf("deliveries can be scheduled at most %s ahead", s.config.MaxScheduleAhead)}
+	}
+	if expiresAt := schedule.ExpiresAt; !expiresAt.IsZero() {
+		if !expiresAt.After(now) {
+			return &ValidationError{Message: "expires_at must be in the future"}
+		}
+		if !expiresAt.After(schedule.DeliverAt) {
+			return &ValidationError{Message: "expires_at must be after the delivery time"}
+		}
+	}
+	return nil
+}
+
+// ListScheduledDeliveries lists the deliveries waiting for their scheduled time, the
+// next to fire first
+func (s *WebhookService) ListScheduledDeliveries(ctx context.Context, filter models.ScheduledDeliveriesRequest) ([]models.WebhookDelivery, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultScheduledLimit
+	}
+
+	deliveries, err := s.repo.ListScheduledDeliveries(ctx, filter)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list scheduled deliveries")
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// CancelDelivery cancels a scheduled delivery before it fires. Its task still fires
+// but the delivery is skipped.
+func (s *WebhookService) CancelDelivery(ctx context.Context, id uuid.UUID) (models.WebhookDelivery, error) {
+	cancelled, err := s.repo.CancelScheduledDelivery(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).WithField("delivery_id", id).Error("Failed to cancel webhook delivery")
+		return models.WebhookDelivery{}, err
+	}
+
+	delivery, err := s.repo.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.logger.WithError(err).WithField("delivery_id", id).Error("Failed to get cancelled webhook delivery")
+		}
+		return models.WebhookDelivery{}, err
+	}
+	if !cancelled {
+		return models.WebhookDelivery{}, ErrNotCancellable
+	}
+
+	s.publishDeliveryEvent(ctx, models.EventDeliveryCancelled, delivery, nil)
+	s.recordAudit(ctx, models.AuditDeliveryCancelled, models.AuditResourceDelivery, id.String(),
+		auditedDelivery{Status: models.StatusScheduled}, auditedDelivery{Status: delivery.Status}, nil)
+	s.logger.WithField("delivery_id", id).Info("Webhook delivery cancelled")
+	return *delivery, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/patrickmn/go-cache"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Unic-X/webhook-delivery/internal/metrics"
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/pkg/signature"
+)
+
+// secretBytes is the number of random bytes in a generated secret
+const secretBytes = 32
+
+// ErrNoSecretKey is returned when revealing the secret of a subscription without one
+var ErrNoSecretKey = errors.New("subscription has no signing secret")
+
+// generateSecret returns a new random signing secret. Generated secrets have the
+// whsec_<base64> form the Standard Webhooks scheme expects.
+func generateSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return signature.SecretPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// RevealSubscriptionSecret returns a subscription's signing secret. Every reveal is
+// written to the audit log with the reason given by the caller, and the secret is
+// not returned when that fails.
+func (s *WebhookService) RevealSubscriptionSecret(ctx context.Context, id uuid.UUID, reason string) (string, error) {
+	sub, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if sub.SecretKey == nil || *sub.SecretKey == "" {
+		return "", ErrNoSecretKey
+	}
+
+	metadata := map[string]string{"reason": reason}
+	if err := s.writeAudit(ctx, models.AuditSubscriptionSecretRevealed, models.AuditResourceSubscription, id.String(), nil, nil, metadata); err != nil {
+		return "", err
+	}
+	metrics.SecretReveals.Inc()
+
+	return *sub.SecretKey, nil
+}
+
+// RotateSubscriptionSecret replaces a subscription's signing secret. The replaced
+// secret stays valid until the overlap ends, so consumers can switch to the new one
+// while deliveries carry signatures from both. A previous secret that has not
+// expired yet is dropped.
+func (s *WebhookService) RotateSubscriptionSecret(ctx context.Context, id uuid.UUID, req models.RotateSecretRequest) (models.Subscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).WithField("subscription_id", id).Error("Failed to get subscription for secret rotation")
+		return models.Subscription{}, err
+	}
+
+	secret := ""
+	if req.SecretKey != nil {
+		secret = *req.SecretKey
+	}
+	if secret == "" {
+		if secret, err = generateSecret(); err != nil {
+			s.logger.WithError(err).Error("Failed to generate subscription secret")
+			return models.Subscription{}, err
+		}
+	}
+	if sub.SecretKey != nil && *sub.SecretKey == secret {
+		return models.Subscription{}, &ValidationError{Message: "secret_key must differ from the current secret"}
+	}
+	before := auditSubscription(*sub)
+
+	overlap := s.config.SecretRotationOverlap
+	if req.OverlapSeconds != nil {
+		overlap = time.Duration(*req.OverlapSeconds) * time.Second
+	}
+
+	now := time.Now()
+	sub.PreviousSecretKey = nil
+	sub.PreviousSecretExpiresAt = nil
+	if sub.SecretKey != nil && *sub.SecretKey != "" && overlap > 0 {
+		expiresAt := now.Add(overlap)
+		sub.PreviousSecretKey = sub.SecretKey
+		sub.PreviousSecretExpiresAt = &expiresAt
+	}
+	sub.SecretKey = &secret
+	sub.UpdatedAt = now
+
+	if err := s.repo.RotateSubscriptionSecret(ctx, sub); err != nil {
+		s.logger.WithError(err).WithField("subscription_id", id).Error("Failed to rotate subscription secret")
+		return models.Subscription{}, err
+	}
+	sub.SetSecretKeyHint()
+
+	cacheKey := fmt.Sprintf("subscription:%s", id.String())
+	s.cache.Set(cacheKey, *sub, cache.DefaultExpiration)
+
+	s.recordAudit(ctx, models.AuditSubscriptionSecretRotated, models.AuditResourceSubscription, id.String(),
+		before, auditSubscription(*sub), nil)
+	s.logger.WithFields(logrus.Fields{
+		"subscription_id":            id,
+		"previous_secret_expires_at": sub.PreviousSecretExpiresAt,
+	}).Info("Subscription secret rotated")
+
+	return *sub, nil
+}
+
+// ExpirePreviousSecrets removes previous signing secrets whose overlap has ended.
+// Expired secrets are never used for signing, this only drops them from storage.
+func (s *WebhookService) ExpirePreviousSecrets(ctx context.Context) error {
+	count, err := s.repo.ClearExpiredPreviousSecrets(ctx, time.Now())
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to clear expired previous secrets")
+		return err
+	}
+
+	s.logger.WithField("subscription_count", count).Info("Expired previous secrets cleared")
+	return nil
+}
@@ -1,16 +1,11 @@
 package service
 
 import (
-	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -18,10 +13,20 @@ import (
 	"github.com/hibiken/asynq"
 	"github.com/patrickmn/go-cache"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/Unic-X/webhook-delivery/internal/archive"
+	"github.com/Unic-X/webhook-delivery/internal/blob"
 	"github.com/Unic-X/webhook-delivery/internal/config"
+	"github.com/Unic-X/webhook-delivery/internal/destination"
+	"github.com/Unic-X/webhook-delivery/internal/events"
+	"github.com/Unic-X/webhook-delivery/internal/metrics"
 	"github.com/Unic-X/webhook-delivery/internal/models"
 	"github.com/Unic-X/webhook-delivery/internal/repository"
+	"github.com/Unic-X/webhook-delivery/internal/signing"
+	"github.com/Unic-X/webhook-delivery/internal/tracing"
+	sig "github.com/Unic-X/webhook-delivery/pkg/signature"
 )
 
 // Service defines the interface for business logic
@@ -32,23 +37,76 @@ type Service interface {
 	UpdateSubscription(ctx context.Context, id uuid.UUID, req models.SubscriptionRequest) (models.Subscription, error)
 	DeleteSubscription(ctx context.Context, id uuid.UUID) error
 	ListSubscriptions(ctx context.Context) ([]models.Subscription, error)
+	RevealSubscriptionSecret(ctx context.Context, id uuid.UUID, reason string) (string, error)
+	RotateSubscriptionSecret(ctx context.Context, id uuid.UUID, req models.RotateSecretRequest) (models.Subscription, error)
+	PublicSigningKeys() sig.JWKS
+
+	// Event type catalog
+	CreateEventType(ctx context.Context, req models.EventTypeRequest) (models.EventType, error)
+	AddEventTypeVersion(ctx context.Context, name string, req models.EventTypeVersionRequest) (models.EventTypeVersion, error)
+	GetEventType(ctx context.Context, name string) (models.EventType, error)
+	ListEventTypes(ctx context.Context) ([]models.EventType, error)
 
 	// Webhook operations
-	IngestWebhook(ctx context.Context, subscriptionID uuid.UUID, eventType string, payload json.RawMessage, signature string) error
+	IngestWebhook(ctx context.Context, subscriptionID uuid.UUID, eventType string, payload json.RawMessage, signature string, schedule models.DeliverySchedule) error
+	PublishEvent(ctx context.Context, eventType string, payload json.RawMessage, schedule models.DeliverySchedule) ([]models.WebhookDelivery, error)
 	VerifySignature(payload []byte, signature string, secretKey string) bool
 
 	// Delivery operations
 	GetDeliveryStatus(ctx context.Context, id uuid.UUID) (models.DeliveryStatusResponse, error)
 	GetRecentDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]models.WebhookDelivery, error)
+	ReplayDelivery(ctx context.Context, id uuid.UUID) (models.WebhookDelivery, error)
+	ReplayDeliveries(ctx context.Context, filter models.ReplayRequest) ([]models.WebhookDelivery, error)
+	ListScheduledDeliveries(ctx context.Context, filter models.ScheduledDeliveriesRequest) ([]models.WebhookDelivery, error)
+	CancelDelivery(ctx context.Context, id uuid.UUID) (models.WebhookDelivery, error)
+	RestoreArchive(ctx context.Context, day time.Time, subscriptionID *uuid.UUID, fn func(models.ArchivedDelivery) error) error
+
+	StreamDeliveryEvents(ctx context.Context, subscriptionID uuid.UUID) (<-chan models.DeliveryEvent, error)
+
+	// Analytics
+	GetDeliveryStats(ctx context.Context, subscriptionID uuid.UUID, from, to time.Time) (models.DeliveryStatsResponse, error)
+
+	// Audit log
+	ListAuditEvents(ctx context.Context, filter models.AuditEventsRequest) ([]models.AuditEvent, error)
+}
+
+// ErrNotReplayable is returned when replaying a delivery that has not failed
+var ErrNotReplayable = errors.New("delivery is not in the FAILED state")
+
+// ErrPayloadPurged is returned when replaying a delivery whose payload was removed by
+// the retention policy
+var ErrPayloadPurged = errors.New("delivery payload was removed by the retention policy")
+
+// defaultReplayLimit caps a replay request that does not set a limit
+const defaultReplayLimit = 100
+
+// httpTimeout is the request timeout of HTTP deliveries
+const httpTimeout = 10 * time.Second
+
+// reencryptBatchSize is the number of rows a re-encryption pass updates per query
+const reencryptBatchSize = 500
+
+// ValidationError is returned when a request is semantically invalid
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
 }
 
 // WebhookService implements the Service interface
 type WebhookService struct {
-	repo       repository.Repository
-	taskClient *asynq.Client
-	cache      *cache.Cache
-	config     *config.Config
-	logger     *logrus.Logger
+	repo         repository.Repository
+	taskClient   *asynq.Client
+	events       *events.Broker
+	destinations *destination.Registry
+	blobs        blob.Store
+	archive      archive.Sink
+	signingKeys  *signing.Keyring
+	cache        *cache.Cache
+	config       *config.Config
+	logger       *logrus.Logger
 }
 
 // NewWebhookService creates a new WebhookService
@@ -62,30 +120,131 @@ func NewWebhookService(repo repository.Repository, redisClient *redis.Client, cf
 	// Initialize cache with 5 minute expiration and 10 minute cleanup interval
 	c := cache.New(5*time.Minute, 10*time.Minute)
 
+	// HTTP and Redis Streams are always available, other transports are registered
+	// by the process that has their clients
+	destinations := destination.NewRegistry()
+	destinations.Register(models.DestinationHTTP, destination.NewHTTP(httpTimeout))
+	destinations.Register(models.DestinationRedisStream, destination.NewRedisStream(redisClient))
+
 	return &WebhookService{
-		repo:       repo,
-		taskClient: taskClient,
-		cache:      c,
-		config:     cfg,
-		logger:     logger,
+		repo:         repo,
+		taskClient:   taskClient,
+		events:       events.NewBroker(redisClient, logger),
+		destinations: destinations,
+		cache:        c,
+		config:       cfg,
+		logger:       logger,
 	}
 }
 
+// RegisterDestination registers the Destination used for a destination type
+func (s *WebhookService) RegisterDestination(destinationType string, d destination.Destination) {
+	s.destinations.Register(destinationType, d)
+}
+
+// SetBlobStore sets the store large payloads are offloaded to. Payloads are always
+// stored inline when no store is set.
+func (s *WebhookService) SetBlobStore(store blob.Store) {
+	s.blobs = store
+}
+
+// SetSigningKeys sets the Ed25519 keys deliveries of the ed25519 signature scheme are
+// signed with and that are published at /.well-known/webhook-keys. Subscriptions
+// cannot use the ed25519 scheme when no keys are set.
+func (s *WebhookService) SetSigningKeys(keys *signing.Keyring) {
+	s.signingKeys = keys
+	s.destinations.Register(models.DestinationHTTP, destination.NewHTTP(httpTimeout, keys.PrivateKeys()...))
+}
+
+// PublicSigningKeys returns the public signing keys as a JSON Web Key Set
+func (s *WebhookService) PublicSigningKeys() sig.JWKS {
+	return s.signingKeys.JWKS()
+}
+
+// validateSignatureScheme checks that the service can sign with the requested scheme
+func (s *WebhookService) validateSignatureScheme(scheme string) error {
+	if scheme == models.SignatureSchemeEd25519 && s.signingKeys == nil {
+		return &ValidationError{Message: "the ed25519 signature scheme requires SIGNING_KEYS to be configured"}
+	}
+	return nil
+}
+
+// validateDestination checks that the request carries the settings its destination
+// type needs and fills in the default destination type
+func validateDestination(req *models.SubscriptionRequest) error {
+	if req.DestinationType == "" {
+		req.DestinationType = models.DestinationHTTP
+	}
+
+	switch req.DestinationType {
+	case models.DestinationHTTP:
+		if req.TargetURL == "" {
+			return &ValidationError{Message: "target_url is required for http destinations"}
+		}
+	case models.DestinationKafka:
+		if req.DestinationConfig == nil || req.DestinationConfig.Topic == "" {
+			return &ValidationError{Message: "destination_config.topic is required for kafka destinations"}
+		}
+	case models.DestinationRedisStream:
+		if req.DestinationConfig == nil || req.DestinationConfig.Stream == "" {
+			return &ValidationError{Message: "destination_config.stream is required for redis_stream destinations"}
+		}
+	default:
+		return &ValidationError{Message: fmt.Sprintf("unsupported destination type %q", req.DestinationType)}
+	}
+
+	return nil
+}
+
 // CreateSubscription creates a new subscription
 func (s *WebhookService) CreateSubscription(ctx context.Context, req models.SubscriptionRequest) (models.Subscription, error) {
+	if err := validateDestination(&req); err != nil {
+		return models.Subscription{}, err
+	}
+	if err := s.validateEventTypes(ctx, req.EventTypes); err != nil {
+		return models.Subscription{}, err
+	}
+	if req.SignatureScheme == "" {
+		req.SignatureScheme = models.SignatureSchemeGitHub
+	}
+	if err := s.validateSignatureScheme(req.SignatureScheme); err != nil {
+		return models.Subscription{}, err
+	}
+	if req.MaxEventAgeSeconds != nil && *req.MaxEventAgeSeconds == 0 {
+		req.MaxEventAgeSeconds = nil
+	}
+
+	secretKey := req.SecretKey
+	if secretKey == nil || *secretKey == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to generate subscription secret")
+			return models.Subscription{}, err
+		}
+		secretKey = &secret
+	}
+
 	sub := models.Subscription{
-		ID:         uuid.New(),
-		TargetURL:  req.TargetURL,
-		SecretKey:  req.SecretKey,
-		EventTypes: models.StringArray(req.EventTypes),
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		ID:                 uuid.New(),
+		TargetURL:          req.TargetURL,
+		SecretKey:          secretKey,
+		EventTypes:         models.StringArray(req.EventTypes),
+		DestinationType:    req.DestinationType,
+		DestinationConfig:  req.DestinationConfig,
+		SignatureScheme:    req.SignatureScheme,
+		MaxEventAgeSeconds: req.MaxEventAgeSeconds,
+		RetentionPolicy:    retentionOverride(req.RetentionPolicy),
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
 	}
 
 	if err := s.repo.CreateSubscription(ctx, &sub); err != nil {
 		s.logger.WithError(err).Error("Failed to create subscription")
 		return models.Subscription{}, err
 	}
+	sub.SetSecretKeyHint()
+	s.recordAudit(ctx, models.AuditSubscriptionCreated, models.AuditResourceSubscription, sub.ID.String(),
+		nil, auditSubscription(sub), nil)
 
 	return sub, nil
 }
@@ -95,15 +254,18 @@ func (s *WebhookService) GetSubscription(ctx context.Context, id uuid.UUID) (mod
 	// Try to get from cache first
 	cacheKey := fmt.Sprintf("subscription:%s", id.String())
 	if cached, found := s.cache.Get(cacheKey); found {
+		metrics.CacheRequests.WithLabelValues("hit").Inc()
 		s.logger.WithField("subscription_id", id).Debug("Subscription retrieved from cache")
 		return cached.(models.Subscription), nil
 	}
+	metrics.CacheRequests.WithLabelValues("miss").Inc()
 
 	sub, err := s.repo.GetSubscription(ctx, id)
 	if err != nil {
 		s.logger.WithError(err).WithField("subscription_id", id).Error("Failed to get subscription")
 		return models.Subscription{}, err
 	}
+	sub.SetSecretKeyHint()
 
 	// Cache the result
 	s.cache.Set(cacheKey, *sub, cache.DefaultExpiration)
@@ -113,22 +275,56 @@ func (s *WebhookService) GetSubscription(ctx context.Context, id uuid.UUID) (mod
 
 // UpdateSubscription updates an existing subscription
 func (s *WebhookService) UpdateSubscription(ctx context.Context, id uuid.UUID, req models.SubscriptionRequest) (models.Subscription, error) {
+	if err := validateDestination(&req); err != nil {
+		return models.Subscription{}, err
+	}
+	if err := s.validateEventTypes(ctx, req.EventTypes); err != nil {
+		return models.Subscription{}, err
+	}
+
+	if err := s.validateSignatureScheme(req.SignatureScheme); err != nil {
+		return models.Subscription{}, err
+	}
+
 	sub, err := s.repo.GetSubscription(ctx, id)
 	if err != nil {
 		s.logger.WithError(err).WithField("subscription_id", id).Error("Failed to get subscription for update")
 		return models.Subscription{}, err
 	}
 
-	// Update the fields
+	before := auditSubscription(*sub)
+
+	// Update the fields, the secret is only replaced when a new one is given
 	sub.TargetURL = req.TargetURL
-	sub.SecretKey = req.SecretKey
+	if req.SecretKey != nil && *req.SecretKey != "" {
+		sub.SecretKey = req.SecretKey
+	}
 	sub.EventTypes = models.StringArray(req.EventTypes)
+	sub.DestinationType = req.DestinationType
+	sub.DestinationConfig = req.DestinationConfig
+	if req.SignatureScheme != "" {
+		sub.SignatureScheme = req.SignatureScheme
+	}
+	// The event age limit is only changed when given, zero removes it
+	if req.MaxEventAgeSeconds != nil {
+		sub.MaxEventAgeSeconds = req.MaxEventAgeSeconds
+		if *req.MaxEventAgeSeconds == 0 {
+			sub.MaxEventAgeSeconds = nil
+		}
+	}
+	// The retention policy is only changed when given, an empty one removes it
+	if req.RetentionPolicy != nil {
+		sub.RetentionPolicy = retentionOverride(req.RetentionPolicy)
+	}
 	sub.UpdatedAt = time.Now()
 
 	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
 		s.logger.WithError(err).WithField("subscription_id", id).Error("Failed to update subscription")
 		return models.Subscription{}, err
 	}
+	sub.SetSecretKeyHint()
+	s.recordAudit(ctx, models.AuditSubscriptionUpdated, models.AuditResourceSubscription, id.String(),
+		before, auditSubscription(*sub), nil)
 
 	// Update the cache
 	cacheKey := fmt.Sprintf("subscription:%s", id.String())
@@ -137,12 +333,23 @@ func (s *WebhookService) UpdateSubscription(ctx context.Context, id uuid.UUID, r
 	return *sub, nil
 }
 
-// DeleteSubscription deletes a subscription by ID
+// DeleteSubscription deletes a subscription by ID. Deleting a subscription that does
+// not exist succeeds and is not audited.
 func (s *WebhookService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	existing, err := s.repo.GetSubscription(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.logger.WithError(err).WithField("subscription_id", id).Error("Failed to get subscription for delete")
+		return err
+	}
+
 	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
 		s.logger.WithError(err).WithField("subscription_id", id).Error("Failed to delete subscription")
 		return err
 	}
+	if existing != nil {
+		s.recordAudit(ctx, models.AuditSubscriptionDeleted, models.AuditResourceSubscription, id.String(),
+			auditSubscription(*existing), nil, nil)
+	}
 
 	// Remove from cache
 	cacheKey := fmt.Sprintf("subscription:%s", id.String())
@@ -158,15 +365,30 @@ func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]models.Subscr
 		s.logger.WithError(err).Error("Failed to list subscriptions")
 		return nil, err
 	}
+	for i := range subs {
+		subs[i].SetSecretKeyHint()
+	}
 	return subs, nil
 }
 
 // IngestWebhook ingests a webhook payload and queues it for delivery
-func (s *WebhookService) IngestWebhook(ctx context.Context, subscriptionID uuid.UUID, eventType string, payload json.RawMessage, signature string) error {
+func (s *WebhookService) IngestWebhook(ctx context.Context, subscriptionID uuid.UUID, eventType string, payload json.RawMessage, signature string, schedule models.DeliverySchedule) error {
+	if err := s.checkPayloadSize(payload); err != nil {
+		return err
+	}
+	if err := s.checkSchedule(schedule); err != nil {
+		return err
+	}
+
 	// Verify subscription exists
 	sub, err := s.GetSubscription(ctx, subscriptionID)
 	if err != nil {
 		s.logger.WithError(err).WithField("subscription_id", subscriptionID).Error("Failed to get subscription for webhook ingestion")
+		outcome := metrics.OutcomeError
+		if errors.Is(err, sql.ErrNoRows) {
+			outcome = metrics.OutcomeUnknownSubscription
+		}
+		metrics.WebhooksIngested.WithLabelValues(outcome).Inc()
 		return err
 	}
 
@@ -185,62 +407,248 @@ func (s *WebhookService) IngestWebhook(ctx context.Context, subscriptionID uuid.
 				"event_type":      eventType,
 				"allowed_types":   sub.EventTypes,
 			}).Info("Event type not matched for subscription, skipping delivery")
+			metrics.WebhooksIngested.WithLabelValues(metrics.OutcomeFiltered).Inc()
 			return nil
 		}
 	}
 
-	// Verify signature if a secret key is present
-	if sub.SecretKey != nil && *sub.SecretKey != "" && signature != "" {
-		if !s.VerifySignature([]byte(payload), signature, *sub.SecretKey) {
+	// Verify signature if a secret key is present. During a rotation overlap either
+	// secret is accepted.
+	if secrets := sub.SigningSecrets(time.Now()); len(secrets) > 0 && signature != "" {
+		if sig.VerifyHub(signature, payload, secrets...) != nil {
 			s.logger.WithField("subscription_id", subscriptionID).Warn("Invalid signature for webhook")
+			metrics.WebhooksIngested.WithLabelValues(metrics.OutcomeInvalidSignature).Inc()
 			return errors.New("invalid signature")
 		}
 	}
 
-	// Create delivery record
+	if err := s.validatePayload(ctx, eventType, payload); err != nil {
+		metrics.WebhooksIngested.WithLabelValues(schemaOutcome(err)).Inc()
+		return err
+	}
+
+	stored, release, err := s.storePayload(ctx, []uuid.UUID{sub.ID}, payload)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	_, err = s.queueDelivery(ctx, sub.ID, eventType, stored[0], schedule)
+	return err
+}
+
+// PublishEvent fans an event out to every subscription that accepts its event type
+// and returns its deliveries. The deliveries are stored together, one whose task
+// cannot be enqueued is returned as FAILED.
+func (s *WebhookService) PublishEvent(ctx context.Context, eventType string, payload json.RawMessage, schedule models.DeliverySchedule) ([]models.WebhookDelivery, error) {
+	if err := s.checkPayloadSize(payload); err != nil {
+		return nil, err
+	}
+	if err := s.checkSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.validatePayload(ctx, eventType, payload); err != nil {
+		metrics.WebhooksIngested.WithLabelValues(schemaOutcome(err)).Inc()
+		return nil, err
+	}
+
+	subs, err := s.repo.ListSubscriptionsForEventType(ctx, eventType)
+	if err != nil {
+		s.logger.WithError(err).WithField("event_type", eventType).Error("Failed to list subscriptions for event")
+		return nil, err
+	}
+
+	subscriptionIDs := make([]uuid.UUID, len(subs))
+	for i, sub := range subs {
+		subscriptionIDs[i] = sub.ID
+	}
+	stored, release, err := s.storePayload(ctx, subscriptionIDs, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	deliveries := make([]models.WebhookDelivery, 0, len(subs))
+	for i, sub := range subs {
+		deliveries = append(deliveries, s.newDelivery(sub.ID, eventType, stored[i], schedule))
+	}
+
+	// All deliveries are stored before any is enqueued, so a failed publish can be
+	// retried without duplicating the deliveries of the subscriptions that succeeded
+	if len(deliveries) > 0 {
+		if err := s.repo.CreateWebhookDeliveries(ctx, deliveries); err != nil {
+			s.logger.WithError(err).WithField("event_type", eventType).Error("Failed to create webhook delivery records")
+			metrics.WebhooksIngested.WithLabelValues(metrics.OutcomeError).Add(float64(len(deliveries)))
+			return nil, err
+		}
+	}
+
+	// The event is accepted once its deliveries are stored. A delivery whose task
+	// cannot be enqueued is marked FAILED so that it can be replayed, failing the
+	// publish instead would store all deliveries again on the retry.
+	for i := range deliveries {
+		if err := s.dispatchDelivery(ctx, &deliveries[i]); err != nil {
+			s.failUndispatchedDelivery(ctx, &deliveries[i])
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"event_type":     eventType,
+		"delivery_count": len(deliveries),
+	}).Info("Event published")
+
+	return deliveries, nil
+}
+
+// newDelivery builds the delivery of a payload for a subscription. Deliveries due
+// later wait as SCHEDULED until their task fires.
+//
+// The delivery tables are partitioned on created_at, so their primary keys are
+// (id, created_at) and nothing in the database keeps an ID from being reused in
+// another partition. IDs are random UUIDs generated here, never taken from a client,
+// which keeps them unique.
+func (s *WebhookService) newDelivery(subscriptionID uuid.UUID, eventType string, payload storedPayload, schedule models.DeliverySchedule) models.WebhookDelivery {
 	var eventTypePtr *string
 	if eventType != "" {
 		eventTypePtr = &eventType
 	}
 
+	// Postgres keeps microseconds, truncate so the creation time matches the stored
+	// one when it is used to find the delivery
+	createdAt := time.Now().Truncate(time.Microsecond)
+
 	delivery := models.WebhookDelivery{
 		ID:             uuid.New(),
 		SubscriptionID: subscriptionID,
-		Payload:        payload,
 		EventType:      eventTypePtr,
-		CreatedAt:      time.Now(),
+		CreatedAt:      createdAt,
 		Status:         models.StatusPending,
 		RetryCount:     0,
 		MaxRetries:     s.config.RetryLimit,
 	}
+	payload.apply(&delivery)
+	if !schedule.ExpiresAt.IsZero() {
+		delivery.ExpiresAt = &schedule.ExpiresAt
+	}
+	if deliverAt := schedule.DeliverAt; deliverAt.After(delivery.CreatedAt) {
+		delivery.Status = models.StatusScheduled
+		delivery.NextRetryAt = &deliverAt
+	}
+	return delivery
+}
+
+// queueDelivery stores a delivery for a subscription and enqueues it for processing
+func (s *WebhookService) queueDelivery(ctx context.Context, subscriptionID uuid.UUID, eventType string, payload storedPayload, schedule models.DeliverySchedule) (*models.WebhookDelivery, error) {
+	delivery := s.newDelivery(subscriptionID, eventType, payload, schedule)
 
 	if err := s.repo.CreateWebhookDelivery(ctx, &delivery); err != nil {
 		s.logger.WithError(err).WithField("subscription_id", subscriptionID).Error("Failed to create webhook delivery record")
-		return err
+		metrics.WebhooksIngested.WithLabelValues(metrics.OutcomeError).Inc()
+		return nil, err
 	}
 
-	// Queue task for processing
-	task := asynq.NewTask("webhook:deliver", []byte(delivery.ID.String()))
-	_, err = s.taskClient.Enqueue(task)
-	if err != nil {
+	if err := s.dispatchDelivery(ctx, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// dispatchDelivery enqueues the task of a stored delivery, at its deliver_at time
+// when it is scheduled
+func (s *WebhookService) dispatchDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	var opts []asynq.Option
+	if delivery.Status == models.StatusScheduled && delivery.NextRetryAt != nil {
+		opts = append(opts, asynq.ProcessAt(*delivery.NextRetryAt))
+	}
+
+	if err := s.enqueueDelivery(ctx, delivery, opts...); err != nil {
 		s.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to enqueue webhook delivery task")
+		metrics.EnqueueFailures.WithLabelValues("ingest").Inc()
+		metrics.WebhooksIngested.WithLabelValues(metrics.OutcomeEnqueueFailed).Inc()
 		return err
 	}
 
+	metrics.WebhooksIngested.WithLabelValues(metrics.OutcomeAccepted).Inc()
+	s.publishDeliveryEvent(ctx, models.EventDeliveryCreated, delivery, nil)
+
 	s.logger.WithFields(logrus.Fields{
 		"delivery_id":     delivery.ID,
-		"subscription_id": subscriptionID,
+		"subscription_id": delivery.SubscriptionID,
+		"deliver_at":      delivery.NextRetryAt,
 	}).Info("Webhook queued for delivery")
 
 	return nil
 }
 
+// failUndispatchedDelivery moves a stored delivery whose task could not be enqueued
+// to FAILED, where it can be replayed
+func (s *WebhookService) failUndispatchedDelivery(ctx context.Context, delivery *models.WebhookDelivery) {
+	delivery.Status = models.StatusFailed
+	delivery.NextRetryAt = nil
+	if err := s.repo.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		s.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to mark unqueued webhook delivery as failed")
+		return
+	}
+	s.publishDeliveryEvent(ctx, models.EventDeliveryDeadLettered, delivery, nil)
+}
+
+// deliveryTaskPayload is the payload of a webhook:deliver task. The delivery's
+// creation time completes its primary key, so the worker reads a single partition.
+// The trace context lets the worker continue the trace that was started when the
+// webhook was ingested.
+type deliveryTaskPayload struct {
+	DeliveryID   uuid.UUID         `json:"delivery_id"`
+	CreatedAt    time.Time         `json:"created_at"`
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+}
+
+// enqueueDelivery enqueues a webhook:deliver task carrying the current trace context
+func (s *WebhookService) enqueueDelivery(ctx context.Context, delivery *models.WebhookDelivery, opts ...asynq.Option) error {
+	ctx, span := tracing.Tracer().Start(ctx, "asynq.enqueue webhook:deliver",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attribute.String("webhook.delivery_id", delivery.ID.String())),
+	)
+	defer span.End()
+
+	payload, err := json.Marshal(deliveryTaskPayload{
+		DeliveryID:   delivery.ID,
+		CreatedAt:    delivery.CreatedAt,
+		TraceContext: tracing.Inject(ctx),
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	if _, err := s.taskClient.EnqueueContext(ctx, asynq.NewTask("webhook:deliver", payload), opts...); err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+// ParseDeliveryTask extracts the delivery ID and creation time from a webhook:deliver
+// task payload and returns a context carrying the trace context of the enqueuer. The
+// creation time is zero for tasks enqueued before it was carried.
+func ParseDeliveryTask(ctx context.Context, payload []byte) (context.Context, uuid.UUID, time.Time, error) {
+	var p deliveryTaskPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		// Tasks enqueued before trace propagation carry the bare delivery ID
+		id, parseErr := uuid.Parse(string(payload))
+		if parseErr != nil {
+			return ctx, uuid.Nil, time.Time{}, parseErr
+		}
+		return ctx, id, time.Time{}, nil
+	}
+
+	return tracing.Extract(ctx, p.TraceContext), p.DeliveryID, p.CreatedAt, nil
+}
+
 // VerifySignature verifies the HMAC-SHA256 signature of a payload
 func (s *WebhookService) VerifySignature(payload []byte, signature string, secretKey string) bool {
-	h := hmac.New(sha256.New, []byte(secretKey))
-	h.Write(payload)
-	expectedSignature := "sha256=" + hex.EncodeToString(h.Sum(nil))
-	return hmac.Equal([]byte(signature), []byte(expectedSignature))
+	return sig.VerifyHub(signature, payload, secretKey) == nil
 }
 
 // GetDeliveryStatus retrieves the status and attempts for a webhook delivery
@@ -251,7 +659,7 @@ func (s *WebhookService) GetDeliveryStatus(ctx context.Context, id uuid.UUID) (m
 		return models.DeliveryStatusResponse{}, err
 	}
 
-	attempts, err := s.repo.GetDeliveryAttempts(ctx, id)
+	attempts, err := s.repo.GetDeliveryAttempts(ctx, id, delivery.CreatedAt)
 	if err != nil {
 		s.logger.WithError(err).WithField("delivery_id", id).Error("Failed to get delivery attempts")
 		return models.DeliveryStatusResponse{}, err
@@ -278,20 +686,82 @@ func (s *WebhookService) GetRecentDeliveries(ctx context.Context, subscriptionID
 	return deliveries, nil
 }
 
-// DeliverWebhook delivers a webhook to the target URL
-func (s *WebhookService) DeliverWebhook(ctx context.Context, deliveryID uuid.UUID) error {
-	delivery, err := s.repo.GetWebhookDelivery(ctx, deliveryID)
+// GetDeliveryStats retrieves hourly delivery statistics for a subscription. Hours that
+// are no longer fully covered by the retained delivery attempts are read from the
+// rollup table, the rest are computed from the live tables.
+func (s *WebhookService) GetDeliveryStats(ctx context.Context, subscriptionID uuid.UUID, from, to time.Time) (models.DeliveryStatsResponse, error) {
+	from = from.Truncate(time.Hour)
+	cutoff := s.statsCutoff(time.Now())
+
+	buckets := make([]models.DeliveryStats, 0)
+
+	if from.Before(cutoff) {
+		end := to
+		if end.After(cutoff) {
+			end = cutoff
+		}
+
+		rolled, err := s.repo.GetDeliveryStats(ctx, subscriptionID, from, end)
+		if err != nil {
+			s.logger.WithError(err).WithField("subscription_id", subscriptionID).Error("Failed to get rolled up delivery stats")
+			return models.DeliveryStatsResponse{}, err
+		}
+		buckets = append(buckets, rolled...)
+	}
+
+	if to.After(cutoff) {
+		start := from
+		if start.Before(cutoff) {
+			start = cutoff
+		}
+
+		live, err := s.repo.ComputeDeliveryStats(ctx, subscriptionID, start, to)
+		if err != nil {
+			s.logger.WithError(err).WithField("subscription_id", subscriptionID).Error("Failed to compute live delivery stats")
+			return models.DeliveryStatsResponse{}, err
+		}
+		buckets = append(buckets, live...)
+	}
+
+	return models.DeliveryStatsResponse{
+		SubscriptionID: subscriptionID,
+		From:           from,
+		To:             to,
+		Buckets:        buckets,
+	}, nil
+}
+
+// DeliverWebhook delivers a webhook to the subscription's destination. createdAt is
+// the delivery's creation time from its task, zero when the task does not carry it.
+func (s *WebhookService) DeliverWebhook(ctx context.Context, deliveryID uuid.UUID, createdAt time.Time) error {
+	var delivery *models.WebhookDelivery
+	var err error
+	if createdAt.IsZero() {
+		delivery, err = s.repo.GetWebhookDelivery(ctx, deliveryID)
+	} else {
+		delivery, err = s.repo.GetWebhookDeliveryAt(ctx, deliveryID, createdAt)
+	}
 	if err != nil {
 		s.logger.WithError(err).WithField("delivery_id", deliveryID).Error("Failed to get webhook delivery for processing")
 		return err
 	}
 
-	// Update status to processing
-	delivery.Status = models.StatusProcessing
-	if err := s.repo.UpdateWebhookDelivery(ctx, delivery); err != nil {
+	// Update status to processing, unless the delivery is already final, e.g. it was
+	// cancelled before its scheduled time or the task was delivered twice
+	claimed, err := s.repo.ClaimWebhookDelivery(ctx, deliveryID, delivery.CreatedAt)
+	if err != nil {
 		s.logger.WithError(err).WithField("delivery_id", deliveryID).Error("Failed to update webhook delivery status to processing")
 		return err
 	}
+	if !claimed {
+		s.logger.WithFields(logrus.Fields{
+			"delivery_id": deliveryID,
+			"status":      delivery.Status,
+		}).Info("Skipping webhook delivery in a final state")
+		return nil
+	}
+	delivery.Status = models.StatusProcessing
+	s.publishDeliveryEvent(ctx, models.EventDeliveryProcessing, delivery, nil)
 
 	// Get subscription details
 	subscription, err := s.GetSubscription(ctx, delivery.SubscriptionID)
@@ -300,113 +770,154 @@ func (s *WebhookService) DeliverWebhook(ctx context.Context, deliveryID uuid.UUI
 		return err
 	}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	// Prepare request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.TargetURL, nil)
-	if err != nil {
-		s.logger.WithError(err).WithField("target_url", subscription.TargetURL).Error("Failed to create HTTP request")
-		return s.handleDeliveryFailure(ctx, delivery, err, nil)
-	}
-
-	// Set payload as request body
-	req.Body = http.NoBody
-	req.GetBody = func() (io.ReadCloser, error) {
-		return io.NopCloser(bytes.NewReader(delivery.Payload)), nil
-	}
-	req.ContentLength = int64(len(delivery.Payload))
-	req.Header.Set("Content-Type", "application/json")
-
-	// Add event type header if present
-	if delivery.EventType != nil {
-		req.Header.Set("X-Webhook-Event", *delivery.EventType)
-	}
-
-	// Add delivery ID header
-	req.Header.Set("X-Webhook-ID", delivery.ID.String())
-
-	// Add signature if secret key is present
-	if subscription.SecretKey != nil && *subscription.SecretKey != "" {
-		h := hmac.New(sha256.New, []byte(*subscription.SecretKey))
-		h.Write(delivery.Payload)
-		signature := "sha256=" + hex.EncodeToString(h.Sum(nil))
-		req.Header.Set("X-Hub-Signature-256", signature)
+	// Deliveries that expired while queued or retrying are not sent
+	if expiry, reason := delivery.Expiry(subscription.MaxEventAge()); !expiry.IsZero() && !time.Now().Before(expiry) {
+		return s.expireDelivery(ctx, delivery, expiry, reason)
 	}
 
-	// Make the request
-	resp, err := client.Do(req)
+	// Send to the destination
+	result := s.send(ctx, delivery, &subscription)
 
 	// Create attempt record
 	attempt := models.DeliveryAttempt{
 		ID:            uuid.New(),
 		DeliveryID:    deliveryID,
 		AttemptNumber: delivery.RetryCount + 1,
-		CreatedAt:     time.Now(),
+		StatusCode:    result.StatusCode,
+		CreatedAt:     attemptTime(delivery),
 	}
 
-	// Handle any request errors
-	if err != nil {
-		s.logger.WithError(err).WithField("delivery_id", deliveryID).Error("Failed to deliver webhook")
-		errDetails := err.Error()
+	// Handle failure
+	if result.Err != nil {
+		errDetails := result.Err.Error()
 		attempt.Status = models.StatusFailed
 		attempt.ErrorDetails = &errDetails
 
 		if err := s.repo.CreateDeliveryAttempt(ctx, &attempt); err != nil {
 			s.logger.WithError(err).WithField("delivery_id", deliveryID).Error("Failed to create delivery attempt record")
 		}
+		s.publishDeliveryEvent(ctx, models.EventAttemptFailed, delivery, &attempt)
+
+		s.logger.WithError(result.Err).WithFields(logrus.Fields{
+			"delivery_id":      deliveryID,
+			"destination_type": subscription.DestinationType,
+			"status_code":      result.StatusCode,
+			"attempt":          attempt.AttemptNumber,
+		}).Warn("Webhook delivery failed")
 
-		return s.handleDeliveryFailure(ctx, delivery, err, nil)
+		return s.handleDeliveryFailure(ctx, delivery, result.Err, result.StatusCode)
 	}
 
-	// Process response
-	defer resp.Body.Close()
+	attempt.Status = models.StatusSuccess
+	if err := s.repo.CreateDeliveryAttempt(ctx, &attempt); err != nil {
+		s.logger.WithError(err).WithField("delivery_id", deliveryID).Error("Failed to create delivery attempt record")
+	}
 
-	// Add status code to attempt
-	attempt.StatusCode = &resp.StatusCode
+	// Update delivery status to delivered
+	delivery.Status = models.StatusDelivered
+	if err := s.repo.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		s.logger.WithError(err).WithField("delivery_id", deliveryID).Error("Failed to update webhook delivery status to delivered")
+	}
+	s.publishDeliveryEvent(ctx, models.EventAttemptSucceeded, delivery, &attempt)
 
-	// Check if it's a success (2xx status code)
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		attempt.Status = models.StatusSuccess
+	s.logger.WithFields(logrus.Fields{
+		"delivery_id":      deliveryID,
+		"destination_type": subscription.DestinationType,
+		"status_code":      result.StatusCode,
+		"attempt":          attempt.AttemptNumber,
+	}).Info("Webhook delivered successfully")
 
-		if err := s.repo.CreateDeliveryAttempt(ctx, &attempt); err != nil {
-			s.logger.WithError(err).WithField("delivery_id", deliveryID).Error("Failed to create delivery attempt record")
-		}
+	return nil
+}
 
-		// Update delivery status to delivered
-		delivery.Status = models.StatusDelivered
-		if err := s.repo.UpdateWebhookDelivery(ctx, delivery); err != nil {
-			s.logger.WithError(err).WithField("delivery_id", deliveryID).Error("Failed to update webhook delivery status to delivered")
-		}
+// send hands the delivery to the destination registered for the subscription's
+// destination type, tracing and timing the call
+func (s *WebhookService) send(ctx context.Context, delivery *models.WebhookDelivery, subscription *models.Subscription) destination.Result {
+	dest, ok := s.destinations.Get(subscription.DestinationType)
+	if !ok {
+		return destination.Result{Err: fmt.Errorf("no destination registered for type %q", subscription.DestinationType)}
+	}
 
-		s.logger.WithFields(logrus.Fields{
-			"delivery_id": deliveryID,
-			"status_code": resp.StatusCode,
-			"attempt":     attempt.AttemptNumber,
-		}).Info("Webhook delivered successfully")
+	payload, err := s.deliveryPayload(delivery)
+	if err != nil {
+		return destination.Result{Err: err}
+	}
 
-		return nil
+	ctx, span := tracing.Tracer().Start(ctx, "deliver "+subscription.DestinationType,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("webhook.delivery_id", delivery.ID.String()),
+			attribute.String("webhook.destination_type", subscription.DestinationType),
+			attribute.Int("webhook.attempt", delivery.RetryCount+1),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	result := dest.Deliver(ctx, delivery, subscription, payload)
+	elapsed := time.Since(start)
+
+	if result.StatusCode != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", *result.StatusCode))
+	}
+	if result.Err != nil {
+		tracing.RecordError(span, result.Err)
 	}
 
-	// Handle failure
-	attempt.Status = models.StatusFailed
-	respBody, _ := io.ReadAll(resp.Body)
-	errDetails := fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(respBody))
-	attempt.ErrorDetails = &errDetails
+	class := metrics.ResultClass(result.StatusCode, result.Err)
+	metrics.DeliveryAttempts.WithLabelValues(class, subscription.ID.String()).Inc()
+	metrics.DeliveryDuration.WithLabelValues(class).Observe(elapsed.Seconds())
 
-	if err := s.repo.CreateDeliveryAttempt(ctx, &attempt); err != nil {
-		s.logger.WithError(err).WithField("delivery_id", deliveryID).Error("Failed to create delivery attempt record")
+	return result
+}
+
+// publishDeliveryEvent publishes a delivery state change to stream subscribers.
+// Failures are logged only, delivery must not depend on the event stream.
+func (s *WebhookService) publishDeliveryEvent(ctx context.Context, eventType string, delivery *models.WebhookDelivery, attempt *models.DeliveryAttempt) {
+	event := models.DeliveryEvent{
+		ID:             uuid.New(),
+		Type:           eventType,
+		DeliveryID:     delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		RetryCount:     delivery.RetryCount,
+		NextRetryAt:    delivery.NextRetryAt,
+		Timestamp:      time.Now(),
+	}
+	if attempt != nil {
+		event.AttemptNumber = attempt.AttemptNumber
+		event.StatusCode = attempt.StatusCode
+		event.ErrorDetails = attempt.ErrorDetails
 	}
 
-	s.logger.WithFields(logrus.Fields{
-		"delivery_id": deliveryID,
-		"status_code": resp.StatusCode,
-		"attempt":     attempt.AttemptNumber,
-	}).Warn("Webhook delivery failed")
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"delivery_id": delivery.ID,
+			"event":       eventType,
+		}).Warn("Failed to publish delivery event")
+	}
+}
 
-	return s.handleDeliveryFailure(ctx, delivery, fmt.Errorf("HTTP %d", resp.StatusCode), &resp.StatusCode)
+// StreamDeliveryEvents streams delivery events for a subscription until ctx is done
+func (s *WebhookService) StreamDeliveryEvents(ctx context.Context, subscriptionID uuid.UUID) (<-chan models.DeliveryEvent, error) {
+	stream, err := s.events.Subscribe(ctx, subscriptionID)
+	if err != nil {
+		s.logger.WithError(err).WithField("subscription_id", subscriptionID).Error("Failed to subscribe to delivery events")
+		return nil, err
+	}
+	return stream, nil
+}
+
+// attemptTime returns the creation time of a new attempt of a delivery. Attempts are
+// looked up from their delivery's creation time on, so an attempt is never dated
+// earlier, even when the clocks of the API and the worker disagree.
+func attemptTime(delivery *models.WebhookDelivery) time.Time {
+	now := time.Now()
+	if now.Before(delivery.CreatedAt) {
+		return delivery.CreatedAt
+	}
+	return now
 }
 
 // handleDeliveryFailure handles the failure of a webhook delivery
@@ -416,6 +927,7 @@ func (s *WebhookService) handleDeliveryFailure(ctx context.Context, delivery *mo
 	// Check if max retries reached
 	if delivery.RetryCount >= delivery.MaxRetries {
 		s.logger.WithField("delivery_id", delivery.ID).Info("Max retries reached, marking as failed")
+		metrics.DeadLettered.Inc()
 
 		delivery.Status = models.StatusFailed
 		delivery.NextRetryAt = nil
@@ -424,14 +936,20 @@ func (s *WebhookService) handleDeliveryFailure(ctx context.Context, delivery *mo
 			s.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to update webhook delivery status to failed")
 			return err
 		}
+		s.publishDeliveryEvent(ctx, models.EventDeliveryDeadLettered, delivery, nil)
 
 		return nil
 	}
 
-	// Calculate next retry time with exponential backoff
+	// Calculate next retry time with exponential backoff. Replays extend MaxRetries, so
+	// count retries from the start of the current retry budget.
+	retry := delivery.RetryCount - (delivery.MaxRetries - s.config.RetryLimit)
+	if retry < 1 {
+		retry = 1
+	}
 	var delay time.Duration
-	if delivery.RetryCount <= len(s.config.RetryDelays) {
-		delay = s.config.RetryDelays[delivery.RetryCount-1]
+	if retry <= len(s.config.RetryDelays) {
+		delay = s.config.RetryDelays[retry-1]
 	} else {
 		delay = s.config.RetryDelays[len(s.config.RetryDelays)-1]
 	}
@@ -446,13 +964,15 @@ func (s *WebhookService) handleDeliveryFailure(ctx context.Context, delivery *mo
 	}
 
 	// Enqueue the task for the next retry
-	task := asynq.NewTask("webhook:deliver", []byte(delivery.ID.String()))
-	_, err = s.taskClient.Enqueue(task, asynq.ProcessIn(delay))
-	if err != nil {
+	if err := s.enqueueDelivery(ctx, delivery, asynq.ProcessIn(delay)); err != nil {
 		s.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to enqueue webhook delivery retry task")
+		metrics.EnqueueFailures.WithLabelValues("retry").Inc()
 		return err
 	}
 
+	metrics.RetriesScheduled.Inc()
+	s.publishDeliveryEvent(ctx, models.EventDeliveryRetryScheduled, delivery, nil)
+
 	s.logger.WithFields(logrus.Fields{
 		"delivery_id": delivery.ID,
 		"retry_count": delivery.RetryCount,
@@ -462,16 +982,178 @@ func (s *WebhookService) handleDeliveryFailure(ctx context.Context, delivery *mo
 	return nil
 }
 
-// CleanupOldLogs deletes logs older than the retention period
+// ReplayDelivery queues a failed delivery for delivery again
+func (s *WebhookService) ReplayDelivery(ctx context.Context, id uuid.UUID) (models.WebhookDelivery, error) {
+	delivery, err := s.repo.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).WithField("delivery_id", id).Error("Failed to get webhook delivery for replay")
+		return models.WebhookDelivery{}, err
+	}
+
+	if err := s.replay(ctx, delivery); err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	s.recordAudit(ctx, models.AuditDeliveryReplayed, models.AuditResourceDelivery, id.String(),
+		auditedDelivery{Status: models.StatusFailed}, auditedDelivery{Status: delivery.Status}, nil)
+
+	s.logger.WithField("delivery_id", id).Info("Webhook delivery replayed")
+	return *delivery, nil
+}
+
+// ReplayDeliveries queues the failed deliveries matching a filter for delivery again
+func (s *WebhookService) ReplayDeliveries(ctx context.Context, filter models.ReplayRequest) ([]models.WebhookDelivery, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultReplayLimit
+	}
+
+	failed, err := s.repo.ListFailedDeliveries(ctx, filter)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list failed deliveries for replay")
+		return nil, err
+	}
+
+	replayed := make([]models.WebhookDelivery, 0, len(failed))
+	for i := range failed {
+		err := s.replay(ctx, &failed[i])
+		if errors.Is(err, ErrNotReplayable) {
+			// Replayed by a concurrent request since it was listed
+			continue
+		}
+		if err != nil {
+			return replayed, err
+		}
+		replayed = append(replayed, failed[i])
+	}
+
+	if len(replayed) > 0 {
+		ids := make([]uuid.UUID, len(replayed))
+		for i := range replayed {
+			ids[i] = replayed[i].ID
+		}
+		s.recordAudit(ctx, models.AuditDeliveriesReplayed, models.AuditResourceDelivery, "", nil, nil, map[string]interface{}{
+			"filter":       filter,
+			"delivery_ids": ids,
+		})
+	}
+
+	s.logger.WithField("delivery_count", len(replayed)).Info("Webhook deliveries replayed")
+	return replayed, nil
+}
+
+// replay moves a failed delivery back to pending with a fresh retry budget and
+// enqueues it. Attempt numbers continue from the earlier attempts.
+func (s *WebhookService) replay(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.Status != models.StatusFailed {
+		return ErrNotReplayable
+	}
+	if delivery.PayloadPurgedAt != nil {
+		return ErrPayloadPurged
+	}
+
+	maxRetries := delivery.RetryCount + s.config.RetryLimit
+	requeued, err := s.repo.RequeueFailedDelivery(ctx, delivery.ID, delivery.CreatedAt, maxRetries)
+	if err != nil {
+		s.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to requeue webhook delivery")
+		return err
+	}
+	if !requeued {
+		return ErrNotReplayable
+	}
+
+	delivery.Status = models.StatusPending
+	delivery.NextRetryAt = nil
+	delivery.MaxRetries = maxRetries
+
+	if err := s.enqueueDelivery(ctx, delivery); err != nil {
+		s.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to enqueue replayed webhook delivery task")
+		metrics.EnqueueFailures.WithLabelValues("replay").Inc()
+		return err
+	}
+
+	s.publishDeliveryEvent(ctx, models.EventDeliveryReplayed, delivery, nil)
+	return nil
+}
+
+// statsCutoff returns the start of the oldest hour whose delivery attempts are still
+// fully retained. Buckets before it can only be served from the rollup table.
+func (s *WebhookService) statsCutoff(now time.Time) time.Time {
+	return now.Add(-time.Duration(s.config.LogRetentionHours) * time.Hour).Truncate(time.Hour).Add(time.Hour)
+}
+
+// RollupDeliveryStats aggregates every completed hour that is still fully retained into
+// the hourly stats table. Buckets are recomputed on each run, so it is safe to repeat.
+// Retention keeps the deliveries and attempts of those hours until they are past the
+// cutoff, so a bucket never loses rows between runs.
+func (s *WebhookService) RollupDeliveryStats(ctx context.Context) error {
+	now := time.Now()
+	from := s.statsCutoff(now)
+	to := now.Truncate(time.Hour)
+
+	if !from.Before(to) {
+		return nil
+	}
+
+	count, err := s.repo.RollupDeliveryStats(ctx, from, to)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to roll up delivery stats")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"bucket_count": count,
+		"from":         from,
+		"to":           to,
+	}).Info("Delivery stats rolled up")
+	return nil
+}
+
+// CleanupOldLogs drops the partitions of delivery attempts older than the log
+// retention period and of deliveries past their retention, then applies the delivery
+// retention policy to the deliveries left
 func (s *WebhookService) CleanupOldLogs(ctx context.Context) error {
-	cutoff := time.Now().Add(-time.Duration(s.config.LogRetentionHours) * time.Hour)
+	// Roll up the hours that are about to lose their attempts first, otherwise
+	// their history is gone for good
+	if err := s.RollupDeliveryStats(ctx); err != nil {
+		return err
+	}
+
+	if err := s.DropExpiredPartitions(ctx); err != nil {
+		return err
+	}
+
+	return s.ApplyRetention(ctx)
+}
+
+// reencrypter is implemented by repositories that encrypt data at rest
+type reencrypter interface {
+	Reencrypt(ctx context.Context, batchSize int) (repository.ReencryptResult, error)
+}
+
+// EncryptsAtRest reports whether the repository encrypts secrets and payloads at rest
+func (s *WebhookService) EncryptsAtRest() bool {
+	_, ok := s.repo.(reencrypter)
+	return ok
+}
 
-	count, err := s.repo.DeleteOldDeliveryAttempts(ctx, cutoff)
+// ReencryptAtRest re-wraps data keys after a master key rotation and encrypts secrets
+// and payloads written before encryption was enabled
+func (s *WebhookService) ReencryptAtRest(ctx context.Context) error {
+	r, ok := s.repo.(reencrypter)
+	if !ok {
+		return nil
+	}
+
+	result, err := r.Reencrypt(ctx, reencryptBatchSize)
+	fields := logrus.Fields{
+		"data_keys_rewrapped": result.DataKeysRewrapped,
+		"secrets_encrypted":   result.SecretsEncrypted,
+		"payloads_encrypted":  result.PayloadsEncrypted,
+	}
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to delete old delivery attempts")
+		s.logger.WithError(err).WithFields(fields).Error("Failed to re-encrypt data at rest")
 		return err
 	}
 
-	s.logger.WithField("deleted_count", count).Info("Old delivery attempts cleaned up")
+	s.logger.WithFields(fields).Info("Data at rest re-encrypted")
 	return nil
 }
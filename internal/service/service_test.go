@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseDeliveryTask(t *testing.T) {
+	id := uuid.New()
+	createdAt := time.Date(2026, 10, 19, 9, 30, 0, 123456000, time.UTC)
+
+	current, err := json.Marshal(deliveryTaskPayload{DeliveryID: id, CreatedAt: createdAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		payload       []byte
+		wantCreatedAt time.Time
+		wantErr       bool
+	}{
+		{name: "with creation time", payload: current, wantCreatedAt: createdAt},
+		// Tasks enqueued before the creation time was carried
+		{name: "without creation time", payload: []byte(`{"delivery_id":"` + id.String() + `"}`)},
+		// Tasks enqueued before trace propagation
+		{name: "bare delivery id", payload: []byte(id.String())},
+		{name: "invalid", payload: []byte("not a delivery"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, gotID, gotCreatedAt, err := ParseDeliveryTask(context.Background(), tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDeliveryTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotID != id {
+				t.Errorf("delivery ID = %s, want %s", gotID, id)
+			}
+			if !gotCreatedAt.Equal(tt.wantCreatedAt) {
+				t.Errorf("created at = %v, want %v", gotCreatedAt, tt.wantCreatedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Package signing holds the Ed25519 key pairs deliveries of the ed25519 signature
+// scheme are signed with.
+package signing
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Unic-X/webhook-delivery/internal/config"
+	"github.com/Unic-X/webhook-delivery/pkg/signature"
+)
+
+// Key is a named Ed25519 key pair
+type Key struct {
+	ID      string
+	Private ed25519.PrivateKey
+}
+
+// Keyring holds the signing keys. The first key is current, the others are previous
+// keys kept during a rotation. Deliveries are signed with every key, so consumers can
+// verify with whichever published key they have.
+type Keyring struct {
+	keys []Key
+}
+
+// LoadKeyring loads the signing keys from SIGNING_KEYS or SIGNING_KEY_FILE. It
+// returns nil when neither is set, which leaves the ed25519 scheme disabled.
+func LoadKeyring(cfg *config.Config) (*Keyring, error) {
+	switch {
+	case len(cfg.SigningKeys) > 0 && cfg.SigningKeyFile != "":
+		return nil, errors.New("set only one of SIGNING_KEYS and SIGNING_KEY_FILE")
+	case len(cfg.SigningKeys) > 0:
+		return ParseKeyring(cfg.SigningKeys)
+	case cfg.SigningKeyFile != "":
+		return readKeyFile(cfg.SigningKeyFile)
+	default:
+		return nil, nil
+	}
+}
+
+// ParseKeyring parses signing keys in the form "<id>:<base64 seed>", where the seed
+// is the 32 byte Ed25519 private key seed. The first entry is the current key.
+func ParseKeyring(entries []string) (*Keyring, error) {
+	if len(entries) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+
+	k := &Keyring{keys: make([]Key, 0, len(entries))}
+	seen := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("signing key %d: expected <id>:<base64 seed>", i+1)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("signing key %q is listed twice", id)
+		}
+		seen[id] = true
+
+		seed, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %q: %w", id, err)
+		}
+		if len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("signing key %q must be %d bytes, got %d", id, ed25519.SeedSize, len(seed))
+		}
+
+		k.keys = append(k.keys, Key{ID: id, Private: ed25519.NewKeyFromSeed(seed)})
+	}
+	return k, nil
+}
+
+// readKeyFile reads a key file with one "<id>:<base64 seed>" entry per line. Blank
+// lines and lines starting with # are ignored.
+func readKeyFile(path string) (*Keyring, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open signing key file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read signing key file: %w", err)
+	}
+	return ParseKeyring(entries)
+}
+
+// PrivateKeys returns the private keys, the current key first. A nil keyring has no
+// keys.
+func (k *Keyring) PrivateKeys() []ed25519.PrivateKey {
+	if k == nil {
+		return nil
+	}
+	keys := make([]ed25519.PrivateKey, len(k.keys))
+	for i, key := range k.keys {
+		keys[i] = key.Private
+	}
+	return keys
+}
+
+// JWKS returns the public keys as a JSON Web Key Set. A nil keyring has no keys.
+func (k *Keyring) JWKS() signature.JWKS {
+	jwks := signature.JWKS{Keys: []signature.JWK{}}
+	if k == nil {
+		return jwks
+	}
+	for i, key := range k.keys {
+		status := signature.KeyStatusPrevious
+		if i == 0 {
+			status = signature.KeyStatusCurrent
+		}
+		jwks.Keys = append(jwks.Keys, signature.NewJWK(key.ID, key.Private.Public().(ed25519.PublicKey), status))
+	}
+	return jwks
+}
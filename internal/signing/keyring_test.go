@@ -0,0 +1,180 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Unic-X/webhook-delivery/internal/config"
+	"github.com/Unic-X/webhook-delivery/pkg/signature"
+)
+
+// The key of test 1 in RFC 8032, section 7.1, and its public key as in RFC 8037,
+// appendix A.2
+const (
+	rfcSeed = "nWGxne/9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A="
+	rfcX    = "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+)
+
+// seedEntry returns a keyring entry for a new random seed
+func seedEntry(t *testing.T, id string) string {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id + ":" + base64.StdEncoding.EncodeToString(key.Seed())
+}
+
+func TestParseKeyring(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		wantIDs []string
+		wantErr bool
+	}{
+		{name: "single key", entries: []string{"k1:" + rfcSeed}, wantIDs: []string{"k1"}},
+		{name: "rotation keeps the order", entries: []string{seedEntry(t, "k2"), "k1:" + rfcSeed}, wantIDs: []string{"k2", "k1"}},
+		{name: "no keys", wantErr: true},
+		{name: "missing separator", entries: []string{rfcSeed}, wantErr: true},
+		{name: "empty id", entries: []string{":" + rfcSeed}, wantErr: true},
+		{name: "duplicate id", entries: []string{"k1:" + rfcSeed, seedEntry(t, "k1")}, wantErr: true},
+		{name: "invalid base64", entries: []string{"k1:not base64!"}, wantErr: true},
+		// A full 64 byte private key instead of the seed
+		{name: "private key instead of seed", entries: []string{"k1:" + base64.StdEncoding.EncodeToString(make([]byte, ed25519.PrivateKeySize))}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := ParseKeyring(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKeyring() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			jwks := k.JWKS()
+			if len(jwks.Keys) != len(tt.wantIDs) {
+				t.Fatalf("JWKS() has %d keys, want %d", len(jwks.Keys), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if jwks.Keys[i].KeyID != id {
+					t.Errorf("key %d has ID %q, want %q", i, jwks.Keys[i].KeyID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadKeyringFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signing-keys")
+	contents := "# current\nk2:" + rfcSeed + "\n\n# previous\n" + seedEntry(t, "k1") + "\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := LoadKeyring(&config.Config{SigningKeyFile: path})
+	if err != nil {
+		t.Fatalf("LoadKeyring() error = %v", err)
+	}
+	if keys := k.JWKS().Keys; len(keys) != 2 || keys[0].KeyID != "k2" || keys[0].X != rfcX {
+		t.Fatalf("JWKS() = %+v, want k2 with the RFC 8032 key first", keys)
+	}
+
+	if k, err := LoadKeyring(&config.Config{}); k != nil || err != nil {
+		t.Fatalf("LoadKeyring() without keys = %v, %v, want nil, nil", k, err)
+	}
+	if _, err := LoadKeyring(&config.Config{SigningKeys: []string{"k1:" + rfcSeed}, SigningKeyFile: path}); err == nil {
+		t.Fatal("LoadKeyring() with keys and a key file succeeded, want an error")
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	k, err := ParseKeyring([]string{"2026-10:" + rfcSeed, seedEntry(t, "2026-04")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := k.JWKS()
+	want := []struct {
+		id     string
+		status string
+	}{
+		{id: "2026-10", status: signature.KeyStatusCurrent},
+		{id: "2026-04", status: signature.KeyStatusPrevious},
+	}
+	if len(jwks.Keys) != len(want) {
+		t.Fatalf("JWKS() has %d keys, want %d", len(jwks.Keys), len(want))
+	}
+	for i, w := range want {
+		key := jwks.Keys[i]
+		if key.KeyID != w.id || key.Status != w.status || key.KeyType != "OKP" || key.Curve != "Ed25519" || key.Algorithm != "EdDSA" {
+			t.Errorf("key %d = %+v, want %s with status %s", i, key, w.id, w.status)
+		}
+	}
+	if jwks.Keys[0].X != rfcX {
+		t.Errorf("current key x = %q, want %q", jwks.Keys[0].X, rfcX)
+	}
+
+	var nilKeyring *Keyring
+	if keys := nilKeyring.JWKS().Keys; keys == nil || len(keys) != 0 {
+		t.Fatalf("JWKS() of a nil keyring = %v, want an empty set", keys)
+	}
+	if keys := nilKeyring.PrivateKeys(); keys != nil {
+		t.Fatalf("PrivateKeys() of a nil keyring = %v, want nil", keys)
+	}
+}
+
+// TestRotation signs a delivery the way the service does during a rotation, with
+// every key, and checks that consumers holding either published key verify it
+func TestRotation(t *testing.T) {
+	k, err := ParseKeyring([]string{seedEntry(t, "next"), "current:" + rfcSeed})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := "msg_p5jXN8AQM9LWM0D4loKWxJek"
+	now := time.Unix(1614265330, 0)
+	body := []byte(`{"test": 2432232314}`)
+
+	var signatures []string
+	for _, key := range k.PrivateKeys() {
+		signatures = append(signatures, signature.Ed25519(key, id, now, body))
+	}
+	if len(signatures) != 2 {
+		t.Fatalf("signed with %d keys, want 2", len(signatures))
+	}
+	// The RFC 8032 key is listed second, so its signature is the known one
+	if want := "v1a,fldxM4gAKugP6nnt1hdz3sgGfZ6d99nzrMFnZOELIxbzEHoVmAb2ADpkJK7zgPePmPsle0zV9jSeGlHFG2NVAw=="; signatures[1] != want {
+		t.Fatalf("signature of the previous key = %q, want %q", signatures[1], want)
+	}
+
+	h := http.Header{}
+	h.Set(signature.IDHeader, id)
+	h.Set(signature.TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	h.Set(signature.SignatureHeader, strings.Join(signatures, " "))
+
+	published, err := k.JWKS().PublicKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range published {
+		if err := signature.VerifyEd25519(h, body, now, 0, key); err != nil {
+			t.Errorf("VerifyEd25519() with published key %d error = %v", i, err)
+		}
+	}
+
+	_, other, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := signature.VerifyEd25519(h, body, now, 0, other.Public().(ed25519.PublicKey)); err == nil {
+		t.Fatal("VerifyEd25519() with an unpublished key succeeded, want an error")
+	}
+}
@@ -3,12 +3,14 @@ package worker
 import (
 	"context"
 
-	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/Unic-X/webhook-delivery/internal/config"
 	"github.com/Unic-X/webhook-delivery/internal/service"
+	"github.com/Unic-X/webhook-delivery/internal/tracing"
 )
 
 // Worker handles background tasks
@@ -48,6 +50,15 @@ func (w *Worker) Start() error {
 	mux := asynq.NewServeMux()
 	mux.HandleFunc("webhook:deliver", w.handleWebhookDelivery)
 	mux.HandleFunc("cleanup:old_logs", w.handleCleanupOldLogs)
+	mux.HandleFunc("stats:rollup", w.handleStatsRollup)
+	mux.HandleFunc("encryption:reencrypt", w.handleReencrypt)
+	mux.HandleFunc("secrets:expire", w.handleExpireSecrets)
+	mux.HandleFunc("partitions:create", w.handleCreatePartitions)
+
+	// Create the partitions ahead right away, the default partition holds rows until then
+	if err := w.service.CreatePartitions(context.Background()); err != nil {
+		w.logger.WithError(err).Error("Failed to create partitions")
+	}
 
 	// Set up periodic task for log cleanup
 	scheduler := asynq.NewScheduler(
@@ -65,6 +76,32 @@ func (w *Worker) Start() error {
 		return err
 	}
 
+	// Schedule delivery stats rollup to run every 15 minutes
+	if _, err := scheduler.Register("@every 15m", asynq.NewTask("stats:rollup", nil)); err != nil {
+		w.logger.WithError(err).Error("Failed to register stats rollup task")
+		return err
+	}
+
+	// Schedule removal of previous secrets whose rotation overlap ended to run every hour
+	if _, err := scheduler.Register("@every 1h", asynq.NewTask("secrets:expire", nil)); err != nil {
+		w.logger.WithError(err).Error("Failed to register secret expiry task")
+		return err
+	}
+
+	// Schedule creation of future delivery partitions to run every hour
+	if _, err := scheduler.Register("@every 1h", asynq.NewTask("partitions:create", nil)); err != nil {
+		w.logger.WithError(err).Error("Failed to register partition creation task")
+		return err
+	}
+
+	// Schedule re-encryption of data at rest to run every 15 minutes when it is enabled
+	if w.service.EncryptsAtRest() {
+		if _, err := scheduler.Register("@every 15m", asynq.NewTask("encryption:reencrypt", nil)); err != nil {
+			w.logger.WithError(err).Error("Failed to register re-encryption task")
+			return err
+		}
+	}
+
 	// Start the scheduler
 	go func() {
 		if err := scheduler.Run(); err != nil {
@@ -85,14 +122,24 @@ func (w *Worker) Shutdown() {
 
 // handleWebhookDelivery handles the webhook delivery task
 func (w *Worker) handleWebhookDelivery(ctx context.Context, task *asynq.Task) error {
-	deliveryID, err := uuid.Parse(string(task.Payload()))
+	ctx, deliveryID, createdAt, err := service.ParseDeliveryTask(ctx, task.Payload())
 	if err != nil {
 		w.logger.WithError(err).Error("Invalid delivery ID in task payload")
 		return err
 	}
 
+	ctx, span := tracing.Tracer().Start(ctx, "asynq.process webhook:deliver",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("webhook.delivery_id", deliveryID.String())),
+	)
+	defer span.End()
+
 	w.logger.WithField("delivery_id", deliveryID).Info("Processing webhook delivery")
-	return w.service.DeliverWebhook(ctx, deliveryID)
+	if err := w.service.DeliverWebhook(ctx, deliveryID, createdAt); err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
 }
 
 // handleCleanupOldLogs handles the log cleanup task
@@ -100,3 +147,27 @@ func (w *Worker) handleCleanupOldLogs(ctx context.Context, _ *asynq.Task) error
 	w.logger.Info("Running log cleanup task")
 	return w.service.CleanupOldLogs(ctx)
 }
+
+// handleStatsRollup handles the delivery stats rollup task
+func (w *Worker) handleStatsRollup(ctx context.Context, _ *asynq.Task) error {
+	w.logger.Info("Running delivery stats rollup task")
+	return w.service.RollupDeliveryStats(ctx)
+}
+
+// handleReencrypt handles the re-encryption task
+func (w *Worker) handleReencrypt(ctx context.Context, _ *asynq.Task) error {
+	w.logger.Info("Running re-encryption task")
+	return w.service.ReencryptAtRest(ctx)
+}
+
+// handleExpireSecrets handles the secret expiry task
+func (w *Worker) handleExpireSecrets(ctx context.Context, _ *asynq.Task) error {
+	w.logger.Info("Running secret expiry task")
+	return w.service.ExpirePreviousSecrets(ctx)
+}
+
+// handleCreatePartitions handles the partition creation task
+func (w *Worker) handleCreatePartitions(ctx context.Context, _ *asynq.Task) error {
+	w.logger.Info("Running partition creation task")
+	return w.service.CreatePartitions(ctx)
+}
@@ -0,0 +1,5 @@
+UPDATE subscriptions SET signature_scheme = 'standard_webhooks' WHERE signature_scheme = 'ed25519';
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_signature_scheme_check;
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_signature_scheme_check CHECK (signature_scheme IN ('github', 'standard_webhooks'));
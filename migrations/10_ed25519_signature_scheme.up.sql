@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_signature_scheme_check;
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_signature_scheme_check CHECK (signature_scheme IN ('github', 'standard_webhooks', 'ed25519'));
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_scheduled;
+
+-- Scheduled deliveries still fire from their queued tasks, cancelled ones are
+-- kept as failed
+UPDATE webhook_deliveries SET status = 'PENDING' WHERE status = 'SCHEDULED';
+UPDATE webhook_deliveries SET status = 'FAILED' WHERE status = 'CANCELLED';
+
+ALTER TABLE webhook_deliveries DROP CONSTRAINT IF EXISTS webhook_deliveries_status_check;
+ALTER TABLE webhook_deliveries ADD CONSTRAINT webhook_deliveries_status_check
+    CHECK (status IN ('PENDING', 'PROCESSING', 'DELIVERED', 'FAILED'));
@@ -0,0 +1,7 @@
+ALTER TABLE webhook_deliveries DROP CONSTRAINT IF EXISTS webhook_deliveries_status_check;
+ALTER TABLE webhook_deliveries ADD CONSTRAINT webhook_deliveries_status_check
+    CHECK (status IN ('PENDING', 'PROCESSING', 'DELIVERED', 'FAILED', 'SCHEDULED', 'CANCELLED'));
+
+-- Scheduled deliveries are listed in the order they fire
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_scheduled ON webhook_deliveries(next_retry_at)
+    WHERE status = 'SCHEDULED';
@@ -0,0 +1,14 @@
+-- Expired deliveries and their attempts are kept as failed
+UPDATE delivery_attempts SET status = 'FAILED' WHERE status = 'EXPIRED';
+UPDATE webhook_deliveries SET status = 'FAILED' WHERE status = 'EXPIRED';
+
+ALTER TABLE delivery_attempts DROP CONSTRAINT IF EXISTS delivery_attempts_status_check;
+ALTER TABLE delivery_attempts ADD CONSTRAINT delivery_attempts_status_check
+    CHECK (status IN ('SUCCESS', 'FAILED'));
+
+ALTER TABLE webhook_deliveries DROP CONSTRAINT IF EXISTS webhook_deliveries_status_check;
+ALTER TABLE webhook_deliveries ADD CONSTRAINT webhook_deliveries_status_check
+    CHECK (status IN ('PENDING', 'PROCESSING', 'DELIVERED', 'FAILED', 'SCHEDULED', 'CANCELLED'));
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS max_event_age_seconds;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS expires_at;
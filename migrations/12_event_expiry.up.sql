@@ -0,0 +1,12 @@
+-- Deliveries expire at expires_at, or max_event_age_seconds after they were created
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS max_event_age_seconds INT
+    CONSTRAINT subscriptions_max_event_age_seconds_check CHECK (max_event_age_seconds > 0);
+
+ALTER TABLE webhook_deliveries DROP CONSTRAINT IF EXISTS webhook_deliveries_status_check;
+ALTER TABLE webhook_deliveries ADD CONSTRAINT webhook_deliveries_status_check
+    CHECK (status IN ('PENDING', 'PROCESSING', 'DELIVERED', 'FAILED', 'SCHEDULED', 'CANCELLED', 'EXPIRED'));
+
+ALTER TABLE delivery_attempts DROP CONSTRAINT IF EXISTS delivery_attempts_status_check;
+ALTER TABLE delivery_attempts ADD CONSTRAINT delivery_attempts_status_check
+    CHECK (status IN ('SUCCESS', 'FAILED', 'EXPIRED'));
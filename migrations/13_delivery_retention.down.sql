@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_payload_ref;
+DROP INDEX IF EXISTS idx_webhook_deliveries_status_created_at;
+
+-- Deliveries without a payload cannot be represented without payload_purged_at
+DELETE FROM webhook_deliveries WHERE payload_purged_at IS NOT NULL;
+
+ALTER TABLE webhook_deliveries
+    DROP CONSTRAINT IF EXISTS webhook_deliveries_payload_check,
+    ADD CONSTRAINT webhook_deliveries_payload_check CHECK (
+        num_nonnulls(payload, payload_ciphertext, payload_ref) = 1
+        AND (payload_ref IS NULL OR (payload_sha256 IS NOT NULL AND payload_size IS NOT NULL))
+    ),
+    DROP COLUMN IF EXISTS payload_purged_at;
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS retention_policy;
@@ -0,0 +1,17 @@
+-- Per-subscription overrides of the global delivery retention policy
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS retention_policy JSONB;
+
+-- Deliveries whose payload was stripped by the retention policy have none left
+ALTER TABLE webhook_deliveries
+    ADD COLUMN IF NOT EXISTS payload_purged_at TIMESTAMP WITH TIME ZONE,
+    DROP CONSTRAINT IF EXISTS webhook_deliveries_payload_check,
+    ADD CONSTRAINT webhook_deliveries_payload_check CHECK (
+        num_nonnulls(payload, payload_ciphertext, payload_ref) = CASE WHEN payload_purged_at IS NULL THEN 1 ELSE 0 END
+        AND (payload_ref IS NULL OR (payload_sha256 IS NOT NULL AND payload_size IS NOT NULL))
+    );
+
+-- Retention selects terminal deliveries by status and age, and checks that an
+-- offloaded payload is no longer referenced before deleting its blob
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status_created_at ON webhook_deliveries(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_payload_ref ON webhook_deliveries(payload_ref)
+    WHERE payload_ref IS NOT NULL;
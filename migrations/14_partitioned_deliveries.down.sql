@@ -0,0 +1,35 @@
+-- Copy the rows of the partitioned tables back into plain tables
+CREATE TABLE webhook_deliveries_unpartitioned (LIKE webhook_deliveries INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+INSERT INTO webhook_deliveries_unpartitioned SELECT * FROM webhook_deliveries;
+DROP TABLE webhook_deliveries;
+ALTER TABLE webhook_deliveries_unpartitioned RENAME TO webhook_deliveries;
+
+ALTER TABLE webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_pkey PRIMARY KEY (id),
+    ADD CONSTRAINT webhook_deliveries_subscription_id_fkey
+        FOREIGN KEY (subscription_id) REFERENCES subscriptions(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries(status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_retry_at ON webhook_deliveries(next_retry_at)
+    WHERE status = 'PENDING' AND next_retry_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries(subscription_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_scheduled ON webhook_deliveries(next_retry_at)
+    WHERE status = 'SCHEDULED';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status_created_at ON webhook_deliveries(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_payload_ref ON webhook_deliveries(payload_ref)
+    WHERE payload_ref IS NOT NULL;
+
+-- Attempts of deliveries removed while partitioned have nothing to reference
+CREATE TABLE delivery_attempts_unpartitioned (LIKE delivery_attempts INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+INSERT INTO delivery_attempts_unpartitioned
+    SELECT * FROM delivery_attempts a WHERE EXISTS (SELECT 1 FROM webhook_deliveries d WHERE d.id = a.delivery_id);
+DROP TABLE delivery_attempts;
+ALTER TABLE delivery_attempts_unpartitioned RENAME TO delivery_attempts;
+
+ALTER TABLE delivery_attempts
+    ADD CONSTRAINT delivery_attempts_pkey PRIMARY KEY (id),
+    ADD CONSTRAINT delivery_attempts_delivery_id_fkey
+        FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_delivery_attempts_delivery_id ON delivery_attempts(delivery_id);
+CREATE INDEX IF NOT EXISTS idx_delivery_attempts_created_at ON delivery_attempts(created_at);
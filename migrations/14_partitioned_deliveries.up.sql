@@ -0,0 +1,105 @@
+-- webhook_deliveries and delivery_attempts are range partitioned on created_at, so
+-- retention drops whole partitions instead of deleting rows. The existing tables are
+-- kept in place as the partitions of everything created before tomorrow, so converting
+-- them does not copy any rows. The worker creates the partitions after those.
+
+-- Foreign keys to a partitioned table must include the partition key, attempts of
+-- deleted deliveries are removed by the service instead
+ALTER TABLE delivery_attempts DROP CONSTRAINT IF EXISTS delivery_attempts_delivery_id_fkey;
+
+ALTER TABLE webhook_deliveries RENAME TO webhook_deliveries_legacy;
+ALTER INDEX IF EXISTS webhook_deliveries_pkey RENAME TO webhook_deliveries_legacy_pkey;
+ALTER INDEX IF EXISTS idx_webhook_deliveries_status RENAME TO idx_webhook_deliveries_legacy_status;
+ALTER INDEX IF EXISTS idx_webhook_deliveries_next_retry_at RENAME TO idx_webhook_deliveries_legacy_next_retry_at;
+ALTER INDEX IF EXISTS idx_webhook_deliveries_subscription_id RENAME TO idx_webhook_deliveries_legacy_subscription_id;
+ALTER INDEX IF EXISTS idx_webhook_deliveries_scheduled RENAME TO idx_webhook_deliveries_legacy_scheduled;
+ALTER INDEX IF EXISTS idx_webhook_deliveries_status_created_at RENAME TO idx_webhook_deliveries_legacy_status_created_at;
+ALTER INDEX IF EXISTS idx_webhook_deliveries_payload_ref RENAME TO idx_webhook_deliveries_legacy_payload_ref;
+ALTER TABLE webhook_deliveries_legacy ALTER COLUMN created_at SET NOT NULL;
+
+CREATE TABLE webhook_deliveries (
+    id UUID NOT NULL,
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    payload JSONB,
+    event_type TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    status TEXT NOT NULL DEFAULT 'PENDING',
+    next_retry_at TIMESTAMP WITH TIME ZONE,
+    retry_count INT DEFAULT 0,
+    max_retries INT NOT NULL,
+    payload_ref TEXT,
+    payload_sha256 TEXT,
+    payload_size BIGINT,
+    payload_ciphertext BYTEA,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    payload_purged_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (id, created_at),
+    CONSTRAINT webhook_deliveries_status_check
+        CHECK (status IN ('PENDING', 'PROCESSING', 'DELIVERED', 'FAILED', 'SCHEDULED', 'CANCELLED', 'EXPIRED')),
+    CONSTRAINT webhook_deliveries_payload_check CHECK (
+        num_nonnulls(payload, payload_ciphertext, payload_ref) = CASE WHEN payload_purged_at IS NULL THEN 1 ELSE 0 END
+        AND (payload_ref IS NULL OR (payload_sha256 IS NOT NULL AND payload_size IS NOT NULL))
+    )
+) PARTITION BY RANGE (created_at);
+
+CREATE INDEX idx_webhook_deliveries_status ON webhook_deliveries(status);
+CREATE INDEX idx_webhook_deliveries_next_retry_at ON webhook_deliveries(next_retry_at)
+    WHERE status = 'PENDING' AND next_retry_at IS NOT NULL;
+CREATE INDEX idx_webhook_deliveries_subscription_id ON webhook_deliveries(subscription_id);
+CREATE INDEX idx_webhook_deliveries_scheduled ON webhook_deliveries(next_retry_at)
+    WHERE status = 'SCHEDULED';
+CREATE INDEX idx_webhook_deliveries_status_created_at ON webhook_deliveries(status, created_at);
+CREATE INDEX idx_webhook_deliveries_payload_ref ON webhook_deliveries(payload_ref)
+    WHERE payload_ref IS NOT NULL;
+
+ALTER TABLE delivery_attempts RENAME TO delivery_attempts_legacy;
+ALTER INDEX IF EXISTS delivery_attempts_pkey RENAME TO delivery_attempts_legacy_pkey;
+ALTER INDEX IF EXISTS idx_delivery_attempts_delivery_id RENAME TO idx_delivery_attempts_legacy_delivery_id;
+ALTER INDEX IF EXISTS idx_delivery_attempts_created_at RENAME TO idx_delivery_attempts_legacy_created_at;
+ALTER TABLE delivery_attempts_legacy ALTER COLUMN created_at SET NOT NULL;
+
+CREATE TABLE delivery_attempts (
+    id UUID NOT NULL,
+    delivery_id UUID NOT NULL,
+    attempt_number INT NOT NULL,
+    status TEXT NOT NULL,
+    status_code INT,
+    error_details TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, created_at),
+    CONSTRAINT delivery_attempts_status_check CHECK (status IN ('SUCCESS', 'FAILED', 'EXPIRED'))
+) PARTITION BY RANGE (created_at);
+
+CREATE INDEX idx_delivery_attempts_delivery_id ON delivery_attempts(delivery_id);
+CREATE INDEX idx_delivery_attempts_created_at ON delivery_attempts(created_at);
+
+-- Attach the old tables, or drop them when empty, then add a default partition for
+-- rows no partition covers and daily partitions for the next week
+DO $$
+DECLARE
+    parent TEXT;
+    has_rows BOOLEAN;
+    today TIMESTAMP WITH TIME ZONE := date_trunc('day', NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC';
+    partition_start TIMESTAMP WITH TIME ZONE;
+BEGIN
+    FOREACH parent IN ARRAY ARRAY['webhook_deliveries', 'delivery_attempts'] LOOP
+        partition_start := today;
+        EXECUTE format('SELECT EXISTS (SELECT 1 FROM %I)', parent || '_legacy') INTO has_rows;
+        IF has_rows THEN
+            partition_start := today + INTERVAL '1 day';
+            EXECUTE format('ALTER TABLE %I ATTACH PARTITION %I FOR VALUES FROM (MINVALUE) TO (%L)',
+                parent, parent || '_legacy', partition_start);
+        ELSE
+            EXECUTE format('DROP TABLE %I', parent || '_legacy');
+        END IF;
+
+        EXECUTE format('CREATE TABLE %I PARTITION OF %I DEFAULT', parent || '_default', parent);
+
+        WHILE partition_start < today + INTERVAL '8 days' LOOP
+            EXECUTE format('CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+                parent || '_p' || to_char(partition_start AT TIME ZONE 'UTC', 'YYYYMMDD'), parent,
+                partition_start, partition_start + INTERVAL '1 day');
+            partition_start := partition_start + INTERVAL '1 day';
+        END LOOP;
+    END LOOP;
+END $$;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS delivery_stats_hourly (
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL DEFAULT '',
+    bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    deliveries_total INT NOT NULL DEFAULT 0,
+    deliveries_delivered INT NOT NULL DEFAULT 0,
+    deliveries_failed INT NOT NULL DEFAULT 0,
+    attempts_total INT NOT NULL DEFAULT 0,
+    attempts_succeeded INT NOT NULL DEFAULT 0,
+    attempts_failed INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, event_type, bucket_start)
+);
+
+CREATE INDEX idx_delivery_stats_hourly_bucket_start ON delivery_stats_hourly(bucket_start);
@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_destination_type_check;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS destination_config,
+    DROP COLUMN IF EXISTS destination_type;
@@ -0,0 +1,6 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS destination_type TEXT NOT NULL DEFAULT 'http',
+    ADD COLUMN IF NOT EXISTS destination_config JSONB;
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_destination_type_check CHECK (destination_type IN ('http', 'kafka'));
@@ -0,0 +1,4 @@
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_destination_type_check;
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_destination_type_check CHECK (destination_type IN ('http', 'kafka'));
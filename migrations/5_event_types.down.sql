@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS event_type_versions;
+DROP TABLE IF EXISTS event_types;
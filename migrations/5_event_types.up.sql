@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS event_types (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS event_type_versions (
+    event_type TEXT NOT NULL REFERENCES event_types(name) ON DELETE CASCADE,
+    version INT NOT NULL,
+    schema JSONB NOT NULL,
+    examples JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (event_type, version)
+);
@@ -0,0 +1,9 @@
+-- Offloaded deliveries have no inline payload to fall back to
+DELETE FROM webhook_deliveries WHERE payload IS NULL;
+
+ALTER TABLE webhook_deliveries
+    DROP CONSTRAINT IF EXISTS webhook_deliveries_payload_check,
+    DROP COLUMN IF EXISTS payload_size,
+    DROP COLUMN IF EXISTS payload_sha256,
+    DROP COLUMN IF EXISTS payload_ref,
+    ALTER COLUMN payload SET NOT NULL;
@@ -0,0 +1,10 @@
+-- Large payloads are offloaded to the blob store, the row keeps a reference and hash
+ALTER TABLE webhook_deliveries
+    ALTER COLUMN payload DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS payload_ref TEXT,
+    ADD COLUMN IF NOT EXISTS payload_sha256 TEXT,
+    ADD COLUMN IF NOT EXISTS payload_size BIGINT,
+    ADD CONSTRAINT webhook_deliveries_payload_check CHECK (
+        (payload IS NOT NULL AND payload_ref IS NULL)
+        OR (payload IS NULL AND payload_ref IS NOT NULL AND payload_sha256 IS NOT NULL AND payload_size IS NOT NULL)
+    );
@@ -0,0 +1,15 @@
+-- Encrypted payloads cannot be recovered without the keys, and sealed secret keys
+-- are left as they are
+DELETE FROM webhook_deliveries WHERE payload_ciphertext IS NOT NULL;
+
+ALTER TABLE webhook_deliveries
+    DROP CONSTRAINT IF EXISTS webhook_deliveries_payload_check,
+    DROP COLUMN IF EXISTS payload_ciphertext,
+    ADD CONSTRAINT webhook_deliveries_payload_check CHECK (
+        (payload IS NOT NULL AND payload_ref IS NULL)
+        OR (payload IS NULL AND payload_ref IS NOT NULL AND payload_sha256 IS NOT NULL AND payload_size IS NOT NULL)
+    );
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS data_key_id;
+
+DROP TABLE IF EXISTS data_keys;
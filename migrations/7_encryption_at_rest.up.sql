@@ -0,0 +1,22 @@
+-- Data keys are generated per subscription and stored wrapped by a master key
+CREATE TABLE IF NOT EXISTS data_keys (
+    id UUID PRIMARY KEY,
+    master_key_id TEXT NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    rotated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_data_keys_master_key_id ON data_keys(master_key_id);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS data_key_id UUID REFERENCES data_keys(id);
+
+-- A payload is stored inline, encrypted or offloaded to the blob store
+ALTER TABLE webhook_deliveries
+    ADD COLUMN IF NOT EXISTS payload_ciphertext BYTEA,
+    DROP CONSTRAINT IF EXISTS webhook_deliveries_payload_check,
+    ADD CONSTRAINT webhook_deliveries_payload_check CHECK (
+        num_nonnulls(payload, payload_ciphertext, payload_ref) = 1
+        AND (payload_ref IS NULL OR (payload_sha256 IS NOT NULL AND payload_size IS NOT NULL))
+    );
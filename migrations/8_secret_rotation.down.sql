@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_subscriptions_previous_secret_expires_at;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS previous_secret_expires_at,
+    DROP COLUMN IF EXISTS previous_secret_key;
@@ -0,0 +1,7 @@
+-- The previous signing secret stays valid until it expires after a rotation
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS previous_secret_key TEXT,
+    ADD COLUMN IF NOT EXISTS previous_secret_expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_previous_secret_expires_at
+    ON subscriptions(previous_secret_expires_at) WHERE previous_secret_expires_at IS NOT NULL;
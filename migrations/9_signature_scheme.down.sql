@@ -0,0 +1,4 @@
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_signature_scheme_check;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS signature_scheme;
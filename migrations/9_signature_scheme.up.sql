@@ -0,0 +1,6 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS signature_scheme TEXT NOT NULL DEFAULT 'github';
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_signature_scheme_check;
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_signature_scheme_check CHECK (signature_scheme IN ('github', 'standard_webhooks'));
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListAuditEvents lists the audit log, newest first. Empty filter fields match every
+// event, and a zero limit lists up to 100.
+func (c *Client) ListAuditEvents(ctx context.Context, filter AuditEventsRequest) ([]AuditEvent, error) {
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"action":        filter.Action,
+		"resource_type": filter.ResourceType,
+		"resource_id":   filter.ResourceID,
+		"actor":         filter.Actor,
+		"request_id":    filter.RequestID,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if filter.Since != nil {
+		query.Set("since", filter.Since.Format(time.RFC3339Nano))
+	}
+	if filter.Until != nil {
+		query.Set("until", filter.Until.Format(time.RFC3339Nano))
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	path := "/audit"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var events []AuditEvent
+	err := c.do(ctx, request{method: http.MethodGet, path: path}, &events)
+	return events, err
+}
@@ -0,0 +1,254 @@
+// Package client is a Go client for the webhook delivery service REST API. It covers
+// subscriptions, the event type catalog, ingest and publishing, and delivery status,
+// using the service's own request and response types.
+//
+// Requests that are safe to repeat are retried on network errors, 429 and 5xx
+// responses with exponential backoff, honouring Retry-After. Ingest and publish send
+// an Idempotency-Key, the same on every attempt, so they are retried too without
+// queueing the event twice. Unsuccessful responses are returned as *Error.
+//
+//	c := client.New("http://localhost:8080")
+//	resp, err := c.Publish(ctx, "order.created", order)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Defaults of a new Client
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 200 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// IdempotencyKeyHeader is the header ingest and publish requests carry their
+// idempotency key in
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Client calls the webhook service REST API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	http       *http.Client
+	header     http.Header
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = httpClient
+	}
+}
+
+// WithRetries sets how many times a failed request is retried, zero disables retries
+func WithRetries(maxRetries int) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+	}
+}
+
+// WithBackoff sets the delay before the first retry and the cap on the delay, which
+// doubles on every retry
+func WithBackoff(min, max time.Duration) Option {
+	return func(c *Client) {
+		c.minBackoff = min
+		c.maxBackoff = max
+	}
+}
+
+// WithHeader adds a header to every request, e.g. for an authenticating proxy
+func WithHeader(name, value string) Option {
+	return func(c *Client) {
+		c.header.Add(name, value)
+	}
+}
+
+// New creates a new Client for the service at baseURL
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		http:       &http.Client{Timeout: DefaultTimeout},
+		header:     make(http.Header),
+		maxRetries: DefaultMaxRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a context that makes ingest and publish use key as their
+// Idempotency-Key instead of a random one. Deriving the key from the producer's own
+// event ID makes the request safe to repeat even across restarts.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// idempotencyKeyFrom returns the context's idempotency key or a new random one
+func idempotencyKeyFrom(ctx context.Context) string {
+	if key, ok := ctx.Value(idempotencyKey{}).(string); ok && key != "" {
+		return key
+	}
+	return uuid.NewString()
+}
+
+// request is an API request
+type request struct {
+	method string
+	path   string
+	header http.Header
+	body   interface{}
+	// idempotent requests are retried
+	idempotent bool
+}
+
+// do sends a request, retrying it when it is idempotent, and decodes the JSON
+// response into out, if given
+func (c *Client) do(ctx context.Context, r request, out interface{}) error {
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return err
+		}
+	}
+
+	retryable := r.idempotent || r.method == http.MethodGet || r.method == http.MethodPut || r.method == http.MethodDelete
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, r, body)
+		if err == nil && resp.StatusCode < 300 {
+			defer resp.Body.Close()
+			if out == nil || resp.StatusCode == http.StatusNoContent {
+				return nil
+			}
+			return json.NewDecoder(resp.Body).Decode(out)
+		}
+
+		var retryAfter time.Duration
+		if err == nil {
+			apiErr := responseError(resp)
+			resp.Body.Close()
+			err = apiErr
+			retryAfter = apiErr.RetryAfter
+			if !retryableStatus(apiErr.StatusCode, r.idempotent) {
+				return err
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if !retryable || attempt >= c.maxRetries {
+			return err
+		}
+		if err := sleep(ctx, c.backoff(attempt, retryAfter)); err != nil {
+			return err
+		}
+	}
+}
+
+// send sends one attempt of a request
+func (c *Client) send(ctx context.Context, r request, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, c.baseURL+r.path, reader)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	return c.http.Do(req)
+}
+
+// retryableStatus reports whether a response status is worth retrying. 409 is only
+// retried for requests with an idempotency key, where it means the first attempt is
+// still being handled.
+func retryableStatus(status int, idempotent bool) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before a retry: Retry-After when the server sent one,
+// otherwise a random delay up to the exponential backoff
+func (c *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	delay := c.maxBackoff
+	if attempt < 30 && c.minBackoff<<attempt < c.maxBackoff {
+		delay = c.minBackoff << attempt
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// errNoID is returned for a zero ID, which would address a collection instead
+var errNoID = errors.New("client: ID is required")
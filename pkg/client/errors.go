@@ -0,0 +1,89 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Errors an *Error matches with errors.Is, by status code
+var (
+	ErrBadRequest      = errors.New("client: bad request")
+	ErrUnauthorized    = errors.New("client: unauthorized")
+	ErrForbidden       = errors.New("client: forbidden")
+	ErrNotFound        = errors.New("client: not found")
+	ErrConflict        = errors.New("client: conflict")
+	ErrPayloadTooLarge = errors.New("client: payload too large")
+	// ErrUnprocessable is matched when a payload does not match its event type's
+	// schema, see Error.Violations, or an idempotency key is reused for a different
+	// request
+	ErrUnprocessable = errors.New("client: unprocessable request")
+)
+
+// statusErrors maps status codes to the errors they match
+var statusErrors = map[int]error{
+	http.StatusBadRequest:            ErrBadRequest,
+	http.StatusUnauthorized:          ErrUnauthorized,
+	http.StatusForbidden:             ErrForbidden,
+	http.StatusNotFound:              ErrNotFound,
+	http.StatusConflict:              ErrConflict,
+	http.StatusRequestEntityTooLarge: ErrPayloadTooLarge,
+	http.StatusUnprocessableEntity:   ErrUnprocessable,
+}
+
+// maxErrorBodySize caps how much of an error response is read
+const maxErrorBodySize = 64 << 10
+
+// Error is an unsuccessful API response. Message is the error of the API's
+// ErrorResponse. Schema validation failures also carry the event type, the schema
+// version and the violations.
+type Error struct {
+	StatusCode int
+	Message    string
+	EventType  string
+	Version    int
+	Violations []SchemaViolation
+	// RetryAfter is the Retry-After the server sent, zero when none
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("webhook api: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Is matches the error of the status code, e.g. errors.Is(err, ErrNotFound)
+func (e *Error) Is(target error) bool {
+	return statusErrors[e.StatusCode] == target
+}
+
+// errorBody is the API's ErrorResponse, with the fields SchemaErrorResponse adds
+type errorBody struct {
+	Error      string            `json:"error"`
+	EventType  string            `json:"event_type"`
+	Version    int               `json:"version"`
+	Violations []SchemaViolation `json:"violations"`
+}
+
+// responseError builds the Error of an unsuccessful response
+func responseError(resp *http.Response) *Error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	var body errorBody
+	if json.Unmarshal(data, &body) == nil && body.Error != "" {
+		apiErr.Message = body.Error
+		apiErr.EventType = body.EventType
+		apiErr.Version = body.Version
+		apiErr.Violations = body.Violations
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	return apiErr
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// errNoName is returned for an empty event type name
+var errNoName = errors.New("client: event type name is required")
+
+// CreateEventType registers an event type with the first version of its schema
+func (c *Client) CreateEventType(ctx context.Context, req EventTypeRequest) (EventType, error) {
+	var eventType EventType
+	err := c.do(ctx, request{method: http.MethodPost, path: "/event-types/", body: req}, &eventType)
+	return eventType, err
+}
+
+// GetEventType gets an event type with all of its schema versions
+func (c *Client) GetEventType(ctx context.Context, name string) (EventType, error) {
+	var eventType EventType
+	if name == "" {
+		return eventType, errNoName
+	}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/event-types/" + url.PathEscape(name)}, &eventType)
+	return eventType, err
+}
+
+// ListEventTypes lists the event type catalog
+func (c *Client) ListEventTypes(ctx context.Context) ([]EventType, error) {
+	var eventTypes []EventType
+	err := c.do(ctx, request{method: http.MethodGet, path: "/event-types/"}, &eventTypes)
+	return eventTypes, err
+}
+
+// AddEventTypeVersion adds a schema version to an event type
+func (c *Client) AddEventTypeVersion(ctx context.Context, name string, req EventTypeVersionRequest) (EventTypeVersion, error) {
+	var version EventTypeVersion
+	if name == "" {
+		return version, errNoName
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/event-types/" + url.PathEscape(name) + "/versions",
+		body:   req,
+	}, &version)
+	return version, err
+}
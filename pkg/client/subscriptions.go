@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+)
+
+// subscriptionPath returns the path of a subscription, or of one of its resources
+func subscriptionPath(id uuid.UUID, resource string) (string, error) {
+	if id == uuid.Nil {
+		return "", errNoID
+	}
+	return "/subscriptions/" + id.String() + resource, nil
+}
+
+// CreateSubscription creates a subscription. The response is the only one that
+// contains the signing secret.
+func (c *Client) CreateSubscription(ctx context.Context, req SubscriptionRequest) (CreatedSubscription, error) {
+	var sub CreatedSubscription
+	err := c.do(ctx, request{method: http.MethodPost, path: "/subscriptions/", body: req}, &sub)
+	return sub, err
+}
+
+// GetSubscription gets a subscription
+func (c *Client) GetSubscription(ctx context.Context, id uuid.UUID) (Subscription, error) {
+	var sub Subscription
+	path, err := subscriptionPath(id, "")
+	if err != nil {
+		return sub, err
+	}
+	err = c.do(ctx, request{method: http.MethodGet, path: path}, &sub)
+	return sub, err
+}
+
+// ListSubscriptions lists all subscriptions
+func (c *Client) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	var subs []Subscription
+	err := c.do(ctx, request{method: http.MethodGet, path: "/subscriptions/"}, &subs)
+	return subs, err
+}
+
+// UpdateSubscription updates a subscription. Empty fields are left unchanged.
+func (c *Client) UpdateSubscription(ctx context.Context, id uuid.UUID, req SubscriptionRequest) (Subscription, error) {
+	var sub Subscription
+	path, err := subscriptionPath(id, "")
+	if err != nil {
+		return sub, err
+	}
+	err = c.do(ctx, request{method: http.MethodPut, path: path, body: req}, &sub)
+	return sub, err
+}
+
+// DeleteSubscription deletes a subscription
+func (c *Client) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	path, err := subscriptionPath(id, "")
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, request{method: http.MethodDelete, path: path}, nil)
+}
+
+// RotateSubscriptionSecret replaces a subscription's signing secret. The response is
+// the only one that contains the new secret.
+func (c *Client) RotateSubscriptionSecret(ctx context.Context, id uuid.UUID, req RotateSecretRequest) (RotateSecretResponse, error) {
+	var resp RotateSecretResponse
+	path, err := subscriptionPath(id, "/secret/rotate")
+	if err != nil {
+		return resp, err
+	}
+	err = c.do(ctx, request{method: http.MethodPost, path: path, body: req}, &resp)
+	return resp, err
+}
+
+// RevealSubscriptionSecret returns a subscription's signing secret. The token is the
+// API's SECRET_REVEAL_TOKEN, and the reason is recorded in its audit log.
+func (c *Client) RevealSubscriptionSecret(ctx context.Context, id uuid.UUID, reason, token string) (RevealSecretResponse, error) {
+	var resp RevealSecretResponse
+	path, err := subscriptionPath(id, "/secret/reveal")
+	if err != nil {
+		return resp, err
+	}
+	header := http.Header{"Authorization": {"Bearer " + token}}
+	err = c.do(ctx, request{
+		method: http.MethodPost,
+		path:   path,
+		header: header,
+		body:   models.RevealSecretRequest{Reason: reason},
+	}, &resp)
+	return resp, err
+}
+
+// RecentDeliveries gets the most recent deliveries of a subscription, 20 when limit
+// is zero
+func (c *Client) RecentDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	path, err := subscriptionPath(subscriptionID, "/deliveries")
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 {
+		path += "?limit=" + strconv.Itoa(limit)
+	}
+	err = c.do(ctx, request{method: http.MethodGet, path: path}, &deliveries)
+	return deliveries, err
+}
+
+// SubscriptionStats gets hourly delivery statistics for a subscription. Zero times
+// default to the last 24 hours.
+func (c *Client) SubscriptionStats(ctx context.Context, subscriptionID uuid.UUID, from, to time.Time) (DeliveryStatsResponse, error) {
+	var stats DeliveryStatsResponse
+	path, err := subscriptionPath(subscriptionID, "/stats")
+	if err != nil {
+		return stats, err
+	}
+	query := url.Values{}
+	if !from.IsZero() {
+		query.Set("from", from.Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		query.Set("to", to.Format(time.RFC3339))
+	}
+	if len(query) > 0 {
+		path = fmt.Sprintf("%s?%s", path, query.Encode())
+	}
+	err = c.do(ctx, request{method: http.MethodGet, path: path}, &stats)
+	return stats, err
+}
@@ -0,0 +1,64 @@
+package client
+
+import "github.com/Unic-X/webhook-delivery/internal/models"
+
+// The API's request and response types. They are the service's own models, aliased
+// so that programs outside this module can name them.
+type (
+	Subscription            = models.Subscription
+	SubscriptionRequest     = models.SubscriptionRequest
+	CreatedSubscription     = models.CreatedSubscription
+	DestinationConfig       = models.DestinationConfig
+	RetentionPolicy         = models.RetentionPolicy
+	RotateSecretRequest     = models.RotateSecretRequest
+	RotateSecretResponse    = models.RotateSecretResponse
+	RevealSecretResponse    = models.RevealSecretResponse
+	WebhookDelivery         = models.WebhookDelivery
+	DeliveryAttempt         = models.DeliveryAttempt
+	ArchivedDelivery        = models.ArchivedDelivery
+	DeliveryEvent           = models.DeliveryEvent
+	DeliveryStatusResponse  = models.DeliveryStatusResponse
+	DeliveryStats           = models.DeliveryStats
+	DeliveryStatsResponse   = models.DeliveryStatsResponse
+	PublishResponse         = models.PublishResponse
+	ReplayRequest           = models.ReplayRequest
+	ReplayResponse          = models.ReplayResponse
+	EventType               = models.EventType
+	EventTypeVersion        = models.EventTypeVersion
+	EventTypeRequest        = models.EventTypeRequest
+	EventTypeVersionRequest = models.EventTypeVersionRequest
+	SchemaViolation         = models.SchemaViolation
+	AuditEvent              = models.AuditEvent
+	AuditEventsRequest      = models.AuditEventsRequest
+)
+
+// Destination types
+const (
+	DestinationHTTP        = models.DestinationHTTP
+	DestinationKafka       = models.DestinationKafka
+	DestinationRedisStream = models.DestinationRedisStream
+)
+
+// Retention modes
+const (
+	RetentionModeDelete       = models.RetentionModeDelete
+	RetentionModeStripPayload = models.RetentionModeStripPayload
+)
+
+// Signature schemes
+const (
+	SignatureSchemeGitHub           = models.SignatureSchemeGitHub
+	SignatureSchemeStandardWebhooks = models.SignatureSchemeStandardWebhooks
+	SignatureSchemeEd25519          = models.SignatureSchemeEd25519
+)
+
+// Delivery statuses
+const (
+	StatusPending    = models.StatusPending
+	StatusProcessing = models.StatusProcessing
+	StatusDelivered  = models.StatusDelivered
+	StatusFailed     = models.StatusFailed
+	StatusScheduled  = models.StatusScheduled
+	StatusCancelled  = models.StatusCancelled
+	StatusExpired    = models.StatusExpired
+)
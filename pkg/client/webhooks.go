@@ -0,0 +1,369 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Unic-X/webhook-delivery/internal/models"
+	"github.com/Unic-X/webhook-delivery/pkg/signature"
+)
+
+// IngestRequest is a webhook ingested for one subscription
+type IngestRequest struct {
+	SubscriptionID uuid.UUID
+	// EventType is matched against the subscription's event types, the webhook is
+	// dropped when it does not match
+	EventType string
+	// Payload is sent as JSON. json.RawMessage and []byte are sent as they are.
+	Payload interface{}
+	// Secret signs the payload with X-Hub-Signature-256 when set
+	Secret string
+	// DeliverAt or Delay schedule the webhook instead of delivering it right away. At
+	// most one may be set.
+	DeliverAt time.Time
+	Delay     time.Duration
+	// ExpiresAt drops the webhook when it could not be delivered by then
+	ExpiresAt time.Time
+}
+
+// Ingest queues a webhook for delivery to one subscription
+func (c *Client) Ingest(ctx context.Context, req IngestRequest) error {
+	if req.SubscriptionID == uuid.Nil {
+		return errNoID
+	}
+	payload, err := marshalPayload(req.Payload)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{IdempotencyKeyHeader: {idempotencyKeyFrom(ctx)}}
+	if req.EventType != "" {
+		header.Set("X-Event-Type", req.EventType)
+	}
+	if req.Secret != "" {
+		header.Set(signature.HubHeader, signature.Hub(req.Secret, payload))
+	}
+
+	return c.do(ctx, request{
+		method:     http.MethodPost,
+		path:       "/webhooks/ingest/" + req.SubscriptionID.String(),
+		header:     header,
+		body:       webhookRequest(payload, req.DeliverAt, req.Delay, req.ExpiresAt),
+		idempotent: true,
+	}, nil)
+}
+
+// PublishRequest is an event published to every subscription that accepts its type
+type PublishRequest struct {
+	EventType string
+	// Payload is sent as JSON. json.RawMessage and []byte are sent as they are.
+	Payload interface{}
+	// DeliverAt or Delay schedule the event instead of delivering it right away. At
+	// most one may be set.
+	DeliverAt time.Time
+	Delay     time.Duration
+	// ExpiresAt drops the event's deliveries that could not be made by then
+	ExpiresAt time.Time
+}
+
+// Publish queues an event for delivery to every subscription that accepts its type.
+// The payload is sent as JSON, json.RawMessage and []byte are sent as they are.
+func (c *Client) Publish(ctx context.Context, eventType string, payload interface{}) (PublishResponse, error) {
+	return c.PublishEvent(ctx, PublishRequest{EventType: eventType, Payload: payload})
+}
+
+// PublishAt publishes an event to be delivered at deliverAt
+func (c *Client) PublishAt(ctx context.Context, eventType string, payload interface{}, deliverAt time.Time) (PublishResponse, error) {
+	return c.PublishEvent(ctx, PublishRequest{EventType: eventType, Payload: payload, DeliverAt: deliverAt})
+}
+
+// PublishAfter publishes an event to be delivered after delay. The delay is counted
+// from when the service receives the request.
+func (c *Client) PublishAfter(ctx context.Context, eventType string, payload interface{}, delay time.Duration) (PublishResponse, error) {
+	return c.PublishEvent(ctx, PublishRequest{EventType: eventType, Payload: payload, Delay: delay})
+}
+
+// PublishEvent publishes an event with all of the request's options
+func (c *Client) PublishEvent(ctx context.Context, req PublishRequest) (PublishResponse, error) {
+	var resp PublishResponse
+	data, err := marshalPayload(req.Payload)
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/webhooks/publish",
+		header: http.Header{
+			"X-Event-Type":       {req.EventType},
+			IdempotencyKeyHeader: {idempotencyKeyFrom(ctx)},
+		},
+		body:       webhookRequest(data, req.DeliverAt, req.Delay, req.ExpiresAt),
+		idempotent: true,
+	}, &resp)
+	return resp, err
+}
+
+// webhookRequest builds the body of an ingest or publish request
+func webhookRequest(payload json.RawMessage, deliverAt time.Time, delay time.Duration, expiresAt time.Time) models.WebhookRequest {
+	req := models.WebhookRequest{Payload: payload}
+	if !deliverAt.IsZero() {
+		req.DeliverAt = &deliverAt
+	}
+	if delay != 0 {
+		req.Delay = delay.String()
+	}
+	if !expiresAt.IsZero() {
+		req.ExpiresAt = &expiresAt
+	}
+	return req
+}
+
+// marshalPayload returns the compacted JSON of a payload. It is compacted so that a
+// signature covers exactly the bytes the API receives.
+func marshalPayload(payload interface{}) (json.RawMessage, error) {
+	var data []byte
+	switch p := payload.(type) {
+	case json.RawMessage:
+		data = p
+	case []byte:
+		data = p
+	default:
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return nil, fmt.Errorf("client: payload is not valid JSON: %w", err)
+	}
+	return compact.Bytes(), nil
+}
+
+// GetDeliveryStatus gets a delivery with its attempts
+func (c *Client) GetDeliveryStatus(ctx context.Context, id uuid.UUID) (DeliveryStatusResponse, error) {
+	var status DeliveryStatusResponse
+	if id == uuid.Nil {
+		return status, errNoID
+	}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/webhooks/deliveries/" + id.String()}, &status)
+	return status, err
+}
+
+// ReplayDelivery queues a failed delivery for delivery again
+func (c *Client) ReplayDelivery(ctx context.Context, id uuid.UUID) (ReplayResponse, error) {
+	var resp ReplayResponse
+	if id == uuid.Nil {
+		return resp, errNoID
+	}
+	err := c.do(ctx, request{method: http.MethodPost, path: "/webhooks/deliveries/" + id.String() + "/replay"}, &resp)
+	return resp, err
+}
+
+// ReplayDeliveries queues the failed deliveries matching a filter for delivery again
+func (c *Client) ReplayDeliveries(ctx context.Context, filter ReplayRequest) (ReplayResponse, error) {
+	var resp ReplayResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: "/webhooks/deliveries/replay", body: filter}, &resp)
+	return resp, err
+}
+
+// ListScheduledDeliveries lists the deliveries waiting for their scheduled time, the
+// next to fire first. uuid.Nil lists those of all subscriptions, a zero
+// limit lists up to 100.
+func (c *Client) ListScheduledDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]WebhookDelivery, error) {
+	query := url.Values{}
+	if subscriptionID != uuid.Nil {
+		query.Set("subscription_id", subscriptionID.String())
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	path := "/webhooks/deliveries/scheduled"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var deliveries []WebhookDelivery
+	err := c.do(ctx, request{method: http.MethodGet, path: path}, &deliveries)
+	return deliveries, err
+}
+
+// CancelDelivery cancels a scheduled delivery before it fires. A delivery that is no
+// longer scheduled returns an error matching ErrConflict.
+func (c *Client) CancelDelivery(ctx context.Context, id uuid.UUID) (WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	if id == uuid.Nil {
+		return delivery, errNoID
+	}
+	err := c.do(ctx, request{method: http.MethodPost, path: "/webhooks/deliveries/" + id.String() + "/cancel"}, &delivery)
+	return delivery, err
+}
+
+// WebhookKeys gets the public keys deliveries of the ed25519 scheme are signed with
+func (c *Client) WebhookKeys(ctx context.Context) (signature.JWKS, error) {
+	var keys signature.JWKS
+	err := c.do(ctx, request{method: http.MethodGet, path: "/.well-known/webhook-keys"}, &keys)
+	return keys, err
+}
+
+// DeliveryStream is a stream of delivery events, read like a bufio.Scanner:
+//
+//	for stream.Next() {
+//		event := stream.Event()
+//	}
+//	err := stream.Err()
+type DeliveryStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	event   DeliveryEvent
+	err     error
+}
+
+// StreamDeliveries streams the delivery state changes and attempt results of a
+// subscription until ctx is done or the stream is closed. The stream is not bound by
+// the HTTP client's timeout.
+func (c *Client) StreamDeliveries(ctx context.Context, subscriptionID uuid.UUID) (*DeliveryStream, error) {
+	path, err := subscriptionPath(subscriptionID, "/deliveries/stream")
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.openStream(ctx, path, "text/event-stream")
+	if err != nil {
+		return nil, err
+	}
+	return &DeliveryStream{body: body, scanner: bufio.NewScanner(body)}, nil
+}
+
+// openStream sends a GET request for a streamed response and returns its body. The
+// request is not bound by the HTTP client's timeout and is not retried.
+func (c *Client) openStream(ctx context.Context, path, accept string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", accept)
+
+	streamClient := &http.Client{
+		Transport:     c.http.Transport,
+		CheckRedirect: c.http.CheckRedirect,
+		Jar:           c.http.Jar,
+	}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp.Body, nil
+}
+
+// Next reads the next event. It returns false when the stream ends.
+func (s *DeliveryStream) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	var data strings.Builder
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case line == "" && data.Len() > 0:
+			s.event = DeliveryEvent{}
+			if err := json.Unmarshal([]byte(data.String()), &s.event); err != nil {
+				s.err = fmt.Errorf("client: malformed delivery event: %w", err)
+				return false
+			}
+			return true
+		}
+	}
+	s.err = s.scanner.Err()
+	return false
+}
+
+// Event returns the event read by Next
+func (s *DeliveryStream) Event() DeliveryEvent {
+	return s.event
+}
+
+// Err returns the error that ended the stream, nil when it ended normally
+func (s *DeliveryStream) Err() error {
+	return s.err
+}
+
+// Close closes the stream
+func (s *DeliveryStream) Close() error {
+	return s.body.Close()
+}
+
+// ArchiveStream is a stream of archived deliveries, read like a DeliveryStream
+type ArchiveStream struct {
+	body     io.ReadCloser
+	decoder  *json.Decoder
+	delivery ArchivedDelivery
+	err      error
+}
+
+// RestoreArchive streams the deliveries archived for a day, in UTC, before the
+// retention policy removed them. A zero subscriptionID reads every subscription. A
+// day with nothing archived returns an error matching ErrNotFound.
+func (c *Client) RestoreArchive(ctx context.Context, day time.Time, subscriptionID uuid.UUID) (*ArchiveStream, error) {
+	path := "/webhooks/archive/" + day.UTC().Format("2006-01-02")
+	if subscriptionID != uuid.Nil {
+		path += "?" + url.Values{"subscription_id": {subscriptionID.String()}}.Encode()
+	}
+	body, err := c.openStream(ctx, path, "application/x-ndjson")
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveStream{body: body, decoder: json.NewDecoder(body)}, nil
+}
+
+// Next reads the next archived delivery. It returns false when the stream ends.
+func (s *ArchiveStream) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	s.delivery = ArchivedDelivery{}
+	if err := s.decoder.Decode(&s.delivery); err != nil {
+		if err != io.EOF {
+			s.err = fmt.Errorf("client: malformed archived delivery: %w", err)
+		}
+		return false
+	}
+	return true
+}
+
+// Delivery returns the archived delivery read by Next
+func (s *ArchiveStream) Delivery() ArchivedDelivery {
+	return s.delivery
+}
+
+// Err returns the error that ended the stream, nil when it ended normally
+func (s *ArchiveStream) Err() error {
+	return s.err
+}
+
+// Close closes the stream
+func (s *ArchiveStream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,45 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ed25519Version precedes each asymmetric signature in webhook-signature
+const ed25519Version = "v1a,"
+
+// Ed25519 returns the webhook-signature value of a message signed with an Ed25519
+// private key. The signed content is the same as for Standard.
+func Ed25519(key ed25519.PrivateKey, id string, timestamp time.Time, body []byte) string {
+	msg := make([]byte, 0, len(id)+len(body)+22)
+	msg = append(msg, id+"."+strconv.FormatInt(timestamp.Unix(), 10)+"."...)
+	msg = append(msg, body...)
+	return ed25519Version + base64.StdEncoding.EncodeToString(ed25519.Sign(key, msg))
+}
+
+// VerifyEd25519 checks the Standard Webhooks headers of a message signed with Ed25519
+// against the body. The timestamp must be within tolerance of now, DefaultTolerance
+// when tolerance is zero. Any of the v1a signatures may match any of the public keys,
+// so keys published during a rotation can all be passed.
+func VerifyEd25519(h http.Header, body []byte, now time.Time, tolerance time.Duration, keys ...ed25519.PublicKey) error {
+	msg, err := parseStandard(h, now, tolerance, ed25519Version)
+	if err != nil {
+		return err
+	}
+
+	signed := append(msg.prefix(), body...)
+	for _, key := range keys {
+		if len(key) != ed25519.PublicKeySize {
+			continue
+		}
+		for _, sig := range msg.signatures {
+			if ed25519.Verify(key, signed, sig) {
+				return nil
+			}
+		}
+	}
+	return ErrNoMatch
+}
@@ -0,0 +1,112 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// The key of test 1 in RFC 8032, section 7.1
+const ed25519Seed = "nWGxne/9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A="
+
+// ed25519Signature is the signature of the Standard Webhooks example message with
+// the RFC 8032 key
+const ed25519Signature = "v1a,fldxM4gAKugP6nnt1hdz3sgGfZ6d99nzrMFnZOELIxbzEHoVmAb2ADpkJK7zgPePmPsle0zV9jSeGlHFG2NVAw=="
+
+func testEd25519Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	seed, err := base64.StdEncoding.DecodeString(ed25519Seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+func TestEd25519(t *testing.T) {
+	key := testEd25519Key(t)
+	got := Ed25519(key, standardID, time.Unix(standardUnix, 0), []byte(standardBody))
+	if got != ed25519Signature {
+		t.Fatalf("Ed25519() = %q, want %q", got, ed25519Signature)
+	}
+}
+
+func TestVerifyEd25519(t *testing.T) {
+	current := testEd25519Key(t)
+	_, previous, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(standardUnix, 0)
+	body := []byte(standardBody)
+	previousSignature := Ed25519(previous, standardID, now, body)
+	publicKey := func(key ed25519.PrivateKey) ed25519.PublicKey { return key.Public().(ed25519.PublicKey) }
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		body    []byte
+		now     time.Time
+		keys    []ed25519.PublicKey
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			header: standardHeaders(standardID, standardUnix, ed25519Signature),
+			body:   body, now: now, keys: []ed25519.PublicKey{publicKey(current)},
+		},
+		{
+			// During a rotation deliveries carry a signature per key, consumers that
+			// only know the previous key still verify
+			name:   "signed with both keys, verified with the previous one",
+			header: standardHeaders(standardID, standardUnix, ed25519Signature+" "+previousSignature),
+			body:   body, now: now, keys: []ed25519.PublicKey{publicKey(previous)},
+		},
+		{
+			name:   "signed with the current key, verified with the published set",
+			header: standardHeaders(standardID, standardUnix, ed25519Signature),
+			body:   body, now: now, keys: []ed25519.PublicKey{publicKey(previous), publicKey(current)},
+		},
+		{
+			name:   "hmac signatures are ignored",
+			header: standardHeaders(standardID, standardUnix, standardSignature),
+			body:   body, now: now, keys: []ed25519.PublicKey{publicKey(current)},
+			wantErr: ErrNoSignature,
+		},
+		{
+			name:   "retired key",
+			header: standardHeaders(standardID, standardUnix, ed25519Signature),
+			body:   body, now: now, keys: []ed25519.PublicKey{publicKey(previous)},
+			wantErr: ErrNoMatch,
+		},
+		{
+			name:   "modified body",
+			header: standardHeaders(standardID, standardUnix, ed25519Signature),
+			body:   []byte(`{"test": 1}`), now: now, keys: []ed25519.PublicKey{publicKey(current)},
+			wantErr: ErrNoMatch,
+		},
+		{
+			name:   "invalid public key",
+			header: standardHeaders(standardID, standardUnix, ed25519Signature),
+			body:   body, now: now, keys: []ed25519.PublicKey{publicKey(current)[:16]},
+			wantErr: ErrNoMatch,
+		},
+		{
+			name:   "outside the tolerance",
+			header: standardHeaders(standardID, standardUnix, ed25519Signature),
+			body:   body, now: now.Add(time.Hour), keys: []ed25519.PublicKey{publicKey(current)},
+			wantErr: ErrTimestampOutOfTolerance,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyEd25519(tt.header, tt.body, tt.now, 0, tt.keys...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyEd25519() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
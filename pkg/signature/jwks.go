@@ -0,0 +1,75 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+)
+
+// Key statuses in a JWKS
+const (
+	// KeyStatusCurrent marks the active signing key
+	KeyStatusCurrent = "current"
+	// KeyStatusPrevious marks a key being rotated out, deliveries are still signed
+	// with it until it is removed
+	KeyStatusPrevious = "previous"
+)
+
+// JWK is an Ed25519 public key in JSON Web Key form (RFC 8037). Status is not part of
+// the standard, it tells whether the key is current or being rotated out.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	KeyID     string `json:"kid"`
+	X         string `json:"x"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set, as published at /.well-known/webhook-keys
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK returns the JWK of an Ed25519 public key
+func NewJWK(keyID string, key ed25519.PublicKey, status string) JWK {
+	return JWK{
+		KeyType:   "OKP",
+		Curve:     "Ed25519",
+		KeyID:     keyID,
+		X:         base64.RawURLEncoding.EncodeToString(key),
+		Use:       "sig",
+		Algorithm: "EdDSA",
+		Status:    status,
+	}
+}
+
+// PublicKey decodes the Ed25519 public key of a JWK
+func (k JWK) PublicKey() (ed25519.PublicKey, error) {
+	if k.KeyType != "OKP" || k.Curve != "Ed25519" {
+		return nil, fmt.Errorf("signature: key %q is not an Ed25519 key", k.KeyID)
+	}
+	key, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("signature: key %q has an invalid public key", k.KeyID)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// PublicKeys decodes the Ed25519 public keys of a JWKS. Keys of other types are
+// skipped.
+func (s JWKS) PublicKeys() ([]ed25519.PublicKey, error) {
+	keys := make([]ed25519.PublicKey, 0, len(s.Keys))
+	for _, k := range s.Keys {
+		if k.KeyType != "OKP" || k.Curve != "Ed25519" {
+			continue
+		}
+		key, err := k.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,97 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+)
+
+// The public key of the example in RFC 8037, appendix A.2, which uses the RFC 8032
+// test key
+const ed25519X = "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+
+func TestNewJWK(t *testing.T) {
+	key := testEd25519Key(t).Public().(ed25519.PublicKey)
+	jwk := NewJWK("2026-10", key, KeyStatusCurrent)
+
+	got, err := json.Marshal(jwk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"kty":"OKP","crv":"Ed25519","kid":"2026-10","x":"` + ed25519X + `","use":"sig","alg":"EdDSA","status":"current"}`
+	if string(got) != want {
+		t.Fatalf("NewJWK() = %s, want %s", got, want)
+	}
+
+	decoded, err := jwk.PublicKey()
+	if err != nil {
+		t.Fatalf("PublicKey() error = %v", err)
+	}
+	if !bytes.Equal(decoded, key) {
+		t.Fatal("PublicKey() differs from the encoded key")
+	}
+}
+
+func TestJWKSPublicKeys(t *testing.T) {
+	current := testEd25519Key(t).Public().(ed25519.PublicKey)
+	previous, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		keys     []JWK
+		wantKeys int
+		wantErr  bool
+	}{
+		{
+			name:     "current and previous key",
+			keys:     []JWK{NewJWK("b", current, KeyStatusCurrent), NewJWK("a", previous, KeyStatusPrevious)},
+			wantKeys: 2,
+		},
+		{
+			name:     "other key types are skipped",
+			keys:     []JWK{{KeyType: "RSA", KeyID: "rsa"}, NewJWK("b", current, KeyStatusCurrent)},
+			wantKeys: 1,
+		},
+		{name: "empty set", keys: []JWK{}, wantKeys: 0},
+		{
+			name:    "truncated key",
+			keys:    []JWK{{KeyType: "OKP", Curve: "Ed25519", KeyID: "b", X: ed25519X[:20]}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid encoding",
+			keys:    []JWK{{KeyType: "OKP", Curve: "Ed25519", KeyID: "b", X: "not+base64url="}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Consumers decode the set as published
+			encoded, err := json.Marshal(JWKS{Keys: tt.keys})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var jwks JWKS
+			if err := json.Unmarshal(encoded, &jwks); err != nil {
+				t.Fatal(err)
+			}
+
+			keys, err := jwks.PublicKeys()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PublicKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(keys) != tt.wantKeys {
+				t.Fatalf("PublicKeys() returned %d keys, want %d", len(keys), tt.wantKeys)
+			}
+		})
+	}
+
+	if _, err := (JWK{KeyType: "OKP", Curve: "X25519", KeyID: "x"}).PublicKey(); err == nil {
+		t.Fatal("PublicKey() of an X25519 key succeeded, want an error")
+	}
+}
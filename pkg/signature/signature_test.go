@@ -0,0 +1,73 @@
+package signature
+
+import (
+	"errors"
+	"testing"
+)
+
+// The example from GitHub's webhook documentation
+const (
+	hubSecret    = "It's a Secret to Everybody"
+	hubBody      = "Hello, World!"
+	hubSignature = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+)
+
+func TestHub(t *testing.T) {
+	if got := Hub(hubSecret, []byte(hubBody)); got != hubSignature {
+		t.Fatalf("Hub() = %q, want %q", got, hubSignature)
+	}
+
+	// Writing the body in pieces gives the same signature
+	s := NewHubSigner(hubSecret)
+	s.Write([]byte(hubBody[:5]))
+	s.Write([]byte(hubBody[5:]))
+	if got := s.Signature(); got != hubSignature {
+		t.Fatalf("Signature() = %q, want %q", got, hubSignature)
+	}
+}
+
+func TestVerifyHub(t *testing.T) {
+	body := []byte(hubBody)
+	other := Hub("old secret", body)
+
+	tests := []struct {
+		name    string
+		header  string
+		body    string
+		secrets []string
+		wantErr error
+	}{
+		{name: "valid", header: hubSignature, body: hubBody, secrets: []string{hubSecret}},
+		{name: "old and new secret during rotation", header: other + " " + hubSignature, body: hubBody, secrets: []string{hubSecret}},
+		{name: "consumer holds both secrets", header: hubSignature, body: hubBody, secrets: []string{"old secret", hubSecret}},
+		{name: "wrong secret", header: hubSignature, body: hubBody, secrets: []string{"wrong"}, wantErr: ErrNoMatch},
+		{name: "modified body", header: hubSignature, body: "Hello, World?", secrets: []string{hubSecret}, wantErr: ErrNoMatch},
+		{name: "uppercase hex", header: "sha256=757107EA0EB2509FC211221CCE984B8A37570B6D7586C22C46F4379C8B043E17", body: hubBody, secrets: []string{hubSecret}, wantErr: ErrNoMatch},
+		{name: "no signature", header: "", body: hubBody, secrets: []string{hubSecret}, wantErr: ErrNoSignature},
+		{name: "no secrets", header: hubSignature, body: hubBody, wantErr: ErrNoMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyHub(tt.header, []byte(tt.body), tt.secrets...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyHub() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	signers := []Signer{NewHubSigner(hubSecret), NewHubSigner("old secret")}
+	for _, s := range signers {
+		s.Write([]byte(hubBody))
+	}
+
+	want := hubSignature + " " + Hub("old secret", []byte(hubBody))
+	if got := Join(signers); got != want {
+		t.Fatalf("Join() = %q, want %q", got, want)
+	}
+	if err := VerifyHub(Join(signers), []byte(hubBody), "old secret"); err != nil {
+		t.Fatalf("VerifyHub() with the old secret error = %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Standard Webhooks headers
+const (
+	IDHeader        = "webhook-id"
+	TimestampHeader = "webhook-timestamp"
+	SignatureHeader = "webhook-signature"
+)
+
+// SecretPrefix precedes the base64 encoded key of a Standard Webhooks secret
+const SecretPrefix = "whsec_"
+
+// standardVersion precedes each signature in webhook-signature
+const standardVersion = "v1,"
+
+// DefaultTolerance is how far a webhook-timestamp may be from the current time
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrMissingHeaders is returned when a Standard Webhooks header is missing
+	ErrMissingHeaders = errors.New("signature: missing webhook-id, webhook-timestamp or webhook-signature header")
+	// ErrInvalidTimestamp is returned when webhook-timestamp is not a Unix time
+	ErrInvalidTimestamp = errors.New("signature: invalid webhook-timestamp")
+	// ErrTimestampOutOfTolerance is returned when webhook-timestamp is too old or too
+	// far in the future, which usually means the message is replayed
+	ErrTimestampOutOfTolerance = errors.New("signature: webhook-timestamp outside the tolerance")
+)
+
+// StandardKey returns the HMAC key of a secret. Secrets of the form whsec_<base64> use
+// the decoded key as the specification requires, other secrets are used as they are.
+func StandardKey(secret string) []byte {
+	if encoded, ok := strings.CutPrefix(secret, SecretPrefix); ok {
+		if key, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+			return key
+		}
+	}
+	return []byte(secret)
+}
+
+// standardSigner is the Signer of the Standard Webhooks scheme
+type standardSigner struct {
+	hash.Hash
+}
+
+// NewStandardSigner returns a Signer for webhook-signature. The message ID and
+// timestamp are written first, so only the body remains to be written.
+func NewStandardSigner(secret string, id string, timestamp time.Time) Signer {
+	s := standardSigner{hmac.New(sha256.New, StandardKey(secret))}
+	s.Write([]byte(id + "." + strconv.FormatInt(timestamp.Unix(), 10) + "."))
+	return s
+}
+
+func (s standardSigner) Signature() string {
+	return standardVersion + base64.StdEncoding.EncodeToString(s.Sum(nil))
+}
+
+// Standard returns the webhook-signature value of a message
+func Standard(secret string, id string, timestamp time.Time, body []byte) string {
+	s := NewStandardSigner(secret, id, timestamp)
+	s.Write(body)
+	return s.Signature()
+}
+
+// VerifyStandard checks the Standard Webhooks headers against the body. The timestamp
+// must be within tolerance of now, DefaultTolerance when tolerance is zero. Any of the
+// v1 signatures may match any of the secrets, other versions are ignored.
+func VerifyStandard(h http.Header, body []byte, now time.Time, tolerance time.Duration, secrets ...string) error {
+	msg, err := parseStandard(h, now, tolerance, standardVersion)
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, StandardKey(secret))
+		mac.Write(msg.prefix())
+		mac.Write(body)
+		expected := mac.Sum(nil)
+		for _, sig := range msg.signatures {
+			if hmac.Equal(sig, expected) {
+				return nil
+			}
+		}
+	}
+	return ErrNoMatch
+}
+
+// standardMessage holds the parsed Standard Webhooks headers of a message
+type standardMessage struct {
+	id         string
+	timestamp  string
+	signatures [][]byte
+}
+
+// prefix returns what is signed before the body
+func (m standardMessage) prefix() []byte {
+	return []byte(m.id + "." + m.timestamp + ".")
+}
+
+// parseStandard parses the Standard Webhooks headers, checks the timestamp and
+// decodes the signatures of the given version
+func parseStandard(h http.Header, now time.Time, tolerance time.Duration, version string) (standardMessage, error) {
+	msg := standardMessage{id: h.Get(IDHeader), timestamp: h.Get(TimestampHeader)}
+	header := h.Get(SignatureHeader)
+	if msg.id == "" || msg.timestamp == "" || header == "" {
+		return msg, ErrMissingHeaders
+	}
+
+	unix, err := strconv.ParseInt(msg.timestamp, 10, 64)
+	if err != nil {
+		return msg, ErrInvalidTimestamp
+	}
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+	timestamp := time.Unix(unix, 0)
+	if timestamp.Before(now.Add(-tolerance)) || timestamp.After(now.Add(tolerance)) {
+		return msg, ErrTimestampOutOfTolerance
+	}
+
+	for _, sig := range strings.Fields(header) {
+		if encoded, ok := strings.CutPrefix(sig, version); ok {
+			if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+				msg.signatures = append(msg.signatures, decoded)
+			}
+		}
+	}
+	if len(msg.signatures) == 0 {
+		return msg, ErrNoSignature
+	}
+	return msg, nil
+}
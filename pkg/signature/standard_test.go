@@ -0,0 +1,148 @@
+package signature
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// The example from the Standard Webhooks specification
+const (
+	standardSecret    = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	standardID        = "msg_p5jXN8AQM9LWM0D4loKWxJek"
+	standardUnix      = 1614265330
+	standardBody      = `{"test": 2432232314}`
+	standardSignature = "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE="
+)
+
+// standardHeaders returns the Standard Webhooks headers of a message
+func standardHeaders(id string, unix int64, signature string) http.Header {
+	h := http.Header{}
+	h.Set(IDHeader, id)
+	h.Set(TimestampHeader, strconv.FormatInt(unix, 10))
+	h.Set(SignatureHeader, signature)
+	return h
+}
+
+// withHeader returns h with a header replaced
+func withHeader(h http.Header, key, value string) http.Header {
+	h.Set(key, value)
+	return h
+}
+
+func TestStandard(t *testing.T) {
+	timestamp := time.Unix(standardUnix, 0)
+	if got := Standard(standardSecret, standardID, timestamp, []byte(standardBody)); got != standardSignature {
+		t.Fatalf("Standard() = %q, want %q", got, standardSignature)
+	}
+
+	// Only secrets of the form whsec_<base64> are decoded
+	tests := []struct {
+		secret string
+		want   string
+	}{
+		{secret: "whsec_aGVsbG8=", want: "hello"},
+		{secret: "whsec_not base64!", want: "whsec_not base64!"},
+		{secret: "plain secret", want: "plain secret"},
+	}
+	for _, tt := range tests {
+		if got := string(StandardKey(tt.secret)); got != tt.want {
+			t.Errorf("StandardKey(%q) = %q, want %q", tt.secret, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyStandard(t *testing.T) {
+	now := time.Unix(standardUnix, 0)
+	rotated := Standard("whsec_b2xkIHNlY3JldA==", standardID, now, []byte(standardBody))
+
+	tests := []struct {
+		name      string
+		header    http.Header
+		body      string
+		now       time.Time
+		tolerance time.Duration
+		secrets   []string
+		wantErr   error
+	}{
+		{
+			name:   "valid",
+			header: standardHeaders(standardID, standardUnix, standardSignature),
+			body:   standardBody, now: now, secrets: []string{standardSecret},
+		},
+		{
+			name:   "old and new secret during rotation",
+			header: standardHeaders(standardID, standardUnix, rotated+" "+standardSignature),
+			body:   standardBody, now: now, secrets: []string{standardSecret},
+		},
+		{
+			name:   "other versions are ignored",
+			header: standardHeaders(standardID, standardUnix, "v1a,c2lnbmF0dXJl "+standardSignature),
+			body:   standardBody, now: now, secrets: []string{standardSecret},
+		},
+		{
+			name:   "within the default tolerance",
+			header: standardHeaders(standardID, standardUnix, standardSignature),
+			body:   standardBody, now: now.Add(DefaultTolerance), secrets: []string{standardSecret},
+		},
+		{
+			name:   "too old",
+			header: standardHeaders(standardID, standardUnix, standardSignature),
+			body:   standardBody, now: now.Add(DefaultTolerance + time.Second), secrets: []string{standardSecret},
+			wantErr: ErrTimestampOutOfTolerance,
+		},
+		{
+			name:   "too far in the future",
+			header: standardHeaders(standardID, standardUnix, standardSignature),
+			body:   standardBody, now: now.Add(-2 * time.Minute), tolerance: time.Minute, secrets: []string{standardSecret},
+			wantErr: ErrTimestampOutOfTolerance,
+		},
+		{
+			name:   "replayed with a new timestamp",
+			header: standardHeaders(standardID, standardUnix+60, standardSignature),
+			body:   standardBody, now: now, secrets: []string{standardSecret},
+			wantErr: ErrNoMatch,
+		},
+		{
+			name:   "other message id",
+			header: standardHeaders("msg_other", standardUnix, standardSignature),
+			body:   standardBody, now: now, secrets: []string{standardSecret},
+			wantErr: ErrNoMatch,
+		},
+		{
+			name:   "modified body",
+			header: standardHeaders(standardID, standardUnix, standardSignature),
+			body:   `{"test": 2432232315}`, now: now, secrets: []string{standardSecret},
+			wantErr: ErrNoMatch,
+		},
+		{
+			name:   "invalid timestamp",
+			header: withHeader(standardHeaders(standardID, standardUnix, standardSignature), TimestampHeader, "yesterday"),
+			body:   standardBody, now: now, secrets: []string{standardSecret},
+			wantErr: ErrInvalidTimestamp,
+		},
+		{
+			name:   "missing id",
+			header: standardHeaders("", standardUnix, standardSignature),
+			body:   standardBody, now: now, secrets: []string{standardSecret},
+			wantErr: ErrMissingHeaders,
+		},
+		{
+			name:   "only other versions",
+			header: standardHeaders(standardID, standardUnix, "v2,c2lnbmF0dXJl"),
+			body:   standardBody, now: now, secrets: []string{standardSecret},
+			wantErr: ErrNoSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyStandard(tt.header, []byte(tt.body), tt.now, tt.tolerance, tt.secrets...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyStandard() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// errReadBody is returned when the request body cannot be read
+var errReadBody = errors.New("webhook: failed to read body")
+
+type contextKey struct{}
+
+// EventFromContext returns the event the middleware verified
+func EventFromContext(ctx context.Context) (*Event, bool) {
+	event, ok := ctx.Value(contextKey{}).(*Event)
+	return event, ok
+}
+
+// NewContext returns a context carrying the event
+func NewContext(ctx context.Context, event *Event) context.Context {
+	return context.WithValue(ctx, contextKey{}, event)
+}
+
+// Middleware verifies deliveries before passing them to next. The event is available
+// from EventFromContext and the body can be read again. Rejected requests are answered
+// by Options.ErrorHandler.
+//
+// With a Store, a delivery whose ID was already handled is answered with 200 without
+// calling next, so the service stops retrying it. When next does not respond with a
+// 2xx status, or panics, the ID is released so the retry is handled.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, v.opts.MaxBodySize+1))
+		if err != nil {
+			v.opts.ErrorHandler(w, r, errReadBody)
+			return
+		}
+		if int64(len(body)) > v.opts.MaxBodySize {
+			v.opts.ErrorHandler(w, r, ErrBodyTooLarge)
+			return
+		}
+
+		event, err := v.Verify(r.Header, body)
+		if err != nil {
+			v.opts.ErrorHandler(w, r, err)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r = r.WithContext(NewContext(r.Context(), event))
+
+		if v.opts.Store == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if event.ID == "" {
+			v.opts.ErrorHandler(w, r, ErrMissingID)
+			return
+		}
+
+		claimed, err := v.opts.Store.Claim(r.Context(), event.ID, v.opts.DedupWindow)
+		if err != nil {
+			http.Error(w, "failed to check for duplicate delivery", http.StatusServiceUnavailable)
+			return
+		}
+		if !claimed {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		rec := &statusRecorder{ResponseWriter: w}
+		handled := false
+		defer func() {
+			if !handled || rec.status < 200 || rec.status > 299 {
+				// Use a fresh context, the request's may already be canceled
+				_ = v.opts.Store.Release(context.WithoutCancel(r.Context()), event.ID)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		handled = true
+	})
+}
+
+// statusRecorder records the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying ResponseWriter
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Unic-X/webhook-delivery/pkg/signature"
+)
+
+const (
+	testSecret = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	testBody   = `{"order":1}`
+)
+
+// signedRequest returns a delivery signed with the standard_webhooks scheme at
+// timestamp
+func signedRequest(id string, timestamp time.Time, secret string, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	r.Header.Set(IDHeader, id)
+	r.Header.Set(EventHeader, "order.created")
+	r.Header.Set(signature.IDHeader, id)
+	r.Header.Set(signature.TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	r.Header.Set(signature.SignatureHeader, signature.Standard(secret, id, timestamp, []byte(body)))
+	return r
+}
+
+// recordingHandler records the events and bodies it receives and responds with status
+type recordingHandler struct {
+	status int
+	events []*Event
+	bodies []string
+}
+
+func (h *recordingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	event, _ := EventFromContext(r.Context())
+	body, _ := io.ReadAll(r.Body)
+	h.events = append(h.events, event)
+	h.bodies = append(h.bodies, string(body))
+	w.WriteHeader(h.status)
+}
+
+func TestMiddlewareTimestampTolerance(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		signedAt   time.Time
+		tolerance  time.Duration
+		wantStatus int
+	}{
+		{name: "now", signedAt: now, wantStatus: http.StatusNoContent},
+		{name: "at the default tolerance", signedAt: now.Add(-signature.DefaultTolerance), wantStatus: http.StatusNoContent},
+		{name: "past the default tolerance", signedAt: now.Add(-signature.DefaultTolerance - time.Second), wantStatus: http.StatusUnauthorized},
+		{name: "replayed an hour later", signedAt: now.Add(-time.Hour), wantStatus: http.StatusUnauthorized},
+		{name: "clock skew within tolerance", signedAt: now.Add(time.Minute), wantStatus: http.StatusNoContent},
+		{name: "too far in the future", signedAt: now.Add(signature.DefaultTolerance + time.Second), wantStatus: http.StatusUnauthorized},
+		{name: "within a custom tolerance", signedAt: now.Add(-20 * time.Minute), tolerance: 30 * time.Minute, wantStatus: http.StatusNoContent},
+		{name: "past a custom tolerance", signedAt: now.Add(-90 * time.Second), tolerance: time.Minute, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, err := NewVerifier(Options{
+				Secrets:   []string{testSecret},
+				Tolerance: tt.tolerance,
+				Now:       func() time.Time { return now },
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := &recordingHandler{status: http.StatusNoContent}
+
+			w := httptest.NewRecorder()
+			verifier.Middleware(next).ServeHTTP(w, signedRequest("msg_1", tt.signedAt, testSecret, testBody))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if handled := len(next.events) == 1; handled != (tt.wantStatus == http.StatusNoContent) {
+				t.Fatalf("handler called %d times", len(next.events))
+			}
+			if tt.wantStatus == http.StatusUnauthorized && !strings.Contains(w.Body.String(), signature.ErrTimestampOutOfTolerance.Error()) {
+				t.Fatalf("response = %q, want the tolerance error", w.Body)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	now := time.Now()
+	_, signingKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hub := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(testBody))
+	hub.Header.Set(IDHeader, "msg_hub")
+	hub.Header.Set(signature.HubHeader, signature.Hub(testSecret, []byte(testBody)))
+
+	signed := signedRequest("msg_ed25519", now, testSecret, testBody)
+	signed.Header.Set(signature.SignatureHeader, signature.Ed25519(signingKey, "msg_ed25519", now, []byte(testBody)))
+
+	tests := []struct {
+		name       string
+		opts       Options
+		request    *http.Request
+		wantStatus int
+		wantID     string
+	}{
+		{
+			name:       "standard webhooks",
+			opts:       Options{Secrets: []string{testSecret}},
+			request:    signedRequest("msg_1", now, testSecret, testBody),
+			wantStatus: http.StatusOK, wantID: "msg_1",
+		},
+		{
+			name:       "rotated secret",
+			opts:       Options{Secrets: []string{"whsec_bmV3IHNlY3JldA==", testSecret}},
+			request:    signedRequest("msg_1", now, testSecret, testBody),
+			wantStatus: http.StatusOK, wantID: "msg_1",
+		},
+		{
+			name:       "github scheme",
+			opts:       Options{Secrets: []string{testSecret}},
+			request:    hub,
+			wantStatus: http.StatusOK, wantID: "msg_hub",
+		},
+		{
+			name:       "github scheme without a timestamp when one is required",
+			opts:       Options{Secrets: []string{testSecret}, RequireTimestamp: true},
+			request:    hub,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "ed25519",
+			opts:       Options{PublicKeys: []ed25519.PublicKey{signingKey.Public().(ed25519.PublicKey)}},
+			request:    signed,
+			wantStatus: http.StatusOK, wantID: "msg_ed25519",
+		},
+		{
+			name:       "wrong secret",
+			opts:       Options{Secrets: []string{"whsec_d3Jvbmc="}},
+			request:    signedRequest("msg_1", now, testSecret, testBody),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "body too large",
+			opts:       Options{Secrets: []string{testSecret}, MaxBodySize: 4},
+			request:    signedRequest("msg_1", now, testSecret, testBody),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Requests are read once, rebuild the body for each run
+			body, _ := io.ReadAll(tt.request.Body)
+			tt.request.Body = io.NopCloser(bytes.NewReader(body))
+
+			verifier, err := NewVerifier(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := &recordingHandler{status: http.StatusOK}
+
+			w := httptest.NewRecorder()
+			verifier.Middleware(next).ServeHTTP(w, tt.request)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if len(next.events) != 0 {
+					t.Fatal("rejected request reached the handler")
+				}
+				return
+			}
+
+			if len(next.events) != 1 || next.events[0] == nil {
+				t.Fatal("handler did not receive the event")
+			}
+			if next.events[0].ID != tt.wantID {
+				t.Errorf("event ID = %q, want %q", next.events[0].ID, tt.wantID)
+			}
+			if next.bodies[0] != testBody || string(next.events[0].Payload) != testBody {
+				t.Errorf("handler read body %q and payload %q, want %q", next.bodies[0], next.events[0].Payload, testBody)
+			}
+		})
+	}
+}
+
+func TestMiddlewareDeduplicates(t *testing.T) {
+	now := time.Now()
+	verifier, err := NewVerifier(Options{Secrets: []string{testSecret}, Store: NewMemoryStore()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := &recordingHandler{status: http.StatusInternalServerError}
+	handler := verifier.Middleware(next)
+
+	// A failed delivery is released so the retry is handled
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest("msg_1", now, testSecret, testBody))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("first attempt status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+
+	next.status = http.StatusOK
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest("msg_1", now.Add(time.Second), testSecret, testBody))
+	if w.Code != http.StatusOK || len(next.events) != 2 {
+		t.Fatalf("retry status = %d after %d calls, want it handled", w.Code, len(next.events))
+	}
+
+	// A duplicate of a handled delivery is acknowledged without calling the handler
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest("msg_1", now.Add(2*time.Second), testSecret, testBody))
+	if w.Code != http.StatusOK || len(next.events) != 2 {
+		t.Fatalf("duplicate status = %d after %d calls, want it acknowledged and skipped", w.Code, len(next.events))
+	}
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultDedupWindow is how long delivery IDs are remembered when
+// Options.DedupWindow is not set. It should outlast the service's retry schedule.
+const DefaultDedupWindow = 24 * time.Hour
+
+// Store remembers the IDs of handled deliveries. Implementations shared by several
+// replicas of a consumer, e.g. a Redis SET NX with an expiry, deduplicate across
+// all of them.
+type Store interface {
+	// Claim records the ID for ttl. It returns false if the ID is already recorded.
+	Claim(ctx context.Context, id string, ttl time.Duration) (bool, error)
+	// Release forgets the ID, so the next delivery with it is handled
+	Release(ctx context.Context, id string) error
+}
+
+// MemoryStore is a Store for a single process
+type MemoryStore struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+	now     func() time.Time
+	claims  int
+}
+
+// NewMemoryStore creates a new MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		expires: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// sweepEvery is how many claims pass between sweeps of expired IDs
+const sweepEvery = 1000
+
+// Claim implements Store
+func (s *MemoryStore) Claim(_ context.Context, id string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.claims++
+	if s.claims%sweepEvery == 0 {
+		for key, expires := range s.expires {
+			if !now.Before(expires) {
+				delete(s.expires, key)
+			}
+		}
+	}
+
+	if expires, ok := s.expires[id]; ok && now.Before(expires) {
+		return false, nil
+	}
+	s.expires[id] = now.Add(ttl)
+	return true, nil
+}
+
+// Release implements Store
+func (s *MemoryStore) Release(_ context.Context, id string) error {
+	s.mu.Lock()
+	delete(s.expires, id)
+	s.mu.Unlock()
+	return nil
+}
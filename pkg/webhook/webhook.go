@@ -0,0 +1,189 @@
+// Package webhook is for services that receive webhooks from the delivery service. It
+// verifies signatures of every scheme the service sends, checks timestamps to reject
+// replays, parses the delivery headers into an Event and drops duplicate deliveries.
+//
+//	verifier, err := webhook.NewVerifier(webhook.Options{
+//		Secrets: []string{os.Getenv("WEBHOOK_SECRET")},
+//		Store:   webhook.NewMemoryStore(),
+//	})
+//	...
+//	http.Handle("/webhooks", verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//		event, _ := webhook.EventFromContext(r.Context())
+//		...
+//	})))
+package webhook
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Unic-X/webhook-delivery/pkg/signature"
+)
+
+// Delivery headers set on every request
+const (
+	EventHeader = "X-Webhook-Event"
+	IDHeader    = "X-Webhook-ID"
+)
+
+// DefaultMaxBodySize is the largest body the middleware reads when Options.MaxBodySize
+// is not set
+const DefaultMaxBodySize = 10 << 20
+
+var (
+	// ErrNoVerificationKeys is returned by NewVerifier when neither secrets nor public
+	// keys are given
+	ErrNoVerificationKeys = errors.New("webhook: no secrets or public keys to verify with")
+	// ErrTimestampRequired is returned for deliveries without a webhook-timestamp when
+	// Options.RequireTimestamp is set
+	ErrTimestampRequired = errors.New("webhook: delivery has no webhook-timestamp")
+	// ErrMissingID is returned when deduplicating a delivery without an X-Webhook-ID
+	ErrMissingID = errors.New("webhook: delivery has no X-Webhook-ID")
+	// ErrBodyTooLarge is returned when the body is larger than Options.MaxBodySize
+	ErrBodyTooLarge = errors.New("webhook: body too large")
+)
+
+// Event is a verified delivery
+type Event struct {
+	// ID is the delivery ID. It is the same on every attempt of a delivery, so it
+	// identifies duplicates.
+	ID string
+	// Type is the event type, empty when the event was ingested without one
+	Type string
+	// Timestamp is when the attempt was signed, zero for the github scheme which
+	// sends no timestamp
+	Timestamp time.Time
+	// Payload is the raw JSON body
+	Payload json.RawMessage
+}
+
+// Decode unmarshals the payload into v
+func (e *Event) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// Options configures a Verifier
+type Options struct {
+	// Secrets verify the github and standard_webhooks schemes. Pass the old and the
+	// new secret during a secret rotation.
+	Secrets []string
+	// PublicKeys verify the ed25519 scheme, see signature.JWKS.PublicKeys
+	PublicKeys []ed25519.PublicKey
+	// Tolerance is how far webhook-timestamp may be from the current time,
+	// signature.DefaultTolerance when zero
+	Tolerance time.Duration
+	// RequireTimestamp rejects deliveries of the github scheme, which carry no
+	// timestamp and so cannot be checked for replays
+	RequireTimestamp bool
+	// MaxBodySize limits the body the middleware reads, DefaultMaxBodySize when zero
+	MaxBodySize int64
+	// Store deduplicates deliveries on their ID. Deduplication is off when nil.
+	Store Store
+	// DedupWindow is how long a delivery ID is remembered, DefaultDedupWindow when
+	// zero
+	DedupWindow time.Duration
+	// ErrorHandler writes the response of a rejected request, the default responds
+	// with StatusCode(err)
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+	// Now returns the current time, time.Now when nil
+	Now func() time.Time
+}
+
+// Verifier verifies deliveries
+type Verifier struct {
+	opts Options
+}
+
+// NewVerifier creates a new Verifier
+func NewVerifier(opts Options) (*Verifier, error) {
+	if len(opts.Secrets) == 0 && len(opts.PublicKeys) == 0 {
+		return nil, ErrNoVerificationKeys
+	}
+	if opts.Tolerance == 0 {
+		opts.Tolerance = signature.DefaultTolerance
+	}
+	if opts.MaxBodySize == 0 {
+		opts.MaxBodySize = DefaultMaxBodySize
+	}
+	if opts.DedupWindow == 0 {
+		opts.DedupWindow = DefaultDedupWindow
+	}
+	if opts.ErrorHandler == nil {
+		opts.ErrorHandler = defaultErrorHandler
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Verifier{opts: opts}, nil
+}
+
+// Verify checks the signature of a delivery and returns its event. Deliveries with
+// the Standard Webhooks headers are verified with the public keys when they carry
+// v1a signatures and with the secrets otherwise. Other deliveries are verified
+// against X-Hub-Signature-256. Signatures are compared in constant time.
+func (v *Verifier) Verify(h http.Header, body []byte) (*Event, error) {
+	event := &Event{
+		ID:      h.Get(IDHeader),
+		Type:    h.Get(EventHeader),
+		Payload: json.RawMessage(body),
+	}
+
+	if h.Get(signature.SignatureHeader) == "" {
+		if v.opts.RequireTimestamp {
+			return nil, ErrTimestampRequired
+		}
+		if err := signature.VerifyHub(h.Get(signature.HubHeader), body, v.opts.Secrets...); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+
+	if err := v.verifyStandard(h, body); err != nil {
+		return nil, err
+	}
+	// The timestamp was parsed by the verification above
+	unix, _ := strconv.ParseInt(h.Get(signature.TimestampHeader), 10, 64)
+	event.Timestamp = time.Unix(unix, 0)
+	if event.ID == "" {
+		event.ID = h.Get(signature.IDHeader)
+	}
+	return event, nil
+}
+
+// verifyStandard verifies the Standard Webhooks headers with the public keys, then
+// with the secrets
+func (v *Verifier) verifyStandard(h http.Header, body []byte) error {
+	now := v.opts.Now()
+	if len(v.opts.PublicKeys) > 0 {
+		err := signature.VerifyEd25519(h, body, now, v.opts.Tolerance, v.opts.PublicKeys...)
+		if !errors.Is(err, signature.ErrNoSignature) {
+			return err
+		}
+	}
+	if len(v.opts.Secrets) > 0 {
+		return signature.VerifyStandard(h, body, now, v.opts.Tolerance, v.opts.Secrets...)
+	}
+	return signature.ErrNoSignature
+}
+
+// StatusCode returns the status a rejected request is answered with: 413 for a body
+// that is too large, 400 for a malformed request and 401 for a signature that does
+// not verify
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrMissingID), errors.Is(err, errReadBody):
+		return http.StatusBadRequest
+	default:
+		return http.StatusUnauthorized
+	}
+}
+
+func defaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	http.Error(w, err.Error(), StatusCode(err))
+}